@@ -1,11 +1,33 @@
 package api
 
 import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/everstake/cosmoscan-api/config"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
 	"github.com/everstake/cosmoscan-api/log"
 	"github.com/gorilla/mux"
+	"github.com/shopspring/decimal"
 	"net/http"
+	"time"
 )
 
+const (
+	exportFormatCSV   = "csv"
+	exportFormatJSONL = "jsonl"
+
+	exportFlushEvery = 500
+)
+
+type exportRow struct {
+	dmodels.AccountEvent
+	Price     *decimal.Decimal `json:"price,omitempty"`
+	FiatValue *decimal.Decimal `json:"fiat_value,omitempty"`
+}
+
 func (api *API) GetAccount(w http.ResponseWriter, r *http.Request) {
 	address, ok := mux.Vars(r)["address"]
 	if !ok || address == "" {
@@ -15,8 +37,228 @@ func (api *API) GetAccount(w http.ResponseWriter, r *http.Request) {
 	resp, err := api.svc.GetAccount(address)
 	if err != nil {
 		log.Error("API GetAccount: svc.GetAccount: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) GetRichList(w http.ResponseWriter, r *http.Request) {
+	var filter filters.Accounts
+	err := api.queryDecoder.Decode(&filter, r.URL.Query())
+	if err != nil {
+		log.Debug("API GetRichList: Decode: %s", err.Error())
+		jsonBadRequest(w, "")
+		return
+	}
+	resp, err := api.svc.GetRichList(filter)
+	if err != nil {
+		log.Error("API GetRichList: svc.GetRichList: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) GetAccountsDistribution(w http.ResponseWriter, r *http.Request) {
+	var filter filters.AccountsDistribution
+	err := api.queryDecoder.Decode(&filter, r.URL.Query())
+	if err != nil {
+		log.Debug("API GetAccountsDistribution: Decode: %s", err.Error())
+		jsonBadRequest(w, "")
+		return
+	}
+	resp, err := api.svc.GetAccountsDistribution(filter)
+	if err != nil {
+		log.Error("API GetAccountsDistribution: svc.GetAccountsDistribution: %s", err.Error())
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
 }
+
+func (api *API) GetAggAccountsDistribution(w http.ResponseWriter, r *http.Request) {
+	var filter filters.AccountsDistributionAgg
+	err := api.queryDecoder.Decode(&filter, r.URL.Query())
+	if err != nil {
+		log.Debug("API GetAggAccountsDistribution: Decode: %s", err.Error())
+		jsonBadRequest(w, "")
+		return
+	}
+	err = filter.Validate()
+	if err != nil {
+		log.Debug("API GetAggAccountsDistribution: Validate: %s", err.Error())
+		jsonBadRequest(w, err.Error())
+		return
+	}
+	filter.Metric = mux.Vars(r)["metric"]
+	resp, err := api.svc.GetAggAccountsDistribution(filter)
+	if err != nil {
+		log.Error("API GetAggAccountsDistribution: svc.GetAggAccountsDistribution: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) GetAccountBalanceHistory(w http.ResponseWriter, r *http.Request) {
+	address, ok := mux.Vars(r)["address"]
+	if !ok || address == "" {
+		jsonBadRequest(w, "invalid address")
+		return
+	}
+	var filter filters.Agg
+	err := api.queryDecoder.Decode(&filter, r.URL.Query())
+	if err != nil {
+		log.Debug("API Decode: %s", err.Error())
+		jsonBadRequest(w, "")
+		return
+	}
+	err = filter.Validate()
+	if err != nil {
+		log.Debug("API Validate: %s", err.Error())
+		jsonBadRequest(w, err.Error())
+		return
+	}
+	resp, err := api.svc.GetAccountBalanceHistory(address, r.URL.Query().Get("currency"), filter)
+	if err != nil {
+		log.Error("API GetAccountBalanceHistory: svc.GetAccountBalanceHistory: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) GetAccountDelegations(w http.ResponseWriter, r *http.Request) {
+	address, ok := mux.Vars(r)["address"]
+	if !ok || address == "" {
+		jsonBadRequest(w, "invalid address")
+		return
+	}
+	resp, err := api.svc.GetAccountDelegations(address)
+	if err != nil {
+		log.Error("API GetAccountDelegations: svc.GetAccountDelegations: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) ExportAccount(w http.ResponseWriter, r *http.Request) {
+	address, ok := mux.Vars(r)["address"]
+	if !ok || address == "" {
+		jsonBadRequest(w, "invalid address")
+		return
+	}
+	if _, err := types.AccAddressFromBech32(address); err != nil {
+		jsonBadRequest(w, "invalid address")
+		return
+	}
+	var filter filters.AccountEvents
+	err := api.queryDecoder.Decode(&filter, r.URL.Query())
+	if err != nil {
+		log.Debug("API Decode: %s", err.Error())
+		jsonBadRequest(w, "")
+		return
+	}
+	filter.Address = address
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = exportFormatCSV
+	}
+	if format != exportFormatCSV && format != exportFormatJSONL {
+		jsonBadRequest(w, "invalid format")
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	extendWriteDeadline(r, api.server.WriteTimeout)
+	fileName := fmt.Sprintf("%s.%s", address, format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", fileName))
+
+	var rows uint64
+	var write func(event dmodels.AccountEvent) error
+	var cw *csv.Writer
+	csvHeader := []string{"time", "type", "tx_hash", "amount", "currency", "counterparty"}
+	if filter.Fiat {
+		csvHeader = append(csvHeader, "price", "fiat_value")
+	}
+	switch format {
+	case exportFormatCSV:
+		w.Header().Set("Content-Type", "text/csv")
+		cw = csv.NewWriter(w)
+		write = func(event dmodels.AccountEvent) error {
+			if rows == 0 {
+				if err := cw.Write(csvHeader); err != nil {
+					return err
+				}
+			}
+			record := []string{
+				event.CreatedAt.UTC().Format(time.RFC3339),
+				event.Type,
+				event.TxHash,
+				event.Amount.String(),
+				event.Currency,
+				event.Counterparty,
+			}
+			if filter.Fiat {
+				price, value := fiatValue(event)
+				record = append(record, price.String(), value.String())
+			}
+			return cw.Write(record)
+		}
+	case exportFormatJSONL:
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		encoder := json.NewEncoder(w)
+		write = func(event dmodels.AccountEvent) error {
+			row := exportRow{AccountEvent: event}
+			if filter.Fiat {
+				price, value := fiatValue(event)
+				row.Price, row.FiatValue = &price, &value
+			}
+			return encoder.Encode(row)
+		}
+	}
+
+	err = api.svc.ExportAccountEvents(filter, func(event dmodels.AccountEvent) error {
+		err := write(event)
+		if err != nil {
+			return err
+		}
+		rows++
+		if rows%exportFlushEvery == 0 {
+			extendWriteDeadline(r, api.server.WriteTimeout)
+			if cw != nil {
+				cw.Flush()
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("API ExportAccount: svc.ExportAccountEvents: %s", err.Error())
+		if rows == 0 {
+			w.Header().Del("Content-Disposition")
+			w.Header().Set("Content-Type", "application/json")
+			jsonError(w, err)
+		}
+		return
+	}
+	if cw != nil {
+		if rows == 0 {
+			_ = cw.Write(csvHeader)
+		}
+		extendWriteDeadline(r, api.server.WriteTimeout)
+		cw.Flush()
+	}
+}
+
+// fiatValue returns the price and the fiat value of the event, only atom amounts have a price
+func fiatValue(event dmodels.AccountEvent) (price decimal.Decimal, value decimal.Decimal) {
+	if event.Currency != config.Currency {
+		return decimal.Zero, decimal.Zero
+	}
+	return event.Price, event.Amount.Mul(event.Price).Truncate(2)
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/log"
+	"github.com/everstake/cosmoscan-api/services"
+	"github.com/everstake/cosmoscan-api/smodels"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strings"
+)
+
+func (api *API) GetAddressLabels(w http.ResponseWriter, r *http.Request) {
+	var filter filters.AddressLabels
+	err := api.queryDecoder.Decode(&filter, r.URL.Query())
+	if err != nil {
+		log.Debug("API GetAddressLabels: Decode: %s", err.Error())
+		jsonBadRequest(w, "")
+		return
+	}
+	resp, err := api.svc.GetAddressLabels(filter)
+	if err != nil {
+		log.Error("API GetAddressLabels: svc.GetAddressLabels: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) GetEntities(w http.ResponseWriter, r *http.Request) {
+	resp, err := api.svc.GetEntities()
+	if err != nil {
+		log.Error("API GetEntities: svc.GetEntities: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) SaveAddressLabel(w http.ResponseWriter, r *http.Request) {
+	var params smodels.AddressLabelParams
+	err := json.NewDecoder(r.Body).Decode(&params)
+	if err != nil {
+		log.Debug("API Decode: %s", err.Error())
+		jsonBadRequest(w, "invalid body")
+		return
+	}
+	params.Address = mux.Vars(r)["address"]
+	resp, err := api.svc.SaveAddressLabel(params)
+	if err != nil {
+		log.Error("API SaveAddressLabel: svc.SaveAddressLabel: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) DeleteAddressLabel(w http.ResponseWriter, r *http.Request) {
+	address, ok := mux.Vars(r)["address"]
+	if !ok || address == "" {
+		jsonBadRequest(w, "invalid address")
+		return
+	}
+	err := api.svc.DeleteAddressLabel(address)
+	if err != nil {
+		log.Error("API DeleteAddressLabel: svc.DeleteAddressLabel: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, map[string]bool{"deleted": true})
+}
+
+// ImportAddressLabels takes the format from the format param or the content type of the body
+func (api *API) ImportAddressLabels(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = services.LabelsFormatJSON
+		if strings.Contains(r.Header.Get("Content-Type"), "csv") {
+			format = services.LabelsFormatCSV
+		}
+	}
+	count, err := api.svc.ImportAddressLabels(format, r.Body)
+	if err != nil {
+		log.Error("API ImportAddressLabels: svc.ImportAddressLabels: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, map[string]uint64{"imported": count})
+}
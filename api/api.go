@@ -1,54 +1,108 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/everstake/cosmoscan-api/config"
 	"github.com/everstake/cosmoscan-api/dao"
+	"github.com/everstake/cosmoscan-api/dao/cache"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/everstake/cosmoscan-api/dmodels"
 	"github.com/everstake/cosmoscan-api/log"
 	"github.com/everstake/cosmoscan-api/services"
+	"github.com/everstake/cosmoscan-api/services/limiter"
 	"github.com/gorilla/mux"
 	"github.com/gorilla/schema"
 	"github.com/rs/cors"
+	"github.com/shopspring/decimal"
 	"github.com/urfave/negroni"
 	"go.uber.org/zap"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"reflect"
 	"strconv"
 	"time"
 )
 
+const (
+	// blockTime is about the time between blocks, responses depending on the latest block are cached for it
+	blockTime = time.Second * 6
+
+	defaultReadTimeout     = time.Second * 10
+	defaultWriteTimeout    = time.Minute * 2
+	defaultIdleTimeout     = time.Minute * 2
+	defaultShutdownTimeout = time.Second * 30
+)
+
 type API struct {
 	dao          dao.DAO
 	cfg          config.Config
 	svc          services.Services
 	router       *mux.Router
 	queryDecoder *schema.Decoder
+	limiter      *limiter.Limiter
+	server       *http.Server
 }
 
+// Stable codes of error responses
+const (
+	errCodeBadRequest          = "bad_request"
+	errCodeNotFound            = "not_found"
+	errCodeConflict            = "conflict"
+	errCodeServiceError        = "service_error"
+	errCodeUpstreamUnavailable = "upstream_unavailable"
+	errCodeTimeout             = "timeout"
+	errCodeUnauthorized        = "unauthorized"
+	errCodeTooManyRequests     = "too_many_requests"
+)
+
 type errResponse struct {
-	Error string `json:"error"`
-	Msg   string `json:"msg"`
+	Error     string `json:"error"`
+	Msg       string `json:"msg"`
+	RequestID string `json:"request_id,omitempty"`
 }
 
 func NewAPI(cfg config.Config, svc services.Services, dao dao.DAO) *API {
+	cache.Register(cachedResponse{})
 	sd := schema.NewDecoder()
 	sd.IgnoreUnknownKeys(true)
 	sd.RegisterConverter(dmodels.Time{}, func(s string) reflect.Value {
 		timestamp, err := strconv.ParseInt(s, 10, 64)
 		if err != nil {
-			return reflect.Value{}
+			// v2 clients may pass times in RFC3339
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return reflect.Value{}
+			}
+			return reflect.ValueOf(dmodels.NewTime(t))
 		}
 		t := dmodels.NewTime(time.Unix(timestamp, 0))
 		return reflect.ValueOf(t)
 	})
+	sd.RegisterConverter(decimal.Decimal{}, func(s string) reflect.Value {
+		d, err := decimal.NewFromString(s)
+		if err != nil {
+			return reflect.Value{}
+		}
+		return reflect.ValueOf(d)
+	})
 	return &API{
 		cfg:          cfg,
 		dao:          dao,
 		svc:          svc,
 		queryDecoder: sd,
+		limiter:      limiter.New(),
+		server: &http.Server{
+			Addr:              fmt.Sprintf(":%s", cfg.API.Port),
+			ReadHeaderTimeout: secondsOrDefault(cfg.API.ReadTimeout, defaultReadTimeout),
+			ReadTimeout:       secondsOrDefault(cfg.API.ReadTimeout, defaultReadTimeout),
+			WriteTimeout:      secondsOrDefault(cfg.API.WriteTimeout, defaultWriteTimeout),
+			IdleTimeout:       secondsOrDefault(cfg.API.IdleTimeout, defaultIdleTimeout),
+			ConnContext:       withConn,
+		},
 	}
 }
 
@@ -60,19 +114,58 @@ func (api *API) Run() error {
 	api.router = mux.NewRouter()
 	api.loadRoutes()
 
-	http.Handle("/", api.router)
+	api.server.Handler = api.router
 	log.Info("Listen API server on %s port", api.cfg.API.Port)
-	err := http.ListenAndServe(fmt.Sprintf(":%s", api.cfg.API.Port), nil)
-	if err != nil {
+	err := api.server.ListenAndServe()
+	if err != nil && err != http.ErrServerClosed {
 		return err
 	}
 	return nil
 }
 
+// Stop stops accepting new connections and waits for in-flight requests to finish
 func (api *API) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), api.ShutdownTimeout())
+	defer cancel()
+	err := api.server.Shutdown(ctx)
+	api.svc.FlushAPIKeysUsage()
+	if err != nil {
+		return fmt.Errorf("server.Shutdown: %w", err)
+	}
 	return nil
 }
 
+// ShutdownTimeout is the time given to in-flight requests to finish on stop
+func (api *API) ShutdownTimeout() time.Duration {
+	return secondsOrDefault(api.cfg.API.ShutdownTimeout, defaultShutdownTimeout)
+}
+
+// connContextKey keeps the connection of a request in its context, see extendWriteDeadline
+type connContextKey struct{}
+
+func withConn(ctx context.Context, c net.Conn) context.Context {
+	return context.WithValue(ctx, connContextKey{}, c)
+}
+
+// extendWriteDeadline gives a streamed response another write timeout from now,
+// so long streams are not cut by the server-wide WriteTimeout while they keep flushing
+func extendWriteDeadline(r *http.Request, timeout time.Duration) {
+	c, ok := r.Context().Value(connContextKey{}).(net.Conn)
+	if !ok || timeout <= 0 {
+		return
+	}
+	if err := c.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+		log.Debug("API extendWriteDeadline: SetWriteDeadline: %s", err.Error())
+	}
+}
+
+func secondsOrDefault(seconds uint64, d time.Duration) time.Duration {
+	if seconds == 0 {
+		return d
+	}
+	return time.Duration(seconds) * time.Second
+}
+
 func (api *API) loadRoutes() {
 
 	api.router = mux.NewRouter()
@@ -87,57 +180,135 @@ func (api *API) loadRoutes() {
 		AllowedOrigins:   api.cfg.API.AllowedHosts,
 		AllowCredentials: true,
 		AllowedMethods:   []string{"POST", "GET", "OPTIONS", "PUT", "DELETE"},
-		AllowedHeaders:   []string{"Accept", "Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "X-User-Env", "Sec-Fetch-Mode"},
+		AllowedHeaders:   []string{"Accept", "Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "X-User-Env", "Sec-Fetch-Mode", apiKeyHeader, requestIDHeader},
+		ExposedHeaders:   []string{requestIDHeader, "Retry-After", "ETag"},
 	}))
+	wrapper.UseFunc(requestID)
+
+	// public, the unversioned routes are the same as v1
+	HandleActions(api.router, wrapper, "", api.withCommonMiddleware(api.publicRoutes()))
+	HandleActions(api.router, wrapper, v1, api.withCommonMiddleware(api.publicRoutes()))
+	HandleActions(api.router, wrapper, v2, api.withCommonMiddleware(api.withV2Format(api.publicRoutes())))
 
-	// public
-	HandleActions(api.router, wrapper, "", []*Route{
+	// admin
+	HandleActions(api.router, wrapper, "/admin", []*Route{
+		{Path: "/api-keys", Method: http.MethodGet, Func: api.GetAPIKeys, Middleware: []negroni.HandlerFunc{api.adminAuth}},
+		{Path: "/api-keys", Method: http.MethodPost, Func: api.CreateAPIKey, Middleware: []negroni.HandlerFunc{api.adminAuth}},
+		{Path: "/api-keys/{id}", Method: http.MethodPut, Func: api.UpdateAPIKey, Middleware: []negroni.HandlerFunc{api.adminAuth}},
+		{Path: "/api-keys/{id}/usage", Method: http.MethodGet, Func: api.GetAPIKeyUsages, Middleware: []negroni.HandlerFunc{api.adminAuth}},
+		{Path: "/labels/import", Method: http.MethodPost, Func: api.ImportAddressLabels, Middleware: []negroni.HandlerFunc{api.adminAuth}},
+		{Path: "/labels/{address}", Method: http.MethodPut, Func: api.SaveAddressLabel, Middleware: []negroni.HandlerFunc{api.adminAuth}},
+		{Path: "/labels/{address}", Method: http.MethodDelete, Func: api.DeleteAddressLabel, Middleware: []negroni.HandlerFunc{api.adminAuth}},
+		{Path: "/gaps", Method: http.MethodGet, Func: api.GetDataGaps, Middleware: []negroni.HandlerFunc{api.adminAuth}},
+		{Path: "/gaps/repair", Method: http.MethodPost, Func: api.RepairDataGaps, Middleware: []negroni.HandlerFunc{api.adminAuth}},
+	})
+
+	// webhooks of the api key given in the X-API-Key header
+	HandleActions(api.router, wrapper, "/webhooks", api.withCommonMiddleware([]*Route{
+		{Path: "", Method: http.MethodGet, Func: api.GetWebhooks, Middleware: []negroni.HandlerFunc{api.apiKeyAuth}},
+		{Path: "", Method: http.MethodPost, Func: api.CreateWebhook, Middleware: []negroni.HandlerFunc{api.apiKeyAuth}},
+		{Path: "/{id}", Method: http.MethodGet, Func: api.GetWebhook, Middleware: []negroni.HandlerFunc{api.apiKeyAuth}},
+		{Path: "/{id}", Method: http.MethodPut, Func: api.UpdateWebhook, Middleware: []negroni.HandlerFunc{api.apiKeyAuth}},
+		{Path: "/{id}", Method: http.MethodDelete, Func: api.DeleteWebhook, Middleware: []negroni.HandlerFunc{api.apiKeyAuth}},
+		{Path: "/{id}/deliveries", Method: http.MethodGet, Func: api.GetWebhookDeliveries, Middleware: []negroni.HandlerFunc{api.apiKeyAuth}},
+	}))
+
+}
+
+func (api *API) publicRoutes() []*Route {
+	return []*Route{
 		{Path: "/", Method: http.MethodGet, Func: api.Index},
 		{Path: "/health", Method: http.MethodGet, Func: api.Health},
 		{Path: "/api", Method: http.MethodGet, Func: api.GetSwaggerAPI},
 
-		{Path: "/meta", Method: http.MethodGet, Func: api.GetMetaData},
-		{Path: "/historical-state", Method: http.MethodGet, Func: api.GetHistoricalState},
-		{Path: "/transactions/fee/agg", Method: http.MethodGet, Func: api.GetAggTransactionsFee},
-		{Path: "/transfers/volume/agg", Method: http.MethodGet, Func: api.GetAggTransfersVolume},
-		{Path: "/operations/count/agg", Method: http.MethodGet, Func: api.GetAggOperationsCount},
-		{Path: "/blocks/count/agg", Method: http.MethodGet, Func: api.GetAggBlocksCount},
-		{Path: "/blocks/delay/agg", Method: http.MethodGet, Func: api.GetAggBlocksDelay},
-		{Path: "/blocks/validators/uniq/agg", Method: http.MethodGet, Func: api.GetAggUniqBlockValidators},
-		{Path: "/blocks/operations/agg", Method: http.MethodGet, Func: api.GetAvgOperationsPerBlock},
-		{Path: "/delegations/volume/agg", Method: http.MethodGet, Func: api.GetAggDelegationsVolume},
-		{Path: "/undelegations/volume/agg", Method: http.MethodGet, Func: api.GetAggUndelegationsVolume},
-		{Path: "/unbonding/volume/agg", Method: http.MethodGet, Func: api.GetAggUnbondingVolume},
-		{Path: "/bonded-ratio/agg", Method: http.MethodGet, Func: api.GetAggBondedRatio},
-		{Path: "/network/stats", Method: http.MethodGet, Func: api.GetNetworkStats},
-		{Path: "/staking/pie", Method: http.MethodGet, Func: api.GetStakingPie},
-		{Path: "/proposals", Method: http.MethodGet, Func: api.GetProposals},
-		{Path: "/proposals/votes", Method: http.MethodGet, Func: api.GetProposalVotes},
-		{Path: "/proposals/deposits", Method: http.MethodGet, Func: api.GetProposalDeposits},
-		{Path: "/proposals/chart", Method: http.MethodGet, Func: api.GetProposalChartData},
-		{Path: "/validators", Method: http.MethodGet, Func: api.GetValidators},
-		{Path: "/validators/33power/agg", Method: http.MethodGet, Func: api.GetAggValidators33Power},
-		{Path: "/validators/top/proposed", Method: http.MethodGet, Func: api.GetTopProposedBlocksValidators},
-		{Path: "/validators/top/jailed", Method: http.MethodGet, Func: api.GetMostJailedValidators},
-		{Path: "/validators/fee/ranges", Method: http.MethodGet, Func: api.GetFeeRanges},
-		{Path: "/validators/delegators/total", Method: http.MethodGet, Func: api.GetValidatorsDelegatorsTotal},
-		{Path: "/accounts/whale/agg", Method: http.MethodGet, Func: api.GetAggWhaleAccounts},
-		{Path: "/validator/{address}/balance", Method: http.MethodGet, Func: api.GetValidatorBalance},
-		{Path: "/validator/{address}/delegations/agg", Method: http.MethodGet, Func: api.GetValidatorDelegationsAgg},
-		{Path: "/validator/{address}/delegators/agg", Method: http.MethodGet, Func: api.GetValidatorDelegatorsAgg},
-		{Path: "/validator/{address}/blocks/stats", Method: http.MethodGet, Func: api.GetValidatorBlocksStat},
-		{Path: "/validator/{address}", Method: http.MethodGet, Func: api.GetValidator},
-		{Path: "/validator/{address}/delegators", Method: http.MethodGet, Func: api.GetValidatorDelegators},
-		{Path: "/blocks", Method: http.MethodGet, Func: api.GetBlocks},
-		{Path: "/block/{height}", Method: http.MethodGet, Func: api.GetBlock},
-		{Path: "/transactions", Method: http.MethodGet, Func: api.GetTransactions},
-		{Path: "/transaction/{hash}", Method: http.MethodGet, Func: api.GetTransaction},
-		{Path: "/account/{address}", Method: http.MethodGet, Func: api.GetAccount},
-	})
+		{Path: "/meta", Method: http.MethodGet, Func: api.GetMetaData, CacheTTL: blockTime, CacheUntilCommit: true},
+		{Path: "/historical-state", Method: http.MethodGet, Func: api.GetHistoricalState, CacheTTL: time.Hour},
+		{Path: "/prices/currencies", Method: http.MethodGet, Func: api.GetQuoteCurrencies, CacheTTL: time.Hour},
+		{Path: "/prices/agg", Method: http.MethodGet, Func: api.GetAggPrices, CacheTTL: time.Minute * 5},
+		{Path: "/metrics", Method: http.MethodGet, Func: api.GetMetrics, CacheTTL: time.Hour},
+		{Path: "/metrics/{name}", Method: http.MethodGet, Func: api.GetMetric, Cost: 2, CacheTTL: time.Minute * 5},
+		{Path: "/supply", Method: http.MethodGet, Func: api.GetSupply, CacheTTL: time.Minute},
+		{Path: "/supply/circulating", Method: http.MethodGet, Func: api.GetCirculatingSupply, CacheTTL: time.Minute},
+		{Path: "/supply/agg", Method: http.MethodGet, Func: api.GetAggSupply, CacheTTL: time.Minute * 5},
+		{Path: "/vesting/schedule", Method: http.MethodGet, Func: api.GetVestingSchedule, CacheTTL: time.Minute * 10, AtomValues: true},
+		{Path: "/transactions/fee/agg", Method: http.MethodGet, Func: api.metricAlias(dmodels.MetricTransactionsFee), CacheTTL: time.Minute * 5, AtomValues: true},
+		{Path: "/transfers/volume/agg", Method: http.MethodGet, Func: api.GetAggTransfersVolume, CacheTTL: time.Minute * 5, AtomValues: true},
+		{Path: "/operations/count/agg", Method: http.MethodGet, Func: api.metricAlias(dmodels.MetricOperationsCount), CacheTTL: time.Minute * 5},
+		{Path: "/blocks/count/agg", Method: http.MethodGet, Func: api.metricAlias(dmodels.MetricBlocksCount), CacheTTL: time.Minute * 5},
+		{Path: "/blocks/delay/agg", Method: http.MethodGet, Func: api.metricAlias(dmodels.MetricBlocksDelay), CacheTTL: time.Minute * 5},
+		{Path: "/blocks/validators/uniq/agg", Method: http.MethodGet, Func: api.metricAlias(dmodels.MetricBlockValidators), CacheTTL: time.Minute * 5},
+		{Path: "/blocks/operations/agg", Method: http.MethodGet, Func: api.GetAvgOperationsPerBlock, CacheTTL: time.Minute * 5},
+		{Path: "/delegations/volume/agg", Method: http.MethodGet, Func: api.GetAggDelegationsVolume, CacheTTL: time.Minute * 5, AtomValues: true},
+		{Path: "/undelegations/volume/agg", Method: http.MethodGet, Func: api.metricAlias(dmodels.MetricUndelegationsVolume), CacheTTL: time.Minute * 5, AtomValues: true},
+		{Path: "/unbonding/volume/agg", Method: http.MethodGet, Func: api.GetAggUnbondingVolume, CacheTTL: time.Minute * 5, AtomValues: true},
+		{Path: "/bonded-ratio/agg", Method: http.MethodGet, Func: api.metricAlias(dmodels.MetricBondedRatio), CacheTTL: time.Minute * 5},
+		{Path: "/network/stats", Method: http.MethodGet, Func: api.GetNetworkStats, CacheTTL: time.Minute * 10},
+		{Path: "/staking/pie", Method: http.MethodGet, Func: api.GetStakingPie, CacheTTL: time.Minute},
+		{Path: "/proposals", Method: http.MethodGet, Func: api.GetProposals, CacheTTL: time.Minute},
+		{Path: "/proposals/votes", Method: http.MethodGet, Func: api.GetProposalVotes, CacheTTL: time.Minute},
+		{Path: "/proposals/votes/agg", Method: http.MethodGet, Func: api.GetAggProposalVotes, CacheTTL: time.Minute * 5},
+		{Path: "/proposals/deposits", Method: http.MethodGet, Func: api.GetProposalDeposits, CacheTTL: time.Minute},
+		{Path: "/proposals/chart", Method: http.MethodGet, Func: api.GetProposalChartData, CacheTTL: time.Minute},
+		{Path: "/proposal/{id}", Method: http.MethodGet, Func: api.GetProposal, Cost: 2, CacheTTL: time.Minute},
+		{Path: "/proposal/{id}/tally/history", Method: http.MethodGet, Func: api.GetProposalTallySnapshots, CacheTTL: time.Minute * 10},
+		{Path: "/validators", Method: http.MethodGet, Func: api.GetValidators, CacheTTL: time.Minute},
+		{Path: "/validators/33power/agg", Method: http.MethodGet, Func: api.GetAggValidators33Power, CacheTTL: time.Minute * 5},
+		{Path: "/validators/top/proposed", Method: http.MethodGet, Func: api.GetTopProposedBlocksValidators, CacheTTL: time.Minute * 10},
+		{Path: "/validators/top/jailed", Method: http.MethodGet, Func: api.GetMostJailedValidators, CacheTTL: time.Minute * 10},
+		{Path: "/validators/fee/ranges", Method: http.MethodGet, Func: api.GetFeeRanges, CacheTTL: time.Minute},
+		{Path: "/validators/apr", Method: http.MethodGet, Func: api.GetValidatorsAPR, Cost: 3, CacheTTL: time.Minute * 10},
+		{Path: "/staking/params", Method: http.MethodGet, Func: api.GetStakingParams, CacheTTL: time.Minute * 10},
+		{Path: "/validators/decentralization", Method: http.MethodGet, Func: api.GetDecentralization, CacheTTL: time.Minute * 10},
+		{Path: "/validators/decentralization/{metric}/agg", Method: http.MethodGet, Func: api.GetAggDecentralization, CacheTTL: time.Minute * 5},
+		{Path: "/validators/uptime", Method: http.MethodGet, Func: api.GetValidatorsUptime, Cost: 2, CacheTTL: time.Minute},
+		{Path: "/validators/delegators/total", Method: http.MethodGet, Func: api.GetValidatorsDelegatorsTotal, Cost: 3, CacheTTL: time.Minute * 10},
+		{Path: "/accounts/whale/agg", Method: http.MethodGet, Func: api.metricAlias(dmodels.MetricWhaleAccounts), CacheTTL: time.Minute * 5},
+		{Path: "/validator/{address}/balance", Method: http.MethodGet, Func: api.GetValidatorBalance, CacheTTL: blockTime, CacheUntilCommit: true},
+		{Path: "/validator/{address}/delegations/agg", Method: http.MethodGet, Func: api.GetValidatorDelegationsAgg, CacheTTL: time.Minute * 5, AtomValues: true},
+		{Path: "/validator/{address}/delegators/agg", Method: http.MethodGet, Func: api.GetValidatorDelegatorsAgg, CacheTTL: time.Minute * 5},
+		{Path: "/validator/{address}/blocks/stats", Method: http.MethodGet, Func: api.GetValidatorBlocksStat, CacheTTL: time.Minute},
+		{Path: "/validator/{address}/power/agg", Method: http.MethodGet, Func: api.GetAggValidatorPower, Cost: 2, CacheTTL: time.Minute * 5, AtomValues: true},
+		{Path: "/validator/{address}/rewards/estimate", Method: http.MethodGet, Func: api.GetValidatorRewardsEstimate, Cost: 2, CacheTTL: time.Minute * 5},
+		{Path: "/validator/{address}/uptime", Method: http.MethodGet, Func: api.GetValidatorUptime, CacheTTL: time.Minute},
+		{Path: "/validator/{address}/signing-map", Method: http.MethodGet, Func: api.GetValidatorSigningMap, CacheTTL: blockTime, CacheUntilCommit: true},
+		{Path: "/validator/{address}", Method: http.MethodGet, Func: api.GetValidator, CacheTTL: time.Minute},
+		{Path: "/validator/{address}/delegators", Method: http.MethodGet, Func: api.GetValidatorDelegators, Cost: 5, CacheTTL: time.Minute},
+		{Path: "/blocks", Method: http.MethodGet, Func: api.GetBlocks, Cost: 2, CacheTTL: blockTime, CacheUntilCommit: true},
+		{Path: "/block/{height}", Method: http.MethodGet, Func: api.GetBlock, CacheTTL: time.Hour},
+		{Path: "/transactions", Method: http.MethodGet, Func: api.GetTransactions, Cost: 2, CacheTTL: blockTime, CacheUntilCommit: true},
+		{Path: "/transaction/{hash}", Method: http.MethodGet, Func: api.GetTransaction, CacheTTL: time.Hour},
+		{Path: "/transfers", Method: http.MethodGet, Func: api.GetTransfers, Cost: 2, CacheTTL: blockTime, CacheUntilCommit: true},
+		{Path: "/trace", Method: http.MethodGet, Func: api.GetTransfersTrace, Cost: 10, CacheTTL: time.Minute * 5},
+		{Path: "/labels", Method: http.MethodGet, Func: api.GetAddressLabels, CacheTTL: time.Minute},
+		{Path: "/entities", Method: http.MethodGet, Func: api.GetEntities, CacheTTL: time.Minute},
+		{Path: "/accounts", Method: http.MethodGet, Func: api.GetRichList, Cost: 2, CacheTTL: time.Minute},
+		{Path: "/accounts/distribution", Method: http.MethodGet, Func: api.GetAccountsDistribution, CacheTTL: time.Minute * 10},
+		{Path: "/accounts/distribution/{metric}/agg", Method: http.MethodGet, Func: api.GetAggAccountsDistribution, CacheTTL: time.Minute * 5},
+		{Path: "/account/{address}", Method: http.MethodGet, Func: api.GetAccount, CacheTTL: blockTime, CacheUntilCommit: true},
+		{Path: "/account/{address}/balance/history", Method: http.MethodGet, Func: api.GetAccountBalanceHistory, Cost: 2, CacheTTL: time.Minute * 5},
+		{Path: "/account/{address}/delegations", Method: http.MethodGet, Func: api.GetAccountDelegations, Cost: 3, CacheTTL: blockTime, CacheUntilCommit: true},
+		{Path: "/account/{address}/vesting", Method: http.MethodGet, Func: api.GetAccountVesting, CacheTTL: time.Minute},
+		{Path: "/account/{address}/counterparties", Method: http.MethodGet, Func: api.GetCounterparties, Cost: 3, CacheTTL: time.Minute},
+		{Path: "/account/{address}/export", Method: http.MethodGet, Func: api.ExportAccount, Cost: 20},
+	}
+}
 
+// withCommonMiddleware puts the rate limiter and the response cache in front of the route middleware
+func (api *API) withCommonMiddleware(routes []*Route) []*Route {
+	for _, r := range routes {
+		common := []negroni.HandlerFunc{api.rateLimit(r.Cost)}
+		if r.CacheTTL > 0 {
+			common = append(common, api.responseCache(r))
+		}
+		r.Middleware = append(common, r.Middleware...)
+	}
+	return routes
 }
 
 func jsonData(writer http.ResponseWriter, data interface{}) {
+	if w, ok := writer.(v2Writer); ok {
+		data = v2Value(reflect.ValueOf(data), false, w.atomValues)
+	}
 	bytes, err := json.Marshal(data)
 	if err != nil {
 		writer.WriteHeader(500)
@@ -148,34 +319,60 @@ func jsonData(writer http.ResponseWriter, data interface{}) {
 	writer.Write(bytes)
 }
 
-func jsonError(writer http.ResponseWriter) {
-	writer.WriteHeader(500)
-	bytes, err := json.Marshal(errResponse{
-		Error: "service_error",
-	})
-	if err != nil {
-		writer.Write([]byte("can`t marshal json"))
-		return
+// jsonError writes the error with the status and the code of its type, untyped errors are service errors
+func jsonError(writer http.ResponseWriter, err error) {
+	status, code := http.StatusInternalServerError, errCodeServiceError
+	switch derrors.Code(err) {
+	case derrors.CodeNotFound:
+		status, code = http.StatusNotFound, errCodeNotFound
+	case derrors.CodeInvalidArgument:
+		status, code = http.StatusBadRequest, errCodeBadRequest
+	case derrors.CodeDuplicate:
+		status, code = http.StatusConflict, errCodeConflict
+	case derrors.CodeUpstreamUnavailable:
+		status, code = http.StatusServiceUnavailable, errCodeUpstreamUnavailable
+	case derrors.CodeTimeout:
+		status, code = http.StatusGatewayTimeout, errCodeTimeout
 	}
-	writer.Header().Set("Content-Type", "application/json")
-	writer.Write(bytes)
+	var msg string
+	if status == http.StatusNotFound || status == http.StatusBadRequest || status == http.StatusConflict {
+		msg = publicMsg(err)
+	}
+	jsonErrorWithStatus(writer, status, errResponse{
+		Error: code,
+		Msg:   msg,
+	})
 }
 
 func jsonBadRequest(writer http.ResponseWriter, msg string) {
-	bytes, err := json.Marshal(errResponse{
-		Error: "bad_request",
+	jsonErrorWithStatus(writer, http.StatusBadRequest, errResponse{
+		Error: errCodeBadRequest,
 		Msg:   msg,
 	})
+}
+
+func jsonErrorWithStatus(writer http.ResponseWriter, status int, resp errResponse) {
+	resp.RequestID = writer.Header().Get(requestIDHeader)
+	bytes, err := json.Marshal(resp)
 	if err != nil {
 		writer.WriteHeader(500)
 		writer.Write([]byte("can`t marshal json"))
 		return
 	}
 	writer.Header().Set("Content-Type", "application/json")
-	writer.WriteHeader(400)
+	writer.WriteHeader(status)
 	writer.Write(bytes)
 }
 
+// publicMsg returns the message of the typed error without the internal call chain
+func publicMsg(err error) string {
+	var e *derrors.Error
+	if errors.As(err, &e) {
+		return e.Msg
+	}
+	return ""
+}
+
 func (api *API) GetSwaggerAPI(w http.ResponseWriter, r *http.Request) {
 	body, err := ioutil.ReadFile("./resources/templates/swagger.html")
 	if err != nil {
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/log"
+	"github.com/everstake/cosmoscan-api/smodels"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+)
+
+func (api *API) GetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	resp, err := api.svc.GetAPIKeys()
+	if err != nil {
+		log.Error("API GetAPIKeys: svc.GetAPIKeys: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	params, ok := decodeAPIKeyParams(w, r)
+	if !ok {
+		return
+	}
+	resp, err := api.svc.CreateAPIKey(params)
+	if err != nil {
+		log.Error("API CreateAPIKey: svc.CreateAPIKey: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) UpdateAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		jsonBadRequest(w, "invalid id")
+		return
+	}
+	params, ok := decodeAPIKeyParams(w, r)
+	if !ok {
+		return
+	}
+	resp, err := api.svc.UpdateAPIKey(id, params)
+	if err != nil {
+		log.Error("API UpdateAPIKey: svc.UpdateAPIKey: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) GetAPIKeyUsages(w http.ResponseWriter, r *http.Request) {
+	var filter filters.APIKeyUsages
+	err := api.queryDecoder.Decode(&filter, r.URL.Query())
+	if err != nil {
+		log.Debug("API Decode: %s", err.Error())
+		jsonBadRequest(w, "")
+		return
+	}
+	filter.KeyID, err = strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		jsonBadRequest(w, "invalid id")
+		return
+	}
+	resp, err := api.svc.GetAPIKeyUsages(filter)
+	if err != nil {
+		log.Error("API GetAPIKeyUsages: svc.GetAPIKeyUsages: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func decodeAPIKeyParams(w http.ResponseWriter, r *http.Request) (params smodels.APIKeyParams, ok bool) {
+	err := json.NewDecoder(r.Body).Decode(&params)
+	if err != nil {
+		log.Debug("API Decode: %s", err.Error())
+		jsonBadRequest(w, "invalid body")
+		return params, false
+	}
+	if params.Name == "" {
+		jsonBadRequest(w, "name is required")
+		return params, false
+	}
+	if params.Rate.IsNegative() {
+		jsonBadRequest(w, "rate must not be negative")
+		return params, false
+	}
+	return params, true
+}
@@ -8,18 +8,6 @@ import (
 	"strconv"
 )
 
-func (api *API) GetAggBlocksCount(w http.ResponseWriter, r *http.Request) {
-	api.aggHandler(w, r, api.svc.GetAggBlocksCount)
-}
-
-func (api *API) GetAggBlocksDelay(w http.ResponseWriter, r *http.Request) {
-	api.aggHandler(w, r, api.svc.GetAggBlocksDelay)
-}
-
-func (api *API) GetAggUniqBlockValidators(w http.ResponseWriter, r *http.Request) {
-	api.aggHandler(w, r, api.svc.GetAggUniqBlockValidators)
-}
-
 func (api *API) GetBlock(w http.ResponseWriter, r *http.Request) {
 	heightStr, ok := mux.Vars(r)["height"]
 	if !ok || heightStr == "" {
@@ -34,7 +22,7 @@ func (api *API) GetBlock(w http.ResponseWriter, r *http.Request) {
 	resp, err := api.svc.GetBlock(height)
 	if err != nil {
 		log.Error("API GetValidator: svc.GetBlock: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
@@ -54,7 +42,7 @@ func (api *API) GetBlocks(w http.ResponseWriter, r *http.Request) {
 	resp, err := api.svc.GetBlocks(filter)
 	if err != nil {
 		log.Error("API GetBlocks: svc.GetBlocks: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"github.com/everstake/cosmoscan-api/dao"
+	"github.com/urfave/negroni"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const responseCacheKeyPrefix = "response_"
+
+type (
+	cachedResponse struct {
+		Header       http.Header
+		Body         []byte
+		ETag         string
+		LastModified time.Time
+		ExpiresAt    time.Time
+		ParserHeight uint64
+	}
+	// responseRecorder buffers the response so it can be stored before it's sent to the client
+	responseRecorder struct {
+		header http.Header
+		body   bytes.Buffer
+		status int
+	}
+)
+
+func (rec *responseRecorder) Header() http.Header {
+	return rec.header
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	return rec.body.Write(b)
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+}
+
+// responseCache serves successful GET responses of the route from the cache for the TTL of the route.
+// Responses of routes with CacheUntilCommit also expire as soon as the parser saves new blocks.
+func (api *API) responseCache(route *Route) negroni.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		if r.Method != http.MethodGet {
+			next(w, r)
+			return
+		}
+		key := responseCacheKey(r)
+		height := api.parserHeight()
+		data, found := api.dao.CacheGet(key)
+		if resp, ok := data.(cachedResponse); found && ok {
+			if !route.CacheUntilCommit || resp.ParserHeight == height {
+				writeCachedResponse(w, r, resp)
+				return
+			}
+		}
+		// the request id is passed to the handler for error responses but isn't stored with the response
+		rec := &responseRecorder{header: http.Header{requestIDHeader: w.Header()[requestIDHeader]}}
+		next(rec, r)
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+		if rec.status != http.StatusOK {
+			rec.header.Del(requestIDHeader)
+			for k, v := range rec.header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(rec.status)
+			w.Write(rec.body.Bytes())
+			return
+		}
+		rec.header.Del(requestIDHeader)
+		sum := sha1.Sum(rec.body.Bytes())
+		now := time.Now().UTC()
+		resp := cachedResponse{
+			Header:       rec.header,
+			Body:         rec.body.Bytes(),
+			ETag:         fmt.Sprintf(`"%s"`, hex.EncodeToString(sum[:])),
+			LastModified: now.Truncate(time.Second),
+			ExpiresAt:    now.Add(route.CacheTTL),
+			ParserHeight: height,
+		}
+		api.dao.CacheSet(key, resp, route.CacheTTL)
+		writeCachedResponse(w, r, resp)
+	}
+}
+
+func (api *API) parserHeight() uint64 {
+	data, found := api.dao.CacheGet(dao.ParserHeightCacheKey)
+	if !found {
+		return 0
+	}
+	height, _ := data.(uint64)
+	return height
+}
+
+func writeCachedResponse(w http.ResponseWriter, r *http.Request, resp cachedResponse) {
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	maxAge := int64(time.Until(resp.ExpiresAt).Seconds())
+	if maxAge < 0 {
+		maxAge = 0
+	}
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+	w.Header().Set("ETag", resp.ETag)
+	w.Header().Set("Last-Modified", resp.LastModified.Format(http.TimeFormat))
+	if notModified(r, resp) {
+		w.Header().Del("Content-Type")
+		w.Header().Del("Content-Length")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Write(resp.Body)
+}
+
+func notModified(r *http.Request, resp cachedResponse) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == resp.ETag {
+				return true
+			}
+		}
+		return false
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		t, err := http.ParseTime(ims)
+		return err == nil && !resp.LastModified.After(t)
+	}
+	return false
+}
+
+// responseCacheKey builds the key from the request path and the query with sorted params,
+// the api key doesn't affect the response so it's left out
+func responseCacheKey(r *http.Request) string {
+	query := make(url.Values)
+	for k, v := range r.URL.Query() {
+		if k == apiKeyQueryParam {
+			continue
+		}
+		values := append([]string(nil), v...)
+		sort.Strings(values)
+		query[k] = values
+	}
+	return responseCacheKeyPrefix + r.URL.Path + "?" + query.Encode()
+}
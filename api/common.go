@@ -40,7 +40,7 @@ func (api *API) aggHandler(w http.ResponseWriter, r *http.Request, action func(f
 	resp, err := action(filter)
 	if err != nil {
 		log.Error("API %s: %s", method, err.Error())
-		jsonError(w)
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
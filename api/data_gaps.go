@@ -0,0 +1,41 @@
+package api
+
+import (
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/log"
+	"net/http"
+)
+
+func (api *API) GetDataGaps(w http.ResponseWriter, r *http.Request) {
+	var filter filters.TimeRange
+	err := api.queryDecoder.Decode(&filter, r.URL.Query())
+	if err != nil {
+		log.Debug("API GetDataGaps: Decode: %s", err.Error())
+		jsonBadRequest(w, "")
+		return
+	}
+	resp, err := api.svc.GetDataGaps(filter)
+	if err != nil {
+		log.Error("API GetDataGaps: svc.GetDataGaps: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) RepairDataGaps(w http.ResponseWriter, r *http.Request) {
+	var filter filters.TimeRange
+	err := api.queryDecoder.Decode(&filter, r.URL.Query())
+	if err != nil {
+		log.Debug("API RepairDataGaps: Decode: %s", err.Error())
+		jsonBadRequest(w, "")
+		return
+	}
+	resp, err := api.svc.RepairDataGaps(filter)
+	if err != nil {
+		log.Error("API RepairDataGaps: svc.RepairDataGaps: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
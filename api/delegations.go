@@ -18,16 +18,12 @@ func (api *API) GetAggDelegationsVolume(w http.ResponseWriter, r *http.Request)
 	resp, err := api.svc.GetAggDelegationsVolume(filter)
 	if err != nil {
 		log.Error("API GetAggDelegationsVolume: svc.GetAggDelegationsVolume: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
 }
 
-func (api *API) GetAggUndelegationsVolume(w http.ResponseWriter, r *http.Request) {
-	api.aggHandler(w, r, api.svc.GetAggUndelegationsVolume)
-}
-
 func (api *API) GetAggUnbondingVolume(w http.ResponseWriter, r *http.Request) {
 	api.aggHandler(w, r, api.svc.GetAggUnbondingVolume)
 }
@@ -36,7 +32,7 @@ func (api *API) GetStakingPie(w http.ResponseWriter, r *http.Request) {
 	resp, err := api.svc.GetStakingPie()
 	if err != nil {
 		log.Error("API GetStakingPie: svc.GetStakingPie: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
@@ -51,7 +47,7 @@ func (api *API) GetValidatorDelegationsAgg(w http.ResponseWriter, r *http.Reques
 	resp, err := api.svc.GetValidatorDelegationsAgg(address)
 	if err != nil {
 		log.Error("API GetValidatorDelegationsAgg: svc.GetValidatorDelegationsAgg: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
@@ -66,7 +62,7 @@ func (api *API) GetValidatorDelegatorsAgg(w http.ResponseWriter, r *http.Request
 	resp, err := api.svc.GetValidatorDelegatorsAgg(address)
 	if err != nil {
 		log.Error("API GetValidatorDelegatorsAgg: svc.GetValidatorDelegatorsAgg: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
@@ -92,7 +88,7 @@ func (api *API) GetValidatorDelegators(w http.ResponseWriter, r *http.Request) {
 	resp, err := api.svc.GetValidatorDelegators(filter)
 	if err != nil {
 		log.Error("API GetValidatorDelegators: svc.GetValidatorDelegators: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
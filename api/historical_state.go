@@ -6,10 +6,10 @@ import (
 )
 
 func (api *API) GetHistoricalState(w http.ResponseWriter, r *http.Request) {
-	resp, err := api.svc.GetHistoricalState()
+	resp, err := api.svc.GetHistoricalState(r.URL.Query().Get("currency"))
 	if err != nil {
 		log.Error("API GetHistoricalState: svc.GetHistoricalState: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
@@ -6,10 +6,10 @@ import (
 )
 
 func (api *API) GetMetaData(w http.ResponseWriter, r *http.Request) {
-	resp, err := api.svc.GetMetaData()
+	resp, err := api.svc.GetMetaData(r.URL.Query().Get("currency"))
 	if err != nil {
 		log.Error("API GetMetaData: svc.GetMetaData: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/log"
+	"github.com/everstake/cosmoscan-api/smodels"
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+func (api *API) GetMetrics(w http.ResponseWriter, r *http.Request) {
+	jsonData(w, api.svc.GetMetrics())
+}
+
+func (api *API) GetMetric(w http.ResponseWriter, r *http.Request) {
+	name, ok := mux.Vars(r)["name"]
+	if !ok || name == "" {
+		jsonBadRequest(w, "invalid name")
+		return
+	}
+	var filter filters.Metric
+	err := api.queryDecoder.Decode(&filter, r.URL.Query())
+	if err != nil {
+		log.Debug("API GetMetric: Decode: %s", err.Error())
+		jsonBadRequest(w, "")
+		return
+	}
+	err = filter.Validate()
+	if err != nil {
+		log.Debug("API GetMetric: Validate: %s", err.Error())
+		jsonBadRequest(w, err.Error())
+		return
+	}
+	filter.Name = name
+	resp, err := api.svc.GetMetric(filter)
+	if err != nil {
+		log.Error("API GetMetric: svc.GetMetric: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	// the unit of the values depends on the metric, so it is not known by the route
+	if v2w, ok := w.(v2Writer); ok {
+		v2w.atomValues = resp.Unit == unitAtom
+		w = v2w
+	}
+	jsonData(w, resp)
+}
+
+// metricAlias serves the items of the metric on the legacy agg routes, the range is validated
+// by aggHandler with the limits of the routes instead of the limits of the metrics
+func (api *API) metricAlias(name string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		api.aggHandler(w, r, func(agg filters.Agg) ([]smodels.AggItem, error) {
+			series, err := api.svc.GetMetric(filters.MetricFromAgg(name, agg))
+			if err != nil {
+				return nil, err
+			}
+			return series.Items, nil
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/everstake/cosmoscan-api/log"
+	"github.com/urfave/negroni"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	requestIDHeader  = "X-Request-ID"
+	maxRequestIDLen  = 64
+	apiKeyHeader     = "X-API-Key"
+	apiKeyQueryParam = "api_key"
+	defaultRouteCost = 1
+)
+
+type apiKeyContextKey struct{}
+
+// requestID takes the request id given by the client or a proxy or generates a new one,
+// it's returned in the response header and in error responses
+func requestID(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	id := r.Header.Get(requestIDHeader)
+	if !validRequestID(id) {
+		b := make([]byte, 16)
+		_, _ = rand.Read(b)
+		id = hex.EncodeToString(b)
+	}
+	w.Header().Set(requestIDHeader, id)
+	next(w, r)
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// rateLimit limits requests by api key if it's given and by client ip otherwise, cost is the weight of the route
+func (api *API) rateLimit(cost uint64) negroni.HandlerFunc {
+	if cost == 0 {
+		cost = defaultRouteCost
+	}
+	cfg := api.cfg.API.RateLimit
+	return func(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		if !cfg.Enabled || r.Method == http.MethodOptions {
+			next(w, r)
+			return
+		}
+		token := apiKeyToken(r)
+		if token == "" {
+			ok, retryAfter := api.limiter.Take("ip:"+api.clientIP(r), cfg.IPRate, cfg.IPBurst, cost)
+			if !ok {
+				jsonTooManyRequests(w, retryAfter, "rate limit exceeded")
+				return
+			}
+			next(w, r)
+			return
+		}
+		key, found, err := api.svc.GetAPIKeyByToken(token)
+		if err != nil {
+			log.Error("API rateLimit: svc.GetAPIKeyByToken: %s", err.Error())
+			jsonError(w, err)
+			return
+		}
+		if !found || !key.Active {
+			jsonUnauthorized(w, "invalid api key")
+			return
+		}
+		rate, burst := cfg.KeyRate, cfg.KeyBurst
+		if key.Rate.IsPositive() {
+			rate, _ = key.Rate.Float64()
+		}
+		if key.Burst > 0 {
+			burst = key.Burst
+		}
+		ok, retryAfter := api.limiter.Take(fmt.Sprintf("key:%d", key.ID), rate, burst, cost)
+		if !ok {
+			jsonTooManyRequests(w, retryAfter, "rate limit exceeded")
+			return
+		}
+		ok, err = api.svc.UseAPIKeyQuota(key, cost)
+		if err != nil {
+			log.Error("API rateLimit: svc.UseAPIKeyQuota: %s", err.Error())
+			jsonError(w, err)
+			return
+		}
+		if !ok {
+			now := time.Now().UTC()
+			tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+			jsonTooManyRequests(w, tomorrow.Sub(now), "daily quota exceeded")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// adminAuth allows requests with the configured admin token only, admin routes are disabled if the token is empty
+func (api *API) adminAuth(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if r.Method == http.MethodOptions {
+		next(w, r)
+		return
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	adminToken := api.cfg.API.AdminToken
+	if adminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+		jsonUnauthorized(w, "")
+		return
+	}
+	next(w, r)
+}
+
+// apiKeyAuth allows requests with an active api key only, the key is put into the request context
+func (api *API) apiKeyAuth(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if r.Method == http.MethodOptions {
+		next(w, r)
+		return
+	}
+	token := apiKeyToken(r)
+	if token == "" {
+		jsonUnauthorized(w, "api key is required")
+		return
+	}
+	key, found, err := api.svc.GetAPIKeyByToken(token)
+	if err != nil {
+		log.Error("API apiKeyAuth: svc.GetAPIKeyByToken: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	if !found || !key.Active {
+		jsonUnauthorized(w, "invalid api key")
+		return
+	}
+	next(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, key)))
+}
+
+// requestAPIKey returns the api key put into the context by apiKeyAuth
+func requestAPIKey(r *http.Request) dmodels.APIKey {
+	key, _ := r.Context().Value(apiKeyContextKey{}).(dmodels.APIKey)
+	return key
+}
+
+func apiKeyToken(r *http.Request) string {
+	token := r.Header.Get(apiKeyHeader)
+	if token == "" {
+		token = r.URL.Query().Get(apiKeyQueryParam)
+	}
+	return token
+}
+
+func (api *API) clientIP(r *http.Request) string {
+	if api.cfg.API.RateLimit.TrustProxyHeaders {
+		if ip := r.Header.Get("X-Real-IP"); ip != "" {
+			return ip
+		}
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func jsonUnauthorized(writer http.ResponseWriter, msg string) {
+	jsonErrorWithStatus(writer, http.StatusUnauthorized, errResponse{
+		Error: errCodeUnauthorized,
+		Msg:   msg,
+	})
+}
+
+func jsonTooManyRequests(writer http.ResponseWriter, retryAfter time.Duration, msg string) {
+	writer.Header().Set("Retry-After", fmt.Sprintf("%d", int64(math.Ceil(retryAfter.Seconds()))))
+	jsonErrorWithStatus(writer, http.StatusTooManyRequests, errResponse{
+		Error: errCodeTooManyRequests,
+		Msg:   msg,
+	})
+}
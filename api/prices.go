@@ -0,0 +1,34 @@
+package api
+
+import (
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/log"
+	"net/http"
+)
+
+func (api *API) GetQuoteCurrencies(w http.ResponseWriter, r *http.Request) {
+	jsonData(w, api.svc.GetQuoteCurrencies())
+}
+
+func (api *API) GetAggPrices(w http.ResponseWriter, r *http.Request) {
+	var filter filters.PricesAgg
+	err := api.queryDecoder.Decode(&filter, r.URL.Query())
+	if err != nil {
+		log.Debug("API GetAggPrices: Decode: %s", err.Error())
+		jsonBadRequest(w, "")
+		return
+	}
+	err = filter.Validate()
+	if err != nil {
+		log.Debug("API GetAggPrices: Validate: %s", err.Error())
+		jsonBadRequest(w, err.Error())
+		return
+	}
+	resp, err := api.svc.GetAggPrices(filter)
+	if err != nil {
+		log.Error("API GetAggPrices: svc.GetAggPrices: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
@@ -3,7 +3,9 @@ package api
 import (
 	"github.com/everstake/cosmoscan-api/dao/filters"
 	"github.com/everstake/cosmoscan-api/log"
+	"github.com/gorilla/mux"
 	"net/http"
+	"strconv"
 )
 
 func (api *API) GetProposals(w http.ResponseWriter, r *http.Request) {
@@ -17,7 +19,7 @@ func (api *API) GetProposals(w http.ResponseWriter, r *http.Request) {
 	resp, err := api.svc.GetProposals(filter)
 	if err != nil {
 		log.Error("API GetProposals: svc.GetProposals: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
@@ -39,7 +41,7 @@ func (api *API) GetProposalVotes(w http.ResponseWriter, r *http.Request) {
 	resp, err := api.svc.GetProposalVotes(filter)
 	if err != nil {
 		log.Error("API GetProposalVotes: svc.GetProposalVotes: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
@@ -56,7 +58,7 @@ func (api *API) GetProposalDeposits(w http.ResponseWriter, r *http.Request) {
 	resp, err := api.svc.GetProposalDeposits(filter)
 	if err != nil {
 		log.Error("API GetProposalDeposits: svc.GetProposalDeposits: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
@@ -66,7 +68,66 @@ func (api *API) GetProposalChartData(w http.ResponseWriter, r *http.Request) {
 	resp, err := api.svc.GetProposalsChartData()
 	if err != nil {
 		log.Error("API GetProposalsChartData: svc.GetProposalsChartData: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) GetProposal(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		jsonBadRequest(w, "invalid id")
+		return
+	}
+	resp, err := api.svc.GetProposal(id)
+	if err != nil {
+		log.Error("API GetProposal: svc.GetProposal: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) GetAggProposalVotes(w http.ResponseWriter, r *http.Request) {
+	var filter filters.ProposalVotesAgg
+	err := api.queryDecoder.Decode(&filter, r.URL.Query())
+	if err != nil {
+		log.Debug("API Decode: %s", err.Error())
+		jsonBadRequest(w, "")
+		return
+	}
+	err = filter.Validate()
+	if err != nil {
+		jsonBadRequest(w, err.Error())
+		return
+	}
+	resp, err := api.svc.GetAggProposalVotes(filter)
+	if err != nil {
+		log.Error("API GetAggProposalVotes: svc.GetAggProposalVotes: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) GetProposalTallySnapshots(w http.ResponseWriter, r *http.Request) {
+	var filter filters.ProposalTallySnapshots
+	err := api.queryDecoder.Decode(&filter, r.URL.Query())
+	if err != nil {
+		log.Debug("API Decode: %s", err.Error())
+		jsonBadRequest(w, "")
+		return
+	}
+	filter.ProposalID, err = strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		jsonBadRequest(w, "invalid id")
+		return
+	}
+	resp, err := api.svc.GetProposalTallySnapshots(filter)
+	if err != nil {
+		log.Error("API GetProposalTallySnapshots: svc.GetProposalTallySnapshots: %s", err.Error())
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
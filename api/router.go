@@ -4,6 +4,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/urfave/negroni"
 	"net/http"
+	"time"
 )
 
 // Route stores an API route data
@@ -12,6 +13,14 @@ type Route struct {
 	Method     string
 	Func       func(http.ResponseWriter, *http.Request)
 	Middleware []negroni.HandlerFunc
+	// Cost is the weight of the route for rate limiting, 1 if not set
+	Cost uint64
+	// CacheTTL enables caching of the route responses
+	CacheTTL time.Duration
+	// CacheUntilCommit expires cached responses when the parser saves new blocks
+	CacheUntilCommit bool
+	// AtomValues means that the values of the returned agg items are amounts in atom
+	AtomValues bool
 }
 
 // HandleActions is used to handle all given routes
@@ -17,7 +17,7 @@ func (api *API) GetNetworkStats(w http.ResponseWriter, r *http.Request) {
 	resp, err := api.svc.GetNetworkStates(filter)
 	if err != nil {
 		log.Error("API GetNetworkStats: svc.GetNetworkStates: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
@@ -26,11 +26,3 @@ func (api *API) GetNetworkStats(w http.ResponseWriter, r *http.Request) {
 func (api *API) GetAggValidators33Power(w http.ResponseWriter, r *http.Request) {
 	api.aggHandler(w, r, api.svc.GetAggValidators33Power)
 }
-
-func (api *API) GetAggWhaleAccounts(w http.ResponseWriter, r *http.Request) {
-	api.aggHandler(w, r, api.svc.GetAggWhaleAccounts)
-}
-
-func (api *API) GetAggBondedRatio(w http.ResponseWriter, r *http.Request) {
-	api.aggHandler(w, r, api.svc.GetAggBondedRatio)
-}
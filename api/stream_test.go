@@ -0,0 +1,59 @@
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExtendWriteDeadline(t *testing.T) {
+	const (
+		writeTimeout = time.Millisecond * 200
+		chunks       = 6
+		chunkDelay   = time.Millisecond * 100
+	)
+	chunk := strings.Repeat("x", 64*1024)
+	stream := func(extend bool) (int, error) {
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			flusher := w.(http.Flusher)
+			for i := 0; i < chunks; i++ {
+				if extend {
+					extendWriteDeadline(r, writeTimeout)
+				}
+				if _, err := w.Write([]byte(chunk)); err != nil {
+					return
+				}
+				flusher.Flush()
+				time.Sleep(chunkDelay)
+			}
+		}))
+		server.Config.WriteTimeout = writeTimeout
+		server.Config.ConnContext = withConn
+		server.Start()
+		defer server.Close()
+		resp, err := http.Get(server.URL)
+		if err != nil {
+			return 0, err
+		}
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		return len(body), err
+	}
+
+	n, err := stream(true)
+	if err != nil {
+		t.Fatalf("stream with extended deadline: %s", err)
+	}
+	if n != chunks*len(chunk) {
+		t.Fatalf("stream with extended deadline: got %d bytes, expected %d", n, chunks*len(chunk))
+	}
+
+	// without the extension the server-wide timeout cuts the stream
+	n, err = stream(false)
+	if err == nil && n == chunks*len(chunk) {
+		t.Fatalf("stream without extended deadline: expected to be cut after %s", writeTimeout)
+	}
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/log"
+	"net/http"
+)
+
+func (api *API) GetSupply(w http.ResponseWriter, r *http.Request) {
+	resp, err := api.svc.GetSupply()
+	if err != nil {
+		log.Error("API GetSupply: svc.GetSupply: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+// GetCirculatingSupply responds with the plain number of atoms as required by the listing sites
+func (api *API) GetCirculatingSupply(w http.ResponseWriter, r *http.Request) {
+	amount, err := api.svc.GetCirculatingSupply()
+	if err != nil {
+		log.Error("API GetCirculatingSupply: svc.GetCirculatingSupply: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte(amount.Truncate(6).String()))
+}
+
+func (api *API) GetAggSupply(w http.ResponseWriter, r *http.Request) {
+	var filter filters.Agg
+	err := api.queryDecoder.Decode(&filter, r.URL.Query())
+	if err != nil {
+		log.Debug("API GetAggSupply: Decode: %s", err.Error())
+		jsonBadRequest(w, "")
+		return
+	}
+	err = filter.Validate()
+	if err != nil {
+		log.Debug("API GetAggSupply: Validate: %s", err.Error())
+		jsonBadRequest(w, err.Error())
+		return
+	}
+	resp, err := api.svc.GetAggSupply(filter)
+	if err != nil {
+		log.Error("API GetAggSupply: svc.GetAggSupply: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
@@ -7,14 +7,6 @@ import (
 	"net/http"
 )
 
-func (api *API) GetAggTransactionsFee(w http.ResponseWriter, r *http.Request) {
-	api.aggHandler(w, r, api.svc.GetAggTransactionsFee)
-}
-
-func (api *API) GetAggOperationsCount(w http.ResponseWriter, r *http.Request) {
-	api.aggHandler(w, r, api.svc.GetAggOperationsCount)
-}
-
 func (api *API) GetAvgOperationsPerBlock(w http.ResponseWriter, r *http.Request) {
 	api.aggHandler(w, r, api.svc.GetAvgOperationsPerBlock)
 }
@@ -28,7 +20,7 @@ func (api *API) GetTransaction(w http.ResponseWriter, r *http.Request) {
 	resp, err := api.svc.GetTransaction(hash)
 	if err != nil {
 		log.Error("API GetTransaction: svc.GetTransaction: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
@@ -48,7 +40,7 @@ func (api *API) GetTransactions(w http.ResponseWriter, r *http.Request) {
 	resp, err := api.svc.GetTransactions(filter)
 	if err != nil {
 		log.Error("API GetTransactions: svc.GetTransactions: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
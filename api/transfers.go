@@ -1,9 +1,83 @@
 package api
 
 import (
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/log"
+	"github.com/gorilla/mux"
 	"net/http"
 )
 
 func (api *API) GetAggTransfersVolume(w http.ResponseWriter, r *http.Request) {
-	api.aggHandler(w, r, api.svc.GetAggTransfersVolume)
+	var filter filters.TransfersAgg
+	err := api.queryDecoder.Decode(&filter, r.URL.Query())
+	if err != nil {
+		log.Debug("API GetAggTransfersVolume: Decode: %s", err.Error())
+		jsonBadRequest(w, "")
+		return
+	}
+	err = filter.Validate()
+	if err != nil {
+		log.Debug("API GetAggTransfersVolume: Validate: %s", err.Error())
+		jsonBadRequest(w, err.Error())
+		return
+	}
+	resp, err := api.svc.GetAggTransfersVolume(filter)
+	if err != nil {
+		log.Error("API GetAggTransfersVolume: svc.GetAggTransfersVolume: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) GetTransfers(w http.ResponseWriter, r *http.Request) {
+	var filter filters.Transfers
+	err := api.queryDecoder.Decode(&filter, r.URL.Query())
+	if err != nil {
+		log.Debug("API GetTransfers: Decode: %s", err.Error())
+		jsonBadRequest(w, "")
+		return
+	}
+	resp, err := api.svc.GetTransfers(filter)
+	if err != nil {
+		log.Error("API GetTransfers: svc.GetTransfers: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) GetCounterparties(w http.ResponseWriter, r *http.Request) {
+	var filter filters.Counterparties
+	err := api.queryDecoder.Decode(&filter, r.URL.Query())
+	if err != nil {
+		log.Debug("API GetCounterparties: Decode: %s", err.Error())
+		jsonBadRequest(w, "")
+		return
+	}
+	filter.Address = mux.Vars(r)["address"]
+	resp, err := api.svc.GetCounterparties(filter)
+	if err != nil {
+		log.Error("API GetCounterparties: svc.GetCounterparties: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) GetTransfersTrace(w http.ResponseWriter, r *http.Request) {
+	var filter filters.Trace
+	err := api.queryDecoder.Decode(&filter, r.URL.Query())
+	if err != nil {
+		log.Debug("API GetTransfersTrace: Decode: %s", err.Error())
+		jsonBadRequest(w, "")
+		return
+	}
+	resp, err := api.svc.GetTransfersTrace(filter)
+	if err != nil {
+		log.Error("API GetTransfersTrace: svc.GetTransfersTrace: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
 }
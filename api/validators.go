@@ -1,16 +1,20 @@
 package api
 
 import (
+	"github.com/everstake/cosmoscan-api/dao/filters"
 	"github.com/everstake/cosmoscan-api/log"
+	"github.com/everstake/cosmoscan-api/smodels"
 	"github.com/gorilla/mux"
+	"github.com/shopspring/decimal"
 	"net/http"
+	"strconv"
 )
 
 func (api *API) GetTopProposedBlocksValidators(w http.ResponseWriter, r *http.Request) {
 	resp, err := api.svc.GetTopProposedBlocksValidators()
 	if err != nil {
 		log.Error("API GetTopProposedBlocksValidators: svc.GetTopProposedBlocksValidators: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
@@ -21,7 +25,7 @@ func (api *API) GetMostJailedValidators(w http.ResponseWriter, r *http.Request)
 	resp, err := api.svc.GetMostJailedValidators()
 	if err != nil {
 		log.Error("API GetMostJailedValidators: svc.GetMostJailedValidators: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
@@ -32,7 +36,7 @@ func (api *API) GetFeeRanges(w http.ResponseWriter, r *http.Request) {
 	resp, err := api.svc.GetFeeRanges()
 	if err != nil {
 		log.Error("API GetFeeRanges: svc.GetFeeRanges: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
@@ -43,7 +47,7 @@ func (api *API) GetValidators(w http.ResponseWriter, r *http.Request) {
 	resp, err := api.svc.GetValidators()
 	if err != nil {
 		log.Error("API GetValidators: svc.GetValidators: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
@@ -54,7 +58,7 @@ func (api *API) GetValidatorsDelegatorsTotal(w http.ResponseWriter, r *http.Requ
 	resp, err := api.svc.GetValidatorsDelegatorsTotal()
 	if err != nil {
 		log.Error("API GetValidatorsDelegatorsTotal: svc.GetValidatorsDelegatorsTotal: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
@@ -69,7 +73,7 @@ func (api *API) GetValidator(w http.ResponseWriter, r *http.Request) {
 	resp, err := api.svc.GetValidator(address)
 	if err != nil {
 		log.Error("API GetValidator: svc.GetValidator: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
@@ -84,7 +88,7 @@ func (api *API) GetValidatorBalance(w http.ResponseWriter, r *http.Request) {
 	resp, err := api.svc.GetValidatorBalance(address)
 	if err != nil {
 		log.Error("API GetValidatorBalance: svc.GetValidatorBalance: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
@@ -99,7 +103,145 @@ func (api *API) GetValidatorBlocksStat(w http.ResponseWriter, r *http.Request) {
 	resp, err := api.svc.GetValidatorBlocksStat(address)
 	if err != nil {
 		log.Error("API GetValidatorBlocksStat: svc.GetValidatorBlocksStat: %s", err.Error())
-		jsonError(w)
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) GetValidatorUptime(w http.ResponseWriter, r *http.Request) {
+	address, ok := mux.Vars(r)["address"]
+	if !ok || address == "" {
+		jsonBadRequest(w, "invalid address")
+		return
+	}
+	resp, err := api.svc.GetValidatorUptime(address)
+	if err != nil {
+		log.Error("API GetValidatorUptime: svc.GetValidatorUptime: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) GetValidatorSigningMap(w http.ResponseWriter, r *http.Request) {
+	address, ok := mux.Vars(r)["address"]
+	if !ok || address == "" {
+		jsonBadRequest(w, "invalid address")
+		return
+	}
+	var blocks uint64
+	if s := r.URL.Query().Get("blocks"); s != "" {
+		var err error
+		blocks, err = strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			jsonBadRequest(w, "invalid blocks")
+			return
+		}
+	}
+	resp, err := api.svc.GetValidatorSigningMap(address, blocks)
+	if err != nil {
+		log.Error("API GetValidatorSigningMap: svc.GetValidatorSigningMap: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) GetValidatorsUptime(w http.ResponseWriter, r *http.Request) {
+	resp, err := api.svc.GetValidatorsUptime()
+	if err != nil {
+		log.Error("API GetValidatorsUptime: svc.GetValidatorsUptime: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) GetAggValidatorPower(w http.ResponseWriter, r *http.Request) {
+	address, ok := mux.Vars(r)["address"]
+	if !ok || address == "" {
+		jsonBadRequest(w, "invalid address")
+		return
+	}
+	api.aggHandler(w, r, func(filter filters.Agg) ([]smodels.AggItem, error) {
+		return api.svc.GetAggValidatorPower(address, filter)
+	})
+}
+
+func (api *API) GetStakingParams(w http.ResponseWriter, r *http.Request) {
+	resp, err := api.svc.GetStakingParams()
+	if err != nil {
+		log.Error("API GetStakingParams: svc.GetStakingParams: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) GetValidatorRewardsEstimate(w http.ResponseWriter, r *http.Request) {
+	address, ok := mux.Vars(r)["address"]
+	if !ok || address == "" {
+		jsonBadRequest(w, "invalid address")
+		return
+	}
+	amount := decimal.New(1, 0)
+	if s := r.URL.Query().Get("amount"); s != "" {
+		var err error
+		amount, err = decimal.NewFromString(s)
+		if err != nil {
+			jsonBadRequest(w, "invalid amount")
+			return
+		}
+	}
+	resp, err := api.svc.GetValidatorRewardsEstimate(address, amount)
+	if err != nil {
+		log.Error("API GetValidatorRewardsEstimate: svc.GetValidatorRewardsEstimate: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) GetValidatorsAPR(w http.ResponseWriter, r *http.Request) {
+	resp, err := api.svc.GetValidatorsAPR()
+	if err != nil {
+		log.Error("API GetValidatorsAPR: svc.GetValidatorsAPR: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) GetDecentralization(w http.ResponseWriter, r *http.Request) {
+	resp, err := api.svc.GetDecentralization()
+	if err != nil {
+		log.Error("API GetDecentralization: svc.GetDecentralization: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) GetAggDecentralization(w http.ResponseWriter, r *http.Request) {
+	var filter filters.DecentralizationAgg
+	err := api.queryDecoder.Decode(&filter, r.URL.Query())
+	if err != nil {
+		log.Debug("API GetAggDecentralization: Decode: %s", err.Error())
+		jsonBadRequest(w, "")
+		return
+	}
+	err = filter.Validate()
+	if err != nil {
+		log.Debug("API GetAggDecentralization: Validate: %s", err.Error())
+		jsonBadRequest(w, err.Error())
+		return
+	}
+	filter.Metric = mux.Vars(r)["metric"]
+	resp, err := api.svc.GetAggDecentralization(filter)
+	if err != nil {
+		log.Error("API GetAggDecentralization: svc.GetAggDecentralization: %s", err.Error())
+		jsonError(w, err)
 		return
 	}
 	jsonData(w, resp)
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/everstake/cosmoscan-api/smodels"
+	"github.com/shopspring/decimal"
+	"github.com/urfave/negroni"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	v1 = "/v1"
+	v2 = "/v2"
+
+	unitTag  = "unit"
+	unitAtom = "atom"
+	// unitValue marks amounts in the unit of the values of the agg items of the response
+	unitValue = "value"
+)
+
+var (
+	uatomMul = decimal.New(1, 6)
+
+	timeType         = reflect.TypeOf(time.Time{})
+	dmodelsTimeType  = reflect.TypeOf(dmodels.Time{})
+	decimalType      = reflect.TypeOf(decimal.Decimal{})
+	aggItemType      = reflect.TypeOf(smodels.AggItem{})
+	rawMessageType   = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerTyp = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// v2Writer marks responses which are rendered in the v2 format by jsonData
+type v2Writer struct {
+	http.ResponseWriter
+	atomValues bool
+}
+
+// Flush keeps streaming handlers working behind the wrapper
+func (w v2Writer) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func v2Format(route *Route) negroni.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		next(v2Writer{ResponseWriter: w, atomValues: route.AtomValues}, r)
+	}
+}
+
+// v2Value converts the response to the v2 format: times are RFC3339 strings and
+// amounts in atom (fields tagged unit:"atom") are integer amounts of uatom
+func v2Value(v reflect.Value, atom bool, atomValues bool) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return v2Value(v.Elem(), atom, atomValues)
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type() == rawMessageType || v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		fallthrough
+	case reflect.Array:
+		items := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items[i] = v2Value(v.Index(i), atom, atomValues)
+		}
+		return items
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		mp := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			mp[key] = v2Value(iter.Value(), atom, atomValues)
+		}
+		return mp
+	case reflect.Struct:
+		switch v.Type() {
+		case timeType:
+			return v.Interface().(time.Time).UTC().Format(time.RFC3339)
+		case dmodelsTimeType:
+			return v.Interface().(dmodels.Time).UTC().Format(time.RFC3339)
+		case decimalType:
+			d := v.Interface().(decimal.Decimal)
+			if atom {
+				return json.Number(d.Mul(uatomMul).Round(0).String())
+			}
+			return d
+		}
+		if v.Type().Implements(jsonMarshalerTyp) {
+			return v.Interface()
+		}
+		fields := make(map[string]interface{})
+		v2Fields(v, fields, atomValues)
+		return fields
+	}
+	return v.Interface()
+}
+
+// v2Fields puts the fields of the struct into the map by their json names, embedded structs are flattened
+func v2Fields(v reflect.Value, fields map[string]interface{}, atomValues bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		name := parts[0]
+		omitEmpty := false
+		for _, opt := range parts[1:] {
+			omitEmpty = omitEmpty || opt == "omitempty"
+		}
+		fv := v.Field(i)
+		if f.Anonymous && name == "" {
+			for fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					break
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				v2Fields(fv, fields, atomValues)
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if omitEmpty && fv.IsZero() {
+			continue
+		}
+		unit := f.Tag.Get(unitTag)
+		atom := unit == unitAtom || (atomValues && (unit == unitValue || t == aggItemType && f.Name == "Value"))
+		fields[name] = v2Value(fv, atom, atomValues)
+	}
+}
+
+// withV2Format makes handlers of the routes render responses in the v2 format
+func (api *API) withV2Format(routes []*Route) []*Route {
+	for _, r := range routes {
+		r.Middleware = append(r.Middleware, v2Format(r))
+	}
+	return routes
+}
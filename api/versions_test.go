@@ -0,0 +1,39 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/everstake/cosmoscan-api/smodels"
+	"github.com/shopspring/decimal"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestV2Value(t *testing.T) {
+	items := []smodels.AggItem{{
+		Time:  dmodels.NewTime(time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)),
+		Value: decimal.RequireFromString("1.5000004"),
+	}}
+	tests := []struct {
+		data       interface{}
+		atomValues bool
+		expected   string
+	}{
+		{data: items, atomValues: true, expected: `[{"time":"2021-01-02T03:04:05Z","value":1500000}]`},
+		{data: items, atomValues: false, expected: `[{"time":"2021-01-02T03:04:05Z","value":"1.5000004"}]`},
+		{
+			data:     smodels.Balance{SelfDelegated: decimal.NewFromInt(2)},
+			expected: `{"available":0,"other_delegated":0,"self_delegated":2000000}`,
+		},
+	}
+	for i, test := range tests {
+		b, err := json.Marshal(v2Value(reflect.ValueOf(test.data), false, test.atomValues))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != test.expected {
+			t.Errorf("%d: expected %s, got %s", i, test.expected, string(b))
+		}
+	}
+}
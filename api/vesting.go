@@ -0,0 +1,36 @@
+package api
+
+import (
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/everstake/cosmoscan-api/log"
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+func (api *API) GetAccountVesting(w http.ResponseWriter, r *http.Request) {
+	address, ok := mux.Vars(r)["address"]
+	if !ok || address == "" {
+		jsonBadRequest(w, "invalid address")
+		return
+	}
+	var filter struct {
+		Time dmodels.Time `schema:"time"`
+	}
+	err := api.queryDecoder.Decode(&filter, r.URL.Query())
+	if err != nil {
+		log.Debug("API GetAccountVesting: Decode: %s", err.Error())
+		jsonBadRequest(w, "")
+		return
+	}
+	resp, err := api.svc.GetAccountVesting(address, filter.Time.Time)
+	if err != nil {
+		log.Error("API GetAccountVesting: svc.GetAccountVesting: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) GetVestingSchedule(w http.ResponseWriter, r *http.Request) {
+	api.aggHandler(w, r, api.svc.GetVestingSchedule)
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/log"
+	"github.com/everstake/cosmoscan-api/smodels"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+)
+
+func (api *API) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	resp, err := api.svc.GetWebhooks(requestAPIKey(r).ID)
+	if err != nil {
+		log.Error("API GetWebhooks: svc.GetWebhooks: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	params, ok := decodeWebhookParams(w, r)
+	if !ok {
+		return
+	}
+	resp, err := api.svc.CreateWebhook(requestAPIKey(r).ID, params)
+	if err != nil {
+		log.Error("API CreateWebhook: svc.CreateWebhook: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		jsonBadRequest(w, "invalid id")
+		return
+	}
+	resp, err := api.svc.GetWebhook(requestAPIKey(r).ID, id)
+	if err != nil {
+		log.Error("API GetWebhook: svc.GetWebhook: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		jsonBadRequest(w, "invalid id")
+		return
+	}
+	params, ok := decodeWebhookParams(w, r)
+	if !ok {
+		return
+	}
+	resp, err := api.svc.UpdateWebhook(requestAPIKey(r).ID, id, params)
+	if err != nil {
+		log.Error("API UpdateWebhook: svc.UpdateWebhook: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func (api *API) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		jsonBadRequest(w, "invalid id")
+		return
+	}
+	err = api.svc.DeleteWebhook(requestAPIKey(r).ID, id)
+	if err != nil {
+		log.Error("API DeleteWebhook: svc.DeleteWebhook: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, map[string]bool{"deleted": true})
+}
+
+func (api *API) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	var filter filters.WebhookDeliveries
+	err := api.queryDecoder.Decode(&filter, r.URL.Query())
+	if err != nil {
+		log.Debug("API GetWebhookDeliveries: Decode: %s", err.Error())
+		jsonBadRequest(w, "")
+		return
+	}
+	filter.WebhookID, err = strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		jsonBadRequest(w, "invalid id")
+		return
+	}
+	resp, err := api.svc.GetWebhookDeliveries(requestAPIKey(r).ID, filter)
+	if err != nil {
+		log.Error("API GetWebhookDeliveries: svc.GetWebhookDeliveries: %s", err.Error())
+		jsonError(w, err)
+		return
+	}
+	jsonData(w, resp)
+}
+
+func decodeWebhookParams(w http.ResponseWriter, r *http.Request) (params smodels.WebhookParams, ok bool) {
+	err := json.NewDecoder(r.Body).Decode(&params)
+	if err != nil {
+		log.Debug("API Decode: %s", err.Error())
+		jsonBadRequest(w, "invalid body")
+		return params, false
+	}
+	if params.URL == "" {
+		jsonBadRequest(w, "url is required")
+		return params, false
+	}
+	return params, true
+}
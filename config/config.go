@@ -11,6 +11,12 @@ const (
 	ServiceName = "cosmoscan-api"
 	configPath  = "./config.json"
 	Currency    = "atom"
+
+	// QuoteCurrency is the default currency of the fiat values
+	QuoteCurrency = "usd"
+
+	CacheBackendMemory = "memory"
+	CacheBackendRedis  = "redis"
 )
 
 type (
@@ -20,6 +26,12 @@ type (
 		Clickhouse            Clickhouse `json:"clickhouse"`
 		Parser                Parser     `json:"parser"`
 		CMCKey                string     `json:"cmc_key"`
+		// Currencies are the quote currencies of the atom price besides usd, fiat (eur, ...) or crypto (btc, ...)
+		Currencies            []string   `json:"currencies"`
+		Cache                 Cache      `json:"cache"`
+		// LabelsFile is a json or csv file of address labels imported on startup
+		LabelsFile            string     `json:"labels_file"`
+		Webhooks              Webhooks   `json:"webhooks"`
 	}
 	Parser struct {
 		Node     string `json:"node"`
@@ -27,8 +39,27 @@ type (
 		Fetchers uint64 `json:"fetchers"`
 	}
 	API struct {
-		Port         string   `json:"port"`
-		AllowedHosts []string `json:"allowed_hosts"`
+		Port         string    `json:"port"`
+		AllowedHosts []string  `json:"allowed_hosts"`
+		AdminToken   string    `json:"admin_token"`
+		RateLimit    RateLimit `json:"rate_limit"`
+		// timeouts of the http server in seconds
+		ReadTimeout     uint64 `json:"read_timeout"`
+		WriteTimeout    uint64 `json:"write_timeout"`
+		IdleTimeout     uint64 `json:"idle_timeout"`
+		ShutdownTimeout uint64 `json:"shutdown_timeout"`
+	}
+	// RateLimit rates are set in requests (cost units) per second, bursts in requests. Rates are limited by each
+	// replica on its own, daily quotas of the api keys are shared via the database: once a minute each replica
+	// flushes its usage and reloads the usage of all replicas, so a key can exceed its quota by the usage
+	// on the other replicas which is not synced yet, up to about two minutes of it
+	RateLimit struct {
+		Enabled           bool    `json:"enabled"`
+		TrustProxyHeaders bool    `json:"trust_proxy_headers"`
+		IPRate            float64 `json:"ip_rate"`
+		IPBurst           uint64  `json:"ip_burst"`
+		KeyRate           float64 `json:"key_rate"`
+		KeyBurst          uint64  `json:"key_burst"`
 	}
 	Mysql struct {
 		Host     string `json:"host"`
@@ -37,6 +68,23 @@ type (
 		User     string `json:"user"`
 		Password string `json:"password"`
 	}
+	// Webhooks urls of local and private network hosts are rejected unless AllowPrivateHosts is set (for development),
+	// Timeout of the delivery is set in seconds
+	Webhooks struct {
+		AllowPrivateHosts bool   `json:"allow_private_hosts"`
+		Timeout           uint64 `json:"timeout"`
+	}
+	// Cache backend is "memory" (default) or "redis"
+	Cache struct {
+		Backend string `json:"backend"`
+		Redis   Redis  `json:"redis"`
+	}
+	Redis struct {
+		Address  string `json:"address"`
+		Password string `json:"password"`
+		DB       int    `json:"db"`
+		Prefix   string `json:"prefix"`
+	}
 	Clickhouse struct {
 		Protocol string `json:"protocol"`
 		Host     string `json:"host"`
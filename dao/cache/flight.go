@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+)
+
+// errLoadPanicked is returned to the callers waiting for a load which has panicked
+var errLoadPanicked = errors.New("cache: load panicked")
+
+type (
+	// flight makes concurrent loads of the same key share a single call
+	flight struct {
+		mu    *sync.Mutex
+		calls map[string]*call
+	}
+	call struct {
+		wg   *sync.WaitGroup
+		data interface{}
+		err  error
+	}
+)
+
+func newFlight() *flight {
+	return &flight{
+		mu:    &sync.Mutex{},
+		calls: make(map[string]*call),
+	}
+}
+
+func (f *flight) do(key string, fn func() (interface{}, error)) (interface{}, error) {
+	f.mu.Lock()
+	if c, ok := f.calls[key]; ok {
+		f.mu.Unlock()
+		c.wg.Wait()
+		return c.data, c.err
+	}
+	c := &call{wg: &sync.WaitGroup{}, err: errLoadPanicked}
+	c.wg.Add(1)
+	f.calls[key] = c
+	f.mu.Unlock()
+
+	// the waiters are released even if fn panics, the panic goes on to the caller
+	defer func() {
+		f.mu.Lock()
+		delete(f.calls, key)
+		f.mu.Unlock()
+		c.wg.Done()
+	}()
+	c.data, c.err = fn()
+	return c.data, c.err
+}
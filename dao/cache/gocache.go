@@ -6,12 +6,14 @@ import (
 )
 
 type Cache struct {
-	cache *cache.Cache
+	cache  *cache.Cache
+	flight *flight
 }
 
 func New() *Cache {
 	return &Cache{
-		cache: cache.New(5*time.Minute, 10*time.Minute),
+		cache:  cache.New(5*time.Minute, 10*time.Minute),
+		flight: newFlight(),
 	}
 }
 
@@ -22,3 +24,22 @@ func (c *Cache) CacheSet(key string, data interface{}, duration time.Duration) {
 func (c *Cache) CacheGet(key string) (data interface{}, found bool) {
 	return c.cache.Get(key)
 }
+
+// CacheLoad returns the cached value or calls load once for all concurrent callers and caches its result
+func (c *Cache) CacheLoad(key string, duration time.Duration, load func() (interface{}, error)) (data interface{}, err error) {
+	data, found := c.cache.Get(key)
+	if found {
+		return data, nil
+	}
+	return c.flight.do(key, func() (interface{}, error) {
+		if data, found := c.cache.Get(key); found {
+			return data, nil
+		}
+		data, err := load()
+		if err != nil {
+			return nil, err
+		}
+		c.cache.Set(key, data, duration)
+		return data, nil
+	})
+}
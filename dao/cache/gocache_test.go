@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheLoad(t *testing.T) {
+	c := New()
+	var loads int32
+	load := func() (interface{}, error) {
+		atomic.AddInt32(&loads, 1)
+		<-time.After(time.Millisecond * 50)
+		return []string{"value"}, nil
+	}
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, err := c.CacheLoad("key", time.Minute, load)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if v := data.([]string); len(v) != 1 || v[0] != "value" {
+				t.Errorf("unexpected value: %v", v)
+			}
+		}()
+	}
+	wg.Wait()
+	if loads != 1 {
+		t.Errorf("expected a single load, got %d", loads)
+	}
+	if _, found := c.CacheGet("key"); !found {
+		t.Error("loaded value must be cached")
+	}
+}
+
+func TestFlightPanic(t *testing.T) {
+	f := newFlight()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected the panic to reach the caller")
+			}
+		}()
+		_, _ = f.do("key", func() (interface{}, error) {
+			close(started)
+			<-release
+			panic("load")
+		})
+	}()
+	<-started
+	done := make(chan error)
+	go func() {
+		_, err := f.do("key", func() (interface{}, error) {
+			return nil, nil
+		})
+		done <- err
+	}()
+	// give the waiter time to join the call in flight
+	<-time.After(time.Millisecond * 50)
+	close(release)
+	select {
+	case err := <-done:
+		if err != nil && !errors.Is(err, errLoadPanicked) {
+			t.Errorf("unexpected error: %s", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the waiter is blocked after the panic")
+	}
+	if _, err := f.do("key", func() (interface{}, error) { return "value", nil }); err != nil {
+		t.Errorf("the key must be loadable after the panic: %s", err)
+	}
+}
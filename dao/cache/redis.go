@@ -0,0 +1,179 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"github.com/everstake/cosmoscan-api/config"
+	"github.com/everstake/cosmoscan-api/log"
+	"github.com/gomodule/redigo/redis"
+	"time"
+)
+
+const (
+	defaultExpiration = 5 * time.Minute
+	lockSuffix        = ":lock"
+	// lockTTL bounds how long other replicas wait for the one that loads a missing value
+	lockTTL      = 30 * time.Second
+	lockPollStep = 100 * time.Millisecond
+)
+
+type (
+	// Redis is a cache shared between replicas, values are gob encoded so their types have to be registered with Register
+	Redis struct {
+		pool   *redis.Pool
+		prefix string
+		flight *flight
+	}
+	envelope struct {
+		Value interface{}
+	}
+)
+
+// Register makes the types of the given values known to the serialization of the redis cache
+func Register(values ...interface{}) {
+	for _, v := range values {
+		gob.Register(v)
+	}
+}
+
+func NewRedis(cfg config.Redis) (*Redis, error) {
+	pool := &redis.Pool{
+		MaxIdle:     10,
+		IdleTimeout: 5 * time.Minute,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", cfg.Address,
+				redis.DialPassword(cfg.Password),
+				redis.DialDatabase(cfg.DB),
+				redis.DialConnectTimeout(5*time.Second),
+				redis.DialReadTimeout(5*time.Second),
+				redis.DialWriteTimeout(5*time.Second),
+			)
+		},
+		TestOnBorrow: func(c redis.Conn, t time.Time) error {
+			if time.Since(t) < time.Minute {
+				return nil
+			}
+			_, err := c.Do("PING")
+			return err
+		},
+	}
+	conn := pool.Get()
+	defer conn.Close()
+	_, err := conn.Do("PING")
+	if err != nil {
+		return nil, fmt.Errorf("PING: %w", err)
+	}
+	return &Redis{
+		pool:   pool,
+		prefix: cfg.Prefix,
+		flight: newFlight(),
+	}, nil
+}
+
+func (c *Redis) CacheSet(key string, data interface{}, duration time.Duration) {
+	err := c.set(key, data, duration)
+	if err != nil {
+		log.Error("Redis: CacheSet %s: %s", key, err.Error())
+	}
+}
+
+func (c *Redis) CacheGet(key string) (data interface{}, found bool) {
+	data, found, err := c.get(key)
+	if err != nil {
+		log.Error("Redis: CacheGet %s: %s", key, err.Error())
+		return nil, false
+	}
+	return data, found
+}
+
+// CacheLoad returns the cached value or loads it. Only one caller in the process and, by means of a lock key,
+// only one replica loads the value at a time, others wait for the result to appear in the cache.
+func (c *Redis) CacheLoad(key string, duration time.Duration, load func() (interface{}, error)) (data interface{}, err error) {
+	data, found := c.CacheGet(key)
+	if found {
+		return data, nil
+	}
+	return c.flight.do(key, func() (interface{}, error) {
+		locked, err := c.lock(key)
+		if err != nil {
+			log.Error("Redis: CacheLoad: lock %s: %s", key, err.Error())
+		}
+		if !locked && err == nil {
+			for deadline := time.Now().Add(lockTTL); time.Now().Before(deadline); {
+				<-time.After(lockPollStep)
+				if data, found := c.CacheGet(key); found {
+					return data, nil
+				}
+			}
+		}
+		if locked {
+			defer c.unlock(key)
+		}
+		data, err := load()
+		if err != nil {
+			return nil, err
+		}
+		c.CacheSet(key, data, duration)
+		return data, nil
+	})
+}
+
+func (c *Redis) set(key string, data interface{}, duration time.Duration) error {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(envelope{Value: data})
+	if err != nil {
+		return fmt.Errorf("gob.Encode: %w", err)
+	}
+	conn := c.pool.Get()
+	defer conn.Close()
+	if duration == 0 {
+		duration = defaultExpiration
+	}
+	if duration < 0 {
+		_, err = conn.Do("SET", c.prefix+key, buf.Bytes())
+	} else {
+		_, err = conn.Do("SET", c.prefix+key, buf.Bytes(), "PX", duration.Milliseconds())
+	}
+	return err
+}
+
+func (c *Redis) get(key string) (data interface{}, found bool, err error) {
+	conn := c.pool.Get()
+	defer conn.Close()
+	b, err := redis.Bytes(conn.Do("GET", c.prefix+key))
+	if err == redis.ErrNil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	var e envelope
+	err = gob.NewDecoder(bytes.NewReader(b)).Decode(&e)
+	if err != nil {
+		return nil, false, fmt.Errorf("gob.Decode: %w", err)
+	}
+	return e.Value, true, nil
+}
+
+func (c *Redis) lock(key string) (bool, error) {
+	conn := c.pool.Get()
+	defer conn.Close()
+	_, err := redis.String(conn.Do("SET", c.prefix+key+lockSuffix, 1, "NX", "PX", lockTTL.Milliseconds()))
+	if err == redis.ErrNil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *Redis) unlock(key string) {
+	conn := c.pool.Get()
+	defer conn.Close()
+	_, err := conn.Do("DEL", c.prefix+key+lockSuffix)
+	if err != nil {
+		log.Error("Redis: unlock %s: %s", key, err.Error())
+	}
+}
@@ -0,0 +1,92 @@
+package clickhouse
+
+import (
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/config"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/jmoiron/sqlx"
+	"strings"
+)
+
+func (db DB) GetAccountEvents(filter filters.AccountEvents, fn func(event dmodels.AccountEvent) error) error {
+	// the fee is charged to the payer of the transaction, the transactions parsed before the payer was stored
+	// (or with an unknown key of the signer) fall back to the senders of the transfers and the delegators
+	signed := fmt.Sprintf(
+		"(trn_fee_payer = ? OR trn_fee_payer = '' AND trn_hash IN (SELECT trf_tx_hash FROM %s WHERE trf_from = ? UNION ALL SELECT dlg_tx_hash FROM %s WHERE dlg_delegator = ? UNION ALL SELECT der_tx_hash FROM %s WHERE der_delegator = ?))",
+		dmodels.TransfersTable, dmodels.DelegationsTable, dmodels.DelegatorRewardsTable,
+	)
+	parts := []struct {
+		columns    []string
+		table      string
+		timeColumn string
+		where      squirrel.Sqlizer
+	}{
+		{
+			columns:    []string{fmt.Sprintf("'%s' AS type", dmodels.AccountEventTransferOut), "trf_tx_hash AS tx_hash", "trf_amount AS amount", "trf_currency AS currency", "toString(trf_to) AS counterparty", "trf_created_at AS created_at"},
+			table:      dmodels.TransfersTable,
+			timeColumn: "trf_created_at",
+			where:      squirrel.Eq{"trf_from": filter.Address},
+		},
+		{
+			columns:    []string{fmt.Sprintf("'%s'", dmodels.AccountEventTransferIn), "trf_tx_hash", "trf_amount", "trf_currency", "toString(trf_from)", "trf_created_at"},
+			table:      dmodels.TransfersTable,
+			timeColumn: "trf_created_at",
+			where:      squirrel.Eq{"trf_to": filter.Address},
+		},
+		// a redelegation is a single event of its source row, the destination row is skipped
+		{
+			columns:    []string{fmt.Sprintf("multiIf(dlg_counterparty != '', '%s', dlg_amount > 0, '%s', '%s')", dmodels.AccountEventRedelegate, dmodels.AccountEventDelegate, dmodels.AccountEventUndelegate), "dlg_tx_hash", "abs(dlg_amount)", fmt.Sprintf("'%s'", config.Currency), "if(dlg_counterparty != '', dlg_counterparty, toString(dlg_validator))", "dlg_created_at"},
+			table:      dmodels.DelegationsTable,
+			timeColumn: "dlg_created_at",
+			where:      squirrel.And{squirrel.Eq{"dlg_delegator": filter.Address}, squirrel.Expr("NOT (dlg_amount > 0 AND dlg_counterparty != '')")},
+		},
+		{
+			columns:    []string{fmt.Sprintf("'%s'", dmodels.AccountEventReward), "der_tx_hash", "der_amount", fmt.Sprintf("'%s'", config.Currency), "toString(der_validator)", "der_created_at"},
+			table:      dmodels.DelegatorRewardsTable,
+			timeColumn: "der_created_at",
+			where:      squirrel.Eq{"der_delegator": filter.Address},
+		},
+		{
+			columns:    []string{fmt.Sprintf("'%s'", dmodels.AccountEventFee), "trn_hash", "trn_fee", fmt.Sprintf("'%s'", config.Currency), "''", "trn_created_at"},
+			table:      dmodels.TransactionsTable,
+			timeColumn: "trn_created_at",
+			where:      squirrel.Expr(signed, filter.Address, filter.Address, filter.Address, filter.Address),
+		},
+	}
+	var queries []string
+	var args []interface{}
+	for _, part := range parts {
+		q := squirrel.Select(part.columns...).From(part.table).Where(part.where)
+		q = filter.Query(part.timeColumn, q)
+		sql, partArgs, err := q.ToSql()
+		if err != nil {
+			return err
+		}
+		queries = append(queries, sql)
+		args = append(args, partArgs...)
+	}
+	events := strings.Join(queries, " UNION ALL ")
+
+	query := fmt.Sprintf("SELECT type, tx_hash, amount, currency, counterparty, created_at, toDecimal64(0, 8) AS price FROM (%s) ORDER BY created_at", events)
+	if filter.Fiat {
+		prices := fmt.Sprintf("SELECT toStartOfHour(prc_time) AS hour, avg(prc_price) AS price FROM %s WHERE prc_currency = ? GROUP BY hour", dmodels.PricesTable)
+		query = fmt.Sprintf(
+			"SELECT type, tx_hash, amount, currency, counterparty, created_at, price FROM (SELECT *, toStartOfHour(created_at) AS hour FROM (%s)) ANY LEFT JOIN (%s) USING hour ORDER BY created_at",
+			events, prices,
+		)
+		args = append(args, filter.Currency)
+	}
+
+	return db.Stream(squirrel.Expr(query, args...), func(rows *sqlx.Rows) error {
+		var event dmodels.AccountEvent
+		err := rows.StructScan(&event)
+		if err != nil {
+			return err
+		}
+		event.TxHash = strings.TrimRight(event.TxHash, "\x00")
+		event.Counterparty = strings.TrimRight(event.Counterparty, "\x00")
+		return fn(event)
+	})
+}
@@ -3,6 +3,7 @@ package clickhouse
 import (
 	"fmt"
 	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/everstake/cosmoscan-api/dao/filters"
 	"github.com/everstake/cosmoscan-api/dmodels"
 )
@@ -52,10 +53,10 @@ func (db DB) CreateAccountTxs(accountTxs []dmodels.AccountTx) error {
 	q := squirrel.Insert(dmodels.AccountTxsTable).Columns("atx_account", "atx_tx_hash")
 	for _, acc := range accountTxs {
 		if acc.Account == "" {
-			return fmt.Errorf("field Account can not beempty")
+			return derrors.InvalidArgument("field Account can not beempty")
 		}
 		if acc.TxHash == "" {
-			return fmt.Errorf("hash can not be empty")
+			return derrors.InvalidArgument("hash can not be empty")
 		}
 		q = q.Values(acc.Account, acc.TxHash)
 	}
@@ -3,6 +3,7 @@ package clickhouse
 import (
 	"fmt"
 	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/everstake/cosmoscan-api/dao/filters"
 	"github.com/everstake/cosmoscan-api/dmodels"
 )
@@ -11,21 +12,27 @@ func (db DB) CreateBalanceUpdates(updates []dmodels.BalanceUpdate) error {
 	if len(updates) == 0 {
 		return nil
 	}
-	q := squirrel.Insert(dmodels.BalanceUpdatesTable).Columns("bau_id", "bau_address", "bau_stake", "bau_balance", "bau_unbonding", "bau_created_at")
+	q := squirrel.Insert(dmodels.BalanceUpdatesTable).Columns("bau_id", "bau_address", "bau_stake", "bau_balance", "bau_unbonding", "bau_rewards", "bau_created_at")
 	for _, update := range updates {
 		if update.ID == "" {
-			return fmt.Errorf("field ProposalID can not be empty")
+			return derrors.InvalidArgument("field ProposalID can not be empty")
 		}
 		if update.CreatedAt.IsZero() {
-			return fmt.Errorf("field CreatedAt can not be 0")
+			return derrors.InvalidArgument("field CreatedAt can not be 0")
 		}
-		q = q.Values(update.ID, update.Address, update.Stake, update.Balance, update.Unbonding, update.CreatedAt)
+		q = q.Values(update.ID, update.Address, update.Stake, update.Balance, update.Unbonding, update.Rewards, update.CreatedAt)
 	}
 	return db.Insert(q)
 }
 
 func (db DB) GetBalanceUpdate(filter filters.BalanceUpdates) (updates []dmodels.BalanceUpdate, err error) {
 	q := squirrel.Select("*").From(dmodels.BalanceUpdatesTable).OrderBy("bau_created_at desc")
+	if filter.Address != "" {
+		q = q.Where(squirrel.Eq{"bau_address": filter.Address})
+	}
+	if !filter.To.IsZero() {
+		q = q.Where(squirrel.LtOrEq{"bau_created_at": filter.To.Time})
+	}
 	if filter.Limit != 0 {
 		q = q.Limit(filter.Limit)
 	}
@@ -35,3 +42,38 @@ func (db DB) GetBalanceUpdate(filter filters.BalanceUpdates) (updates []dmodels.
 	err = db.Find(&updates, q)
 	return updates, err
 }
+
+// GetAggBalanceUpdates returns the last balance update of the address in each period with updates
+func (db DB) GetAggBalanceUpdates(filter filters.BalanceUpdatesAgg) (updates []dmodels.BalanceUpdate, err error) {
+	q := squirrel.Select(
+		"argMax(bau_balance, bau_created_at) as balance",
+		"argMax(bau_stake, bau_created_at) as stake",
+		"argMax(bau_unbonding, bau_created_at) as unbonding",
+		"argMax(bau_rewards, bau_created_at) as rewards",
+		fmt.Sprintf("toDateTime(%s(bau_created_at)) AS time", filter.AggFunc()),
+	).From(dmodels.BalanceUpdatesTable).
+		Where(squirrel.Eq{"bau_address": filter.Address}).
+		GroupBy("time")
+	if !filter.From.IsZero() {
+		q = q.Where(squirrel.GtOrEq{"bau_created_at": filter.From.Time})
+	}
+	if !filter.To.IsZero() {
+		q = q.Where(squirrel.LtOrEq{"bau_created_at": filter.To.Time})
+	}
+	q = squirrel.Select(
+		"balance as bau_balance",
+		"stake as bau_stake",
+		"unbonding as bau_unbonding",
+		"rewards as bau_rewards",
+		"time as bau_created_at",
+	).FromSelect(q, "t").OrderBy("bau_created_at")
+	err = db.Find(&updates, q)
+	return updates, err
+}
+
+// GetBalanceUpdatesAddresses returns the addresses which have at least one balance update
+func (db DB) GetBalanceUpdatesAddresses() (addresses []string, err error) {
+	q := squirrel.Select("DISTINCT bau_address").From(dmodels.BalanceUpdatesTable)
+	err = db.Find(&addresses, q)
+	return addresses, err
+}
@@ -3,9 +3,9 @@ package clickhouse
 import (
 	"fmt"
 	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/everstake/cosmoscan-api/dao/filters"
 	"github.com/everstake/cosmoscan-api/dmodels"
-	"github.com/everstake/cosmoscan-api/smodels"
 )
 
 func (db DB) CreateBlocks(blocks []dmodels.Block) error {
@@ -15,16 +15,16 @@ func (db DB) CreateBlocks(blocks []dmodels.Block) error {
 	q := squirrel.Insert(dmodels.BlocksTable).Columns("blk_id", "blk_hash", "blk_proposer", "blk_created_at")
 	for _, block := range blocks {
 		if block.ID == 0 {
-			return fmt.Errorf("field ProposalID can not be 0")
+			return derrors.InvalidArgument("field ProposalID can not be 0")
 		}
 		if block.Hash == "" {
-			return fmt.Errorf("hash can not be empty")
+			return derrors.InvalidArgument("hash can not be empty")
 		}
 		if block.Proposer == "" {
-			return fmt.Errorf("proposer can not be empty")
+			return derrors.InvalidArgument("proposer can not be empty")
 		}
 		if block.CreatedAt.IsZero() {
-			return fmt.Errorf("field CreatedAt can not be 0")
+			return derrors.InvalidArgument("field CreatedAt can not be 0")
 		}
 		q = q.Values(block.ID, block.Hash, block.Proposer, block.CreatedAt)
 	}
@@ -49,38 +49,6 @@ func (db DB) GetBlocksCount(filter filters.Blocks) (total uint64, err error) {
 	return total, err
 }
 
-func (db DB) GetAggBlocksCount(filter filters.Agg) (items []smodels.AggItem, err error) {
-	q := filter.BuildQuery("toDecimal64(count(blk_id), 0)", "blk_created_at", dmodels.BlocksTable)
-	err = db.Find(&items, q)
-	return items, err
-}
-
-func (db DB) GetAggBlocksDelay(filter filters.Agg) (items []smodels.AggItem, err error) {
-	q := squirrel.Select(
-		"avg(toUnixTimestamp(b1.blk_created_at) - toUnixTimestamp(b2.blk_created_at)) as value",
-		fmt.Sprintf("toDateTime(%s(b1.blk_created_at)) AS time", filter.AggFunc()),
-	).From(fmt.Sprintf("%s as b1", dmodels.BlocksTable)).
-		JoinClause("JOIN blocks as b2 ON b1.blk_id = toUInt64(plus(b2.blk_id, 1))").
-		Where(squirrel.Gt{"b1.blk_id": 2}).
-		GroupBy("time").
-		OrderBy("time")
-
-	if !filter.From.IsZero() {
-		q = q.Where(squirrel.GtOrEq{"time": filter.From.Time})
-	}
-	if !filter.To.IsZero() {
-		q = q.Where(squirrel.LtOrEq{"time": filter.To.Time})
-	}
-	err = db.Find(&items, q)
-	return items, err
-}
-
-func (db DB) GetAggUniqBlockValidators(filter filters.Agg) (items []smodels.AggItem, err error) {
-	q := filter.BuildQuery("toDecimal64(count(DISTINCT blk_proposer), 0)", "blk_created_at", dmodels.BlocksTable)
-	err = db.Find(&items, q)
-	return items, err
-}
-
 func (db DB) GetAvgBlocksDelay(filter filters.TimeRange) (delay float64, err error) {
 	q := squirrel.Select(
 		"avg(toUnixTimestamp(b1.blk_created_at) - toUnixTimestamp(b2.blk_created_at)) as delay",
@@ -99,11 +67,29 @@ func (db DB) GetAvgBlocksDelay(filter filters.TimeRange) (delay float64, err err
 
 func (db DB) GetProposedBlocksTotal(filter filters.BlocksProposed) (total uint64, err error) {
 	q := squirrel.Select("count(*) as total").From(dmodels.BlocksTable)
+	q = proposedBlocksQuery(filter, q)
+	err = db.FindFirst(&total, q)
+	return total, err
+}
+
+func (db DB) GetProposedBlocksHeights(filter filters.BlocksProposed) (heights []uint64, err error) {
+	q := squirrel.Select("blk_id").From(dmodels.BlocksTable).OrderBy("blk_id")
+	q = proposedBlocksQuery(filter, q)
+	err = db.Find(&heights, q)
+	return heights, err
+}
+
+func proposedBlocksQuery(filter filters.BlocksProposed, q squirrel.SelectBuilder) squirrel.SelectBuilder {
 	if len(filter.Proposers) != 0 {
 		q = q.Where(squirrel.Eq{"blk_proposer": filter.Proposers})
 	}
-	err = db.FindFirst(&total, q)
-	return total, err
+	if filter.FromHeight != 0 {
+		q = q.Where(squirrel.GtOrEq{"blk_id": filter.FromHeight})
+	}
+	if filter.ToHeight != 0 {
+		q = q.Where(squirrel.LtOrEq{"blk_id": filter.ToHeight})
+	}
+	return q
 }
 
 func (db DB) GetTopProposedBlocksValidators() (items []dmodels.ValidatorValue, err error) {
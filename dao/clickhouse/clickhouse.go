@@ -2,9 +2,11 @@ package clickhouse
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/Masterminds/squirrel"
 	"github.com/everstake/cosmoscan-api/config"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/golang-migrate/migrate/v4"
 	goclickhouse "github.com/golang-migrate/migrate/v4/database/clickhouse"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
@@ -22,11 +24,11 @@ type DB struct {
 func NewDB(cfg config.Clickhouse) (*DB, error) {
 	conn, err := sql.Open("clickhouse", makeSource(cfg))
 	if err != nil {
-		return nil, fmt.Errorf("can`t make connection: %s", err.Error())
+		return nil, fmt.Errorf("can`t make connection: %w", err)
 	}
 	//err = makeMigration(conn, migrationsPath, cfg.Database)
 	//if err != nil {
-	//	return nil, fmt.Errorf("can`t make makeMigration: %s", err.Error())
+	//	return nil, fmt.Errorf("can`t make makeMigration: %w", err)
 	//}
 	return &DB{
 		conn: sqlx.NewDb(conn, "clickhouse"),
@@ -39,11 +41,11 @@ func (db *DB) Find(dest interface{}, b squirrel.SelectBuilder) error {
 		return err
 	}
 	err = db.conn.Select(dest, q, params...)
-	if err == sql.ErrNoRows {
+	if errors.Is(err, sql.ErrNoRows) {
 		return nil
 	}
 	if err != nil {
-		return err
+		return derrors.FromConnection(err)
 	}
 	return nil
 }
@@ -54,12 +56,35 @@ func (db *DB) FindFirst(dest interface{}, b squirrel.SelectBuilder) error {
 		return err
 	}
 	err = db.conn.Get(dest, q, params...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return derrors.ErrNotFound
+	}
 	if err != nil {
-		return err
+		return derrors.FromConnection(err)
 	}
 	return nil
 }
 
+// Stream executes the query and passes rows to fn one by one without loading the whole result into memory
+func (db *DB) Stream(b squirrel.Sqlizer, fn func(rows *sqlx.Rows) error) error {
+	q, params, err := b.ToSql()
+	if err != nil {
+		return err
+	}
+	rows, err := db.conn.Queryx(q, params...)
+	if err != nil {
+		return derrors.FromConnection(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		err = fn(rows)
+		if err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
 func (db *DB) Insert(b squirrel.InsertBuilder) error {
 	q, params, err := b.ToSql()
 	if err != nil {
@@ -67,7 +92,7 @@ func (db *DB) Insert(b squirrel.InsertBuilder) error {
 	}
 	_, err = db.conn.Exec(q, params...)
 	if err != nil {
-		return err
+		return derrors.FromConnection(err)
 	}
 	return nil
 }
@@ -86,13 +111,13 @@ func makeSource(cfg config.Clickhouse) string {
 func makeMigration(conn *sql.DB, migrationDir string, dbName string) error {
 	driver, err := goclickhouse.WithInstance(conn, &goclickhouse.Config{})
 	if err != nil {
-		return fmt.Errorf("clickhouse.WithInstance: %s", err.Error())
+		return fmt.Errorf("clickhouse.WithInstance: %w", err)
 	}
 	mg, err := migrate.NewWithDatabaseInstance(
 		fmt.Sprintf("file://%s", migrationDir),
 		dbName, driver)
 	if err != nil {
-		return fmt.Errorf("migrate.NewWithDatabaseInstance: %s", err.Error())
+		return fmt.Errorf("migrate.NewWithDatabaseInstance: %w", err)
 	}
 	if err := mg.Up(); err != nil {
 		if err != migrate.ErrNoChange {
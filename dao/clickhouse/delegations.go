@@ -3,6 +3,7 @@ package clickhouse
 import (
 	"fmt"
 	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/everstake/cosmoscan-api/dao/filters"
 	"github.com/everstake/cosmoscan-api/dmodels"
 	"github.com/everstake/cosmoscan-api/smodels"
@@ -13,24 +14,24 @@ func (db DB) CreateDelegations(delegations []dmodels.Delegation) error {
 	if len(delegations) == 0 {
 		return nil
 	}
-	q := squirrel.Insert(dmodels.DelegationsTable).Columns("dlg_id", "dlg_tx_hash", "dlg_delegator", "dlg_validator", "dlg_amount", "dlg_created_at")
+	q := squirrel.Insert(dmodels.DelegationsTable).Columns("dlg_id", "dlg_tx_hash", "dlg_delegator", "dlg_validator", "dlg_amount", "dlg_counterparty", "dlg_created_at")
 	for _, delegation := range delegations {
 		if delegation.ID == "" {
-			return fmt.Errorf("field ProposalID can not be empty")
+			return derrors.InvalidArgument("field ProposalID can not be empty")
 		}
 		if delegation.TxHash == "" {
-			return fmt.Errorf("field TxHash can not be empty")
+			return derrors.InvalidArgument("field TxHash can not be empty")
 		}
 		if delegation.Delegator == "" {
-			return fmt.Errorf("field Delegator can not be empty")
+			return derrors.InvalidArgument("field Delegator can not be empty")
 		}
 		if delegation.Validator == "" {
-			return fmt.Errorf("field Validator can not be empty")
+			return derrors.InvalidArgument("field Validator can not be empty")
 		}
 		if delegation.CreatedAt.IsZero() {
-			return fmt.Errorf("field CreatedAt can not be zero")
+			return derrors.InvalidArgument("field CreatedAt can not be zero")
 		}
-		q = q.Values(delegation.ID, delegation.TxHash, delegation.Delegator, delegation.Validator, delegation.Amount, delegation.CreatedAt)
+		q = q.Values(delegation.ID, delegation.TxHash, delegation.Delegator, delegation.Validator, delegation.Amount, delegation.Counterparty, delegation.CreatedAt)
 	}
 	return db.Insert(q)
 }
@@ -154,3 +155,76 @@ func (db DB) GetValidatorDelegatorsTotal(filter filters.ValidatorDelegators) (to
 	err = db.FindFirst(&total, q)
 	return total, err
 }
+
+// GetDelegationStakes returns the stake of each delegator per validator at the filter.To moment along with the first delegation time
+func (db DB) GetDelegationStakes(filter filters.DelegationStakes) (items []dmodels.DelegationStake, err error) {
+	q := squirrel.Select("dlg_delegator as delegator", "dlg_validator as validator", "sum(dlg_amount) as amount", "min(dlg_created_at) as since").
+		From(dmodels.DelegationsTable).
+		GroupBy("dlg_delegator", "dlg_validator").
+		Having(squirrel.Gt{"amount": 0})
+	if len(filter.Delegators) != 0 {
+		q = q.Where(squirrel.Eq{"dlg_delegator": filter.Delegators})
+	}
+	if len(filter.Validators) != 0 {
+		q = q.Where(squirrel.Eq{"dlg_validator": filter.Validators})
+	}
+	if !filter.To.IsZero() {
+		q = q.Where(squirrel.LtOrEq{"dlg_created_at": filter.To.Time})
+	}
+	err = db.Find(&items, q)
+	return items, err
+}
+
+// GetValidatorsStake returns the total delegated stake of each validator at the filter.To moment
+func (db DB) GetValidatorsStake(filter filters.DelegationStakes) (items []dmodels.DelegationStake, err error) {
+	q := squirrel.Select("dlg_validator as validator", "sum(dlg_amount) as amount").
+		From(dmodels.DelegationsTable).
+		GroupBy("dlg_validator").
+		Having(squirrel.Gt{"amount": 0})
+	if len(filter.Delegators) != 0 {
+		q = q.Where(squirrel.Eq{"dlg_delegator": filter.Delegators})
+	}
+	if len(filter.Validators) != 0 {
+		q = q.Where(squirrel.Eq{"dlg_validator": filter.Validators})
+	}
+	if !filter.To.IsZero() {
+		q = q.Where(squirrel.LtOrEq{"dlg_created_at": filter.To.Time})
+	}
+	err = db.Find(&items, q)
+	return items, err
+}
+
+// GetRedelegations returns the redelegations of the delegator, a redelegation is stored as the pair of
+// the negative source and the positive destination delegations, the source row keeps the destination validator.
+// The rows parsed before the counterparty was stored are paired by the transaction and the amount
+func (db DB) GetRedelegations(filter filters.Redelegations) (items []dmodels.Redelegation, err error) {
+	query := `SELECT tx_hash, validator_src, validator_dst, amount, created_at FROM (
+	SELECT dlg_tx_hash as tx_hash, toString(dlg_validator) as validator_src, dlg_counterparty as validator_dst, -dlg_amount as amount, dlg_created_at as created_at
+	FROM delegations
+	WHERE dlg_delegator = ? AND dlg_amount < 0 AND dlg_counterparty != ''
+	UNION ALL
+	SELECT tx_hash, validator_src, validator_dst, amount, created_at FROM
+	(SELECT dlg_tx_hash as tx_hash, toString(dlg_validator) as validator_src, -dlg_amount as amount, dlg_created_at as created_at
+	FROM delegations
+	WHERE dlg_delegator = ? AND dlg_amount < 0 AND dlg_counterparty = '') as t1
+	ALL INNER JOIN (
+		SELECT dlg_tx_hash as tx_hash, toString(dlg_validator) as validator_dst, dlg_amount as amount
+		FROM delegations
+		WHERE dlg_delegator = ? AND dlg_amount > 0 AND dlg_counterparty = ''
+	) as t2 USING (tx_hash, amount)
+	WHERE validator_src != validator_dst
+	)
+	ORDER BY created_at DESC`
+	if filter.Limit != 0 {
+		query = fmt.Sprintf("%s LIMIT %d", query, filter.Limit)
+	}
+	if filter.Offset != 0 {
+		query = fmt.Sprintf("%s OFFSET %d", query, filter.Offset)
+	}
+	q, args, err := squirrel.Expr(query, filter.Delegator, filter.Delegator, filter.Delegator).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	err = db.conn.Select(&items, q, args...)
+	return items, err
+}
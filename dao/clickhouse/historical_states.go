@@ -43,11 +43,17 @@ func (db DB) CreateHistoricalStates(states []dmodels.HistoricalState) error {
 
 func (db DB) GetHistoricalStates(filter filters.HistoricalState) (states []dmodels.HistoricalState, err error) {
 	q := squirrel.Select("*").From(dmodels.HistoricalStates).OrderBy("his_created_at desc")
+	if !filter.From.IsZero() {
+		q = q.Where(squirrel.GtOrEq{"his_created_at": filter.From.Time})
+	}
+	if !filter.To.IsZero() {
+		q = q.Where(squirrel.LtOrEq{"his_created_at": filter.To.Time})
+	}
 	if filter.Limit != 0 {
 		q = q.Limit(filter.Limit)
 	}
 	if filter.Offset != 0 {
-		q = q.Limit(filter.Offset)
+		q = q.Offset(filter.Offset)
 	}
 	err = db.Find(&states, q)
 	return states, err
@@ -1,8 +1,8 @@
 package clickhouse
 
 import (
-	"fmt"
 	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/everstake/cosmoscan-api/dao/filters"
 	"github.com/everstake/cosmoscan-api/dmodels"
 )
@@ -20,14 +20,20 @@ func (db DB) CreateHistoryProposals(proposals []dmodels.HistoryProposal) error {
 		"hpr_amount",
 		"hpr_init_deposit",
 		"hpr_proposer",
+		"hpr_metadata",
+		"hpr_messages",
+		"hpr_contents",
 		"hpr_created_at",
 	)
 	for _, proposal := range proposals {
 		if proposal.ID == 0 {
-			return fmt.Errorf("field ProposalID can not be 0")
+			return derrors.InvalidArgument("field ProposalID can not be 0")
 		}
 		if proposal.CreatedAt.IsZero() {
-			return fmt.Errorf("field CreatedAt can not be zero")
+			return derrors.InvalidArgument("field CreatedAt can not be zero")
+		}
+		if proposal.Messages == "" {
+			proposal.Messages = "[]"
 		}
 		q = q.Values(
 			proposal.ID,
@@ -38,6 +44,9 @@ func (db DB) CreateHistoryProposals(proposals []dmodels.HistoryProposal) error {
 			proposal.Amount,
 			proposal.InitDeposit,
 			proposal.Proposer,
+			proposal.Metadata,
+			proposal.Messages,
+			proposal.Contents,
 			proposal.CreatedAt,
 		)
 	}
@@ -1,8 +1,8 @@
 package clickhouse
 
 import (
-	"fmt"
 	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/everstake/cosmoscan-api/dmodels"
 )
 
@@ -13,13 +13,13 @@ func (db DB) CreateJailers(jailers []dmodels.Jailer) error {
 	q := squirrel.Insert(dmodels.JailersTable).Columns("jlr_id", "jlr_address", "jlr_created_at")
 	for _, jailer := range jailers {
 		if jailer.ID == "" {
-			return fmt.Errorf("field ProposalID can not be empty")
+			return derrors.InvalidArgument("field ProposalID can not be empty")
 		}
 		if jailer.Address == "" {
-			return fmt.Errorf("field Address can not be empty")
+			return derrors.InvalidArgument("field Address can not be empty")
 		}
 		if jailer.CreatedAt.IsZero() {
-			return fmt.Errorf("field CreatedAt can not be zero")
+			return derrors.InvalidArgument("field CreatedAt can not be zero")
 		}
 		q = q.Values(jailer.ID, jailer.Address, jailer.CreatedAt)
 	}
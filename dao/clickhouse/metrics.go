@@ -0,0 +1,64 @@
+package clickhouse
+
+import (
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/everstake/cosmoscan-api/smodels"
+	"github.com/shopspring/decimal"
+)
+
+// GetMetric returns the values of the metric aggregated per time bucket
+func (db DB) GetMetric(metric dmodels.Metric, filter filters.Metric) (items []smodels.AggItem, err error) {
+	q := metricQuery(metric, filter).
+		Column(filter.BucketColumn(metric.TimeColumn)).
+		GroupBy("time").
+		OrderBy("time")
+	err = db.Find(&items, q)
+	return items, err
+}
+
+// GetMetricTotal returns the value of the metric aggregated over the whole time range, zero without any rows
+func (db DB) GetMetricTotal(metric dmodels.Metric, filter filters.Metric) (total decimal.Decimal, err error) {
+	var totals []decimal.Decimal
+	q := metricQuery(metric, filter).Having("count() > 0")
+	err = db.Find(&totals, q)
+	if err != nil || len(totals) == 0 {
+		return total, err
+	}
+	return totals[0], nil
+}
+
+func metricQuery(metric dmodels.Metric, filter filters.Metric) squirrel.SelectBuilder {
+	q := squirrel.Select(fmt.Sprintf("%s AS value", metricAggValue(filter.Agg, metric.Value))).From(metric.Table)
+	column := filter.RangeColumn(metric)
+	if !filter.From.IsZero() {
+		q = q.Where(squirrel.GtOrEq{column: filter.From.Time})
+	}
+	if !filter.To.IsZero() {
+		q = q.Where(squirrel.LtOrEq{column: filter.To.Time})
+	}
+	if metric.Where != "" {
+		q = q.Where(metric.Where)
+	}
+	return q
+}
+
+// metricAggValue keeps the aggregated values decimal to be scanned into AggItem
+func metricAggValue(agg string, value string) string {
+	switch agg {
+	case dmodels.MetricAggCount:
+		return fmt.Sprintf("toDecimal64(count(%s), 0)", value)
+	case dmodels.MetricAggUniq:
+		return fmt.Sprintf("toDecimal64(uniqExact(%s), 0)", value)
+	case dmodels.MetricAggP50:
+		return fmt.Sprintf("toDecimal128(quantile(0.5)(%s), 8)", value)
+	case dmodels.MetricAggP95:
+		return fmt.Sprintf("toDecimal128(quantile(0.95)(%s), 8)", value)
+	case dmodels.MetricAggP99:
+		return fmt.Sprintf("toDecimal128(quantile(0.99)(%s), 8)", value)
+	default:
+		return fmt.Sprintf("%s(%s)", agg, value)
+	}
+}
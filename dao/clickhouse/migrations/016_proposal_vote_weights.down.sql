@@ -0,0 +1,5 @@
+ALTER TABLE proposal_votes
+    DROP COLUMN IF EXISTS prv_weight_yes,
+    DROP COLUMN IF EXISTS prv_weight_abstain,
+    DROP COLUMN IF EXISTS prv_weight_no,
+    DROP COLUMN IF EXISTS prv_weight_no_with_veto;
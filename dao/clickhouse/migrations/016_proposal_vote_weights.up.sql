@@ -0,0 +1,5 @@
+ALTER TABLE proposal_votes
+    ADD COLUMN IF NOT EXISTS prv_weight_yes          Decimal128(18) DEFAULT toDecimal128(prv_option = 'Yes', 18),
+    ADD COLUMN IF NOT EXISTS prv_weight_abstain      Decimal128(18) DEFAULT toDecimal128(prv_option = 'Abstain', 18),
+    ADD COLUMN IF NOT EXISTS prv_weight_no           Decimal128(18) DEFAULT toDecimal128(prv_option = 'No', 18),
+    ADD COLUMN IF NOT EXISTS prv_weight_no_with_veto Decimal128(18) DEFAULT toDecimal128(prv_option = 'NoWithVeto', 18);
@@ -0,0 +1,3 @@
+ALTER TABLE history_proposals
+    DROP COLUMN IF EXISTS hpr_metadata,
+    DROP COLUMN IF EXISTS hpr_messages;
@@ -0,0 +1,3 @@
+ALTER TABLE history_proposals
+    ADD COLUMN IF NOT EXISTS hpr_metadata String DEFAULT '',
+    ADD COLUMN IF NOT EXISTS hpr_messages String DEFAULT '[]';
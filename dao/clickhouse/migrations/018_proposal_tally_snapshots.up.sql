@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS proposal_tally_snapshots
+(
+    pts_id                FixedString(40),
+    pts_proposal_id       UInt64,
+    pts_yes               Decimal128(18),
+    pts_abstain           Decimal128(18),
+    pts_no                Decimal128(18),
+    pts_no_with_veto      Decimal128(18),
+    pts_bonded            Decimal128(18),
+    pts_turnout           Decimal(5, 2),
+    pts_quorum_reached    UInt8,
+    pts_threshold_reached UInt8,
+    pts_vetoed            UInt8,
+    pts_projected_outcome String,
+    pts_quorum_needed     Decimal128(18),
+    pts_yes_needed        Decimal128(18),
+    pts_veto_needed       Decimal128(18),
+    pts_created_at        DateTime
+) ENGINE ReplacingMergeTree()
+      PARTITION BY toYYYYMM(pts_created_at)
+      ORDER BY (pts_id);
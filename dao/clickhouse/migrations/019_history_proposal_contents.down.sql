@@ -0,0 +1,2 @@
+ALTER TABLE history_proposals
+    DROP COLUMN IF EXISTS hpr_contents;
@@ -0,0 +1,2 @@
+ALTER TABLE history_proposals
+    ADD COLUMN IF NOT EXISTS hpr_contents String DEFAULT '[]';
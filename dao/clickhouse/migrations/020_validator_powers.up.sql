@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS validator_powers
+(
+    vlp_id                FixedString(40),
+    vlp_height            UInt64,
+    vlp_validator         FixedString(40),
+    vlp_power             Decimal128(18),
+    vlp_proposer_priority Int64,
+    vlp_created_at        DateTime
+) ENGINE ReplacingMergeTree()
+      PARTITION BY toYYYYMM(vlp_created_at)
+      ORDER BY (vlp_id);
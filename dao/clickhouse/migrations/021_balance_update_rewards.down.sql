@@ -0,0 +1 @@
+ALTER TABLE balance_updates DROP COLUMN IF EXISTS bau_rewards;
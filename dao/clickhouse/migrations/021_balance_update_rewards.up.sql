@@ -0,0 +1 @@
+ALTER TABLE balance_updates ADD COLUMN IF NOT EXISTS bau_rewards Decimal(20, 8) AFTER bau_unbonding;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS supply_snapshots
+(
+    sup_id              FixedString(40),
+    sup_total           Decimal128(18),
+    sup_liquid          Decimal128(18),
+    sup_bonded          Decimal128(18),
+    sup_unbonding       Decimal128(18),
+    sup_community_pool  Decimal128(18),
+    sup_module_accounts Decimal128(18),
+    sup_vesting_locked  Decimal128(18),
+    sup_circulating     Decimal128(18),
+    sup_created_at      DateTime
+) ENGINE ReplacingMergeTree()
+      PARTITION BY toYYYYMM(sup_created_at)
+      ORDER BY (sup_id);
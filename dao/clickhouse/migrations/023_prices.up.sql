@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS prices
+(
+    prc_id       FixedString(40),
+    prc_currency String,
+    prc_price    Decimal128(18),
+    prc_volume   Decimal128(18),
+    prc_source   String,
+    prc_time     DateTime
+) ENGINE ReplacingMergeTree()
+      PARTITION BY toYYYYMM(prc_time)
+      ORDER BY (prc_id);
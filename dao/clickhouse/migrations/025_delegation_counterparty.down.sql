@@ -0,0 +1 @@
+ALTER TABLE delegations DROP COLUMN IF EXISTS dlg_counterparty;
@@ -0,0 +1 @@
+ALTER TABLE delegations ADD COLUMN IF NOT EXISTS dlg_counterparty String AFTER dlg_amount;
@@ -1,8 +1,8 @@
 package clickhouse
 
 import (
-	"fmt"
 	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/everstake/cosmoscan-api/dao/filters"
 	"github.com/everstake/cosmoscan-api/dmodels"
 )
@@ -14,16 +14,16 @@ func (db DB) CreateMissedBlocks(blocks []dmodels.MissedBlock) error {
 	q := squirrel.Insert(dmodels.MissedBlocks).Columns("mib_id", "mib_height", "mib_validator", "mib_created_at")
 	for _, block := range blocks {
 		if block.ID == "" {
-			return fmt.Errorf("field ProposalID can not be empty")
+			return derrors.InvalidArgument("field ProposalID can not be empty")
 		}
 		if block.Height == 0 {
-			return fmt.Errorf("field ProposalID can not be zero")
+			return derrors.InvalidArgument("field ProposalID can not be zero")
 		}
 		if block.Validator == "" {
-			return fmt.Errorf("field Validator can not be empty")
+			return derrors.InvalidArgument("field Validator can not be empty")
 		}
 		if block.CreatedAt.IsZero() {
-			return fmt.Errorf("field CreatedAt can not be 0")
+			return derrors.InvalidArgument("field CreatedAt can not be 0")
 		}
 		q = q.Values(block.ID, block.Height, block.Validator, block.CreatedAt)
 	}
@@ -31,10 +31,38 @@ func (db DB) CreateMissedBlocks(blocks []dmodels.MissedBlock) error {
 }
 
 func (db DB) GetMissedBlocksCount(filter filters.MissedBlocks) (total uint64, err error) {
-	q := squirrel.Select("count(*) as total").From(dmodels.MissedBlocks)
+	q := squirrel.Select("uniqExact(mib_height) as total").From(dmodels.MissedBlocks)
+	q = missedBlocksQuery(filter, q)
+	err = db.FindFirst(&total, q)
+	return total, err
+}
+
+func (db DB) GetMissedBlocksHeights(filter filters.MissedBlocks) (heights []uint64, err error) {
+	q := squirrel.Select("DISTINCT mib_height").From(dmodels.MissedBlocks).OrderBy("mib_height")
+	q = missedBlocksQuery(filter, q)
+	err = db.Find(&heights, q)
+	return heights, err
+}
+
+// GetValidatorsMissedBlocks returns the number of missed blocks per validator consensus address
+func (db DB) GetValidatorsMissedBlocks(filter filters.MissedBlocks) (items []dmodels.ValidatorValue, err error) {
+	q := squirrel.Select("uniqExact(mib_height) as value", "mib_validator as validator").
+		From(dmodels.MissedBlocks).
+		GroupBy("validator")
+	q = missedBlocksQuery(filter, q)
+	err = db.Find(&items, q)
+	return items, err
+}
+
+func missedBlocksQuery(filter filters.MissedBlocks, q squirrel.SelectBuilder) squirrel.SelectBuilder {
 	if len(filter.Validators) != 0 {
 		q = q.Where(squirrel.Eq{"mib_validator": filter.Validators})
 	}
-	err = db.FindFirst(&total, q)
-	return total, err
+	if filter.FromHeight != 0 {
+		q = q.Where(squirrel.GtOrEq{"mib_height": filter.FromHeight})
+	}
+	if filter.ToHeight != 0 {
+		q = q.Where(squirrel.LtOrEq{"mib_height": filter.ToHeight})
+	}
+	return q
 }
@@ -0,0 +1,95 @@
+package clickhouse
+
+import (
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/everstake/cosmoscan-api/smodels"
+	"strings"
+)
+
+func (db DB) CreatePrices(prices []dmodels.Price) error {
+	if len(prices) == 0 {
+		return nil
+	}
+	q := squirrel.Insert(dmodels.PricesTable).Columns(
+		"prc_id",
+		"prc_currency",
+		"prc_price",
+		"prc_volume",
+		"prc_source",
+		"prc_time",
+	)
+	for _, p := range prices {
+		if p.ID == "" {
+			return derrors.InvalidArgument("field ID can not be empty")
+		}
+		if p.Currency == "" {
+			return derrors.InvalidArgument("field Currency can not be empty")
+		}
+		if p.Time.IsZero() {
+			return derrors.InvalidArgument("field Time can not be zero")
+		}
+		q = q.Values(
+			p.ID,
+			p.Currency,
+			p.Price,
+			p.Volume,
+			p.Source,
+			p.Time.Time,
+		)
+	}
+	return db.Insert(q)
+}
+
+// GetPrices returns the prices from the latest one
+func (db DB) GetPrices(filter filters.Prices) (prices []dmodels.Price, err error) {
+	q := squirrel.Select("*").From(dmodels.PricesTable).OrderBy("prc_time desc")
+	if filter.Currency != "" {
+		q = q.Where(squirrel.Eq{"prc_currency": filter.Currency})
+	}
+	if !filter.From.IsZero() {
+		q = q.Where(squirrel.GtOrEq{"prc_time": filter.From.Time})
+	}
+	if !filter.To.IsZero() {
+		q = q.Where(squirrel.LtOrEq{"prc_time": filter.To.Time})
+	}
+	if filter.Limit != 0 {
+		q = q.Limit(filter.Limit)
+	}
+	if filter.Offset != 0 {
+		q = q.Offset(filter.Offset)
+	}
+	err = db.Find(&prices, q)
+	if err != nil {
+		return nil, err
+	}
+	for i := range prices {
+		prices[i].ID = strings.TrimRight(prices[i].ID, "\x00")
+	}
+	return prices, nil
+}
+
+// GetAggPrices returns the average price of every period
+func (db DB) GetAggPrices(filter filters.PricesAgg) (items []smodels.AggItem, err error) {
+	q := squirrel.Select(
+		"avg(prc_price) AS value",
+		fmt.Sprintf("toDateTime(%s(prc_time)) AS time", filter.AggFunc()),
+	).From(dmodels.PricesTable).
+		Where(squirrel.Eq{"prc_currency": filter.Currency}).
+		GroupBy("time").
+		OrderBy("time")
+	if !filter.From.IsZero() {
+		q = q.Where(squirrel.GtOrEq{"prc_time": filter.From.Time})
+	}
+	if !filter.To.IsZero() {
+		q = q.Where(squirrel.LtOrEq{"prc_time": filter.To.Time})
+	}
+	err = db.Find(&items, q)
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -1,8 +1,8 @@
 package clickhouse
 
 import (
-	"fmt"
 	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/everstake/cosmoscan-api/dao/filters"
 	"github.com/everstake/cosmoscan-api/dmodels"
 )
@@ -14,13 +14,13 @@ func (db DB) CreateProposalDeposits(deposits []dmodels.ProposalDeposit) error {
 	q := squirrel.Insert(dmodels.ProposalDepositsTable).Columns("prd_id", "prd_proposal_id", "prd_depositor", "prd_amount", "prd_created_at")
 	for _, deposit := range deposits {
 		if deposit.ID == "" {
-			return fmt.Errorf("field ProposalID can not be empty")
+			return derrors.InvalidArgument("field ProposalID can not be empty")
 		}
 		if deposit.ProposalID == 0 {
-			return fmt.Errorf("field ProposalID can not be zero")
+			return derrors.InvalidArgument("field ProposalID can not be zero")
 		}
 		if deposit.CreatedAt.IsZero() {
-			return fmt.Errorf("field CreatedAt can not be zero")
+			return derrors.InvalidArgument("field CreatedAt can not be zero")
 		}
 		q = q.Values(deposit.ID, deposit.ProposalID, deposit.Depositor, deposit.Amount, deposit.CreatedAt)
 	}
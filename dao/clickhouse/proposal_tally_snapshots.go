@@ -0,0 +1,71 @@
+package clickhouse
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+)
+
+func (db DB) CreateProposalTallySnapshots(snapshots []dmodels.ProposalTallySnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	q := squirrel.Insert(dmodels.ProposalTallySnapshotsTable).Columns(
+		"pts_id",
+		"pts_proposal_id",
+		"pts_yes",
+		"pts_abstain",
+		"pts_no",
+		"pts_no_with_veto",
+		"pts_bonded",
+		"pts_turnout",
+		"pts_quorum_reached",
+		"pts_threshold_reached",
+		"pts_vetoed",
+		"pts_projected_outcome",
+		"pts_quorum_needed",
+		"pts_yes_needed",
+		"pts_veto_needed",
+		"pts_created_at",
+	)
+	for _, s := range snapshots {
+		if s.ID == "" {
+			return derrors.InvalidArgument("field ID can not be empty")
+		}
+		if s.ProposalID == 0 {
+			return derrors.InvalidArgument("field ProposalID can not be zero")
+		}
+		if s.CreatedAt.IsZero() {
+			return derrors.InvalidArgument("field CreatedAt can not be zero")
+		}
+		q = q.Values(
+			s.ID,
+			s.ProposalID,
+			s.Yes,
+			s.Abstain,
+			s.No,
+			s.NoWithVeto,
+			s.Bonded,
+			s.Turnout,
+			s.QuorumReached,
+			s.ThresholdReached,
+			s.Vetoed,
+			s.ProjectedOutcome,
+			s.QuorumNeeded,
+			s.YesNeeded,
+			s.VetoNeeded,
+			s.CreatedAt.Time,
+		)
+	}
+	return db.Insert(q)
+}
+
+func (db DB) GetProposalTallySnapshots(filter filters.ProposalTallySnapshots) (snapshots []dmodels.ProposalTallySnapshot, err error) {
+	q := squirrel.Select("*").From(dmodels.ProposalTallySnapshotsTable).
+		Where(squirrel.Eq{"pts_proposal_id": filter.ProposalID}).
+		OrderBy("pts_created_at")
+	q = filter.Query("pts_created_at", q)
+	err = db.Find(&snapshots, q)
+	return snapshots, err
+}
@@ -3,33 +3,64 @@ package clickhouse
 import (
 	"fmt"
 	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/everstake/cosmoscan-api/dao/filters"
 	"github.com/everstake/cosmoscan-api/dmodels"
 	"github.com/everstake/cosmoscan-api/smodels"
 )
 
+// voteWeightColumns maps vote options to the columns holding their weights
+var voteWeightColumns = map[string]string{
+	dmodels.VoteOptionYes:        "prv_weight_yes",
+	dmodels.VoteOptionAbstain:    "prv_weight_abstain",
+	dmodels.VoteOptionNo:         "prv_weight_no",
+	dmodels.VoteOptionNoWithVeto: "prv_weight_no_with_veto",
+}
+
 func (db DB) CreateProposalVotes(votes []dmodels.ProposalVote) error {
 	if len(votes) == 0 {
 		return nil
 	}
-	q := squirrel.Insert(dmodels.ProposalVotesTable).Columns("prv_id", "prv_proposal_id", "prv_voter", "prv_tx_hash", "prv_option", "prv_created_at")
+	q := squirrel.Insert(dmodels.ProposalVotesTable).Columns(
+		"prv_id",
+		"prv_proposal_id",
+		"prv_voter",
+		"prv_tx_hash",
+		"prv_option",
+		"prv_weight_yes",
+		"prv_weight_abstain",
+		"prv_weight_no",
+		"prv_weight_no_with_veto",
+		"prv_created_at",
+	)
 	for _, vote := range votes {
 		if vote.ID == "" {
-			return fmt.Errorf("field ProposalID can not be empty")
+			return derrors.InvalidArgument("field ProposalID can not be empty")
 		}
 		if vote.ProposalID == 0 {
-			return fmt.Errorf("field ProposalID can not be zero")
+			return derrors.InvalidArgument("field ProposalID can not be zero")
 		}
 		if vote.Voter == "" {
-			return fmt.Errorf("field Voter can not be empty")
+			return derrors.InvalidArgument("field Voter can not be empty")
 		}
 		if vote.TxHash == "" {
-			return fmt.Errorf("field TxHash can not be empty")
+			return derrors.InvalidArgument("field TxHash can not be empty")
 		}
 		if vote.CreatedAt.IsZero() {
-			return fmt.Errorf("field CreatedAt can not be zero")
+			return derrors.InvalidArgument("field CreatedAt can not be zero")
 		}
-		q = q.Values(vote.ID, vote.ProposalID, vote.Voter, vote.TxHash, vote.Option, vote.CreatedAt)
+		q = q.Values(
+			vote.ID,
+			vote.ProposalID,
+			vote.Voter,
+			vote.TxHash,
+			vote.Option,
+			vote.WeightYes,
+			vote.WeightAbstain,
+			vote.WeightNo,
+			vote.WeightNoWithVeto,
+			vote.CreatedAt,
+		)
 	}
 	return db.Insert(q)
 }
@@ -42,6 +73,13 @@ func (db DB) GetProposalVotes(filter filters.ProposalVotes) (votes []dmodels.Pro
 	if len(filter.Voters) != 0 {
 		q = q.Where(squirrel.Eq{"prv_voter": filter.Voters})
 	}
+	if filter.Option != "" {
+		column, ok := voteWeightColumns[filter.Option]
+		if !ok {
+			return nil, derrors.InvalidArgument("unknown vote option")
+		}
+		q = q.Where(squirrel.Gt{column: 0})
+	}
 	if filter.Limit != 0 {
 		q = q.Limit(filter.Limit)
 	}
@@ -52,10 +90,19 @@ func (db DB) GetProposalVotes(filter filters.ProposalVotes) (votes []dmodels.Pro
 	return votes, err
 }
 
-func (db DB) GetAggProposalVotes(filter filters.Agg, id []uint64) (items []smodels.AggItem, err error) {
-	q := filter.BuildQuery("toDecimal64(count(*), 0)", "prv_created_at", dmodels.ProposalVotesTable)
-	if len(id) != 0 {
-		q = q.Where(squirrel.Eq{"prv_proposal_id": id})
+// GetAggProposalVotes returns the number of votes, split votes are counted by the weight of filter.Option if it is set
+func (db DB) GetAggProposalVotes(filter filters.ProposalVotesAgg) (items []smodels.AggItem, err error) {
+	value := "toDecimal64(count(*), 0)"
+	if filter.Option != "" {
+		column, ok := voteWeightColumns[filter.Option]
+		if !ok {
+			return nil, derrors.InvalidArgument("unknown vote option")
+		}
+		value = fmt.Sprintf("sum(%s)", column)
+	}
+	q := filter.BuildQuery(value, "prv_created_at", dmodels.ProposalVotesTable)
+	if len(filter.ProposalID) != 0 {
+		q = q.Where(squirrel.Eq{"prv_proposal_id": filter.ProposalID})
 	}
 	err = db.Find(&items, q)
 	return items, err
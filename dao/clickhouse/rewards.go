@@ -1,8 +1,9 @@
 package clickhouse
 
 import (
-	"fmt"
 	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
+	"github.com/everstake/cosmoscan-api/dao/filters"
 	"github.com/everstake/cosmoscan-api/dmodels"
 )
 
@@ -13,19 +14,19 @@ func (db DB) CreateDelegatorRewards(rewards []dmodels.DelegatorReward) error {
 	q := squirrel.Insert(dmodels.DelegatorRewardsTable).Columns("der_id", "der_tx_hash", "der_delegator", "der_validator", "der_amount", "der_created_at")
 	for _, reward := range rewards {
 		if reward.ID == "" {
-			return fmt.Errorf("field ProposalID can not be empty")
+			return derrors.InvalidArgument("field ProposalID can not be empty")
 		}
 		if reward.TxHash == "" {
-			return fmt.Errorf("field TxHash can not be empty")
+			return derrors.InvalidArgument("field TxHash can not be empty")
 		}
 		if reward.Delegator == "" {
-			return fmt.Errorf("field Delegator can not be empty")
+			return derrors.InvalidArgument("field Delegator can not be empty")
 		}
 		if reward.Validator == "" {
-			return fmt.Errorf("field Validator can not be empty")
+			return derrors.InvalidArgument("field Validator can not be empty")
 		}
 		if reward.CreatedAt.IsZero() {
-			return fmt.Errorf("field CreatedAt can not be zero")
+			return derrors.InvalidArgument("field CreatedAt can not be zero")
 		}
 		q = q.Values(reward.ID, reward.TxHash, reward.Delegator, reward.Validator, reward.Amount, reward.CreatedAt)
 	}
@@ -39,18 +40,47 @@ func (db DB) CreateValidatorRewards(rewards []dmodels.ValidatorReward) error {
 	q := squirrel.Insert(dmodels.ValidatorRewardsTable).Columns("var_id", "var_tx_hash", "var_address", "var_amount", "var_created_at")
 	for _, reward := range rewards {
 		if reward.ID == "" {
-			return fmt.Errorf("field ProposalID can not be empty")
+			return derrors.InvalidArgument("field ProposalID can not be empty")
 		}
 		if reward.TxHash == "" {
-			return fmt.Errorf("field TxHash can not be empty")
+			return derrors.InvalidArgument("field TxHash can not be empty")
 		}
 		if reward.Address == "" {
-			return fmt.Errorf("field Address can not be empty")
+			return derrors.InvalidArgument("field Address can not be empty")
 		}
 		if reward.CreatedAt.IsZero() {
-			return fmt.Errorf("field CreatedAt can not be zero")
+			return derrors.InvalidArgument("field CreatedAt can not be zero")
 		}
 		q = q.Values(reward.ID, reward.TxHash, reward.Address, reward.Amount, reward.CreatedAt)
 	}
 	return db.Insert(q)
 }
+
+// GetValidatorsDelegatorRewards returns the rewards withdrawn by the delegators of each validator
+func (db DB) GetValidatorsDelegatorRewards(filter filters.ValidatorRewards) (items []dmodels.ValidatorAmount, err error) {
+	q := squirrel.Select("der_validator as validator", "sum(der_amount) as amount").
+		From(dmodels.DelegatorRewardsTable).
+		GroupBy("der_validator")
+	if len(filter.Validators) != 0 {
+		q = q.Where(squirrel.Eq{"der_validator": filter.Validators})
+	}
+	if len(filter.Delegators) != 0 {
+		q = q.Where(squirrel.Eq{"der_delegator": filter.Delegators})
+	}
+	q = filter.TimeRange.Query("der_created_at", q)
+	err = db.Find(&items, q)
+	return items, err
+}
+
+// GetValidatorsCommissions returns the commission withdrawn by each validator
+func (db DB) GetValidatorsCommissions(filter filters.ValidatorRewards) (items []dmodels.ValidatorAmount, err error) {
+	q := squirrel.Select("var_address as validator", "sum(var_amount) as amount").
+		From(dmodels.ValidatorRewardsTable).
+		GroupBy("var_address")
+	if len(filter.Validators) != 0 {
+		q = q.Where(squirrel.Eq{"var_address": filter.Validators})
+	}
+	q = filter.TimeRange.Query("var_created_at", q)
+	err = db.Find(&items, q)
+	return items, err
+}
@@ -1,8 +1,8 @@
 package clickhouse
 
 import (
-	"fmt"
 	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/everstake/cosmoscan-api/dao/filters"
 	"github.com/everstake/cosmoscan-api/dmodels"
 	"github.com/everstake/cosmoscan-api/smodels"
@@ -15,13 +15,13 @@ func (db DB) CreateStats(stats []dmodels.Stat) (err error) {
 	q := squirrel.Insert(dmodels.StatsTable).Columns("stt_id", "stt_title", "stt_value", "stt_created_at")
 	for _, stat := range stats {
 		if stat.ID == "" {
-			return fmt.Errorf("field ProposalID can not be empty")
+			return derrors.InvalidArgument("field ProposalID can not be empty")
 		}
 		if stat.Title == "" {
-			return fmt.Errorf("field Title can not be empty")
+			return derrors.InvalidArgument("field Title can not be empty")
 		}
 		if stat.CreatedAt.IsZero() {
-			return fmt.Errorf("field CreatedAt can not be zero")
+			return derrors.InvalidArgument("field CreatedAt can not be zero")
 		}
 		q = q.Values(stat.ID, stat.Title, stat.Value, stat.CreatedAt)
 	}
@@ -43,16 +43,20 @@ func (db DB) GetStats(filter filters.Stats) (stats []dmodels.Stat, err error) {
 	return stats, err
 }
 
+// aggStatValue is the max of the values of the period, the values are stored as strings
+// and compared as numbers, so "9" is not above "10"
+const aggStatValue = "max(toDecimal128OrZero(stt_value, 18))"
+
 func (db DB) GetAggValidators33Power(filter filters.Agg) (items []smodels.AggItem, err error) {
-	q := filter.BuildQuery("max(stt_value)", "stt_created_at", dmodels.StatsTable).
+	q := filter.BuildQuery(aggStatValue, "stt_created_at", dmodels.StatsTable).
 		Where(squirrel.Eq{"stt_title": dmodels.StatsValidatorsWith33Power})
 	err = db.Find(&items, q)
 	return items, err
 }
 
-func (db DB) GetAggWhaleAccounts(filter filters.Agg) (items []smodels.AggItem, err error) {
-	q := filter.BuildQuery("max(stt_value)", "stt_created_at", dmodels.StatsTable).
-		Where(squirrel.Eq{"stt_title": dmodels.StatsTotalWhaleAccounts})
+func (db DB) GetAggStats(filter filters.StatsAgg) (items []smodels.AggItem, err error) {
+	q := filter.BuildQuery(aggStatValue, "stt_created_at", dmodels.StatsTable).
+		Where(squirrel.Eq{"stt_title": filter.Title})
 	err = db.Find(&items, q)
 	return items, err
 }
@@ -0,0 +1,69 @@
+package clickhouse
+
+import (
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+)
+
+func (db DB) CreateSupplySnapshots(snapshots []dmodels.SupplySnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	q := squirrel.Insert(dmodels.SupplySnapshotsTable).Columns(
+		"sup_id",
+		"sup_total",
+		"sup_liquid",
+		"sup_bonded",
+		"sup_unbonding",
+		"sup_community_pool",
+		"sup_module_accounts",
+		"sup_vesting_locked",
+		"sup_circulating",
+		"sup_created_at",
+	)
+	for _, s := range snapshots {
+		if s.ID == "" {
+			return derrors.InvalidArgument("field ID can not be empty")
+		}
+		if s.CreatedAt.IsZero() {
+			return derrors.InvalidArgument("field CreatedAt can not be zero")
+		}
+		q = q.Values(
+			s.ID,
+			s.Total,
+			s.Liquid,
+			s.Bonded,
+			s.Unbonding,
+			s.CommunityPool,
+			s.ModuleAccounts,
+			s.VestingLocked,
+			s.Circulating,
+			s.CreatedAt.Time,
+		)
+	}
+	return db.Insert(q)
+}
+
+// GetAggSupplySnapshots returns the last supply snapshot of each period
+func (db DB) GetAggSupplySnapshots(filter filters.Agg) (snapshots []dmodels.SupplySnapshot, err error) {
+	fields := []string{"total", "liquid", "bonded", "unbonding", "community_pool", "module_accounts", "vesting_locked", "circulating"}
+	q := squirrel.Select(fmt.Sprintf("toDateTime(%s(sup_created_at)) AS time", filter.AggFunc())).
+		From(dmodels.SupplySnapshotsTable).
+		GroupBy("time")
+	outer := squirrel.Select("time as sup_created_at")
+	for _, field := range fields {
+		q = q.Column(fmt.Sprintf("argMax(sup_%s, sup_created_at) AS %s", field, field))
+		outer = outer.Column(fmt.Sprintf("%s AS sup_%s", field, field))
+	}
+	if !filter.From.IsZero() {
+		q = q.Where(squirrel.GtOrEq{"sup_created_at": filter.From.Time})
+	}
+	if !filter.To.IsZero() {
+		q = q.Where(squirrel.LtOrEq{"sup_created_at": filter.To.Time})
+	}
+	err = db.Find(&snapshots, outer.FromSelect(q, "t").OrderBy("sup_created_at"))
+	return snapshots, err
+}
@@ -3,6 +3,7 @@ package clickhouse
 import (
 	"fmt"
 	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/everstake/cosmoscan-api/dao/filters"
 	"github.com/everstake/cosmoscan-api/dmodels"
 	"github.com/everstake/cosmoscan-api/smodels"
@@ -19,19 +20,20 @@ func (db DB) CreateTransactions(transactions []dmodels.Transaction) error {
 		"trn_height",
 		"trn_messages",
 		"trn_fee",
+		"trn_fee_payer",
 		"trn_gas_used",
 		"trn_gas_wanted",
 		"trn_created_at",
 	)
 	for _, tx := range transactions {
 		if tx.Hash == "" {
-			return fmt.Errorf("field Hash can not be empty")
+			return derrors.InvalidArgument("field Hash can not be empty")
 		}
 		if tx.Height == 0 {
-			return fmt.Errorf("field Height can not be 0")
+			return derrors.InvalidArgument("field Height can not be 0")
 		}
 		if tx.CreatedAt.IsZero() {
-			return fmt.Errorf("field CreatedAt can not be zero")
+			return derrors.InvalidArgument("field CreatedAt can not be zero")
 		}
 		q = q.Values(
 			tx.Hash,
@@ -39,6 +41,7 @@ func (db DB) CreateTransactions(transactions []dmodels.Transaction) error {
 			tx.Height,
 			tx.Messages,
 			tx.Fee,
+			tx.FeePayer,
 			tx.GasUsed,
 			tx.GasWanted,
 			tx.CreatedAt,
@@ -47,18 +50,6 @@ func (db DB) CreateTransactions(transactions []dmodels.Transaction) error {
 	return db.Insert(q)
 }
 
-func (db DB) GetAggTransactionsFee(filter filters.Agg) (items []smodels.AggItem, err error) {
-	q := filter.BuildQuery("sum(trn_fee)", "trn_created_at", dmodels.TransactionsTable)
-	err = db.Find(&items, q)
-	return items, err
-}
-
-func (db DB) GetAggOperationsCount(filter filters.Agg) (items []smodels.AggItem, err error) {
-	q := filter.BuildQuery("toDecimal64(sum(trn_messages), 0)", "trn_created_at", dmodels.TransactionsTable)
-	err = db.Find(&items, q)
-	return items, err
-}
-
 func (db DB) GetTransactionsFeeVolume(filter filters.TimeRange) (total decimal.Decimal, err error) {
 	q := squirrel.Select("sum(trn_fee) as total").From(dmodels.TransactionsTable)
 	q = filter.Query("trn_created_at", q)
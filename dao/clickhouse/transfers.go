@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"github.com/Masterminds/squirrel"
 	"github.com/everstake/cosmoscan-api/config"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/everstake/cosmoscan-api/dao/filters"
 	"github.com/everstake/cosmoscan-api/dmodels"
 	"github.com/everstake/cosmoscan-api/smodels"
 	"github.com/shopspring/decimal"
+	"strings"
 )
 
 func (db DB) CreateTransfers(transfers []dmodels.Transfer) error {
@@ -17,20 +19,20 @@ func (db DB) CreateTransfers(transfers []dmodels.Transfer) error {
 	q := squirrel.Insert(dmodels.TransfersTable).Columns("trf_id", "trf_tx_hash", "trf_from", "trf_to", "trf_amount", "trf_created_at", "trf_currency")
 	for _, transfer := range transfers {
 		if transfer.ID == "" {
-			return fmt.Errorf("field ProposalID can not be empty")
+			return derrors.InvalidArgument("field ProposalID can not be empty")
 		}
 		if transfer.TxHash == "" {
-			return fmt.Errorf("field TxHash can not be empty")
+			return derrors.InvalidArgument("field TxHash can not be empty")
 		}
 		if transfer.CreatedAt.IsZero() {
-			return fmt.Errorf("field CreatedAt can not be zero")
+			return derrors.InvalidArgument("field CreatedAt can not be zero")
 		}
 		q = q.Values(transfer.ID, transfer.TxHash, transfer.From, transfer.To, transfer.Amount, transfer.CreatedAt, transfer.Currency)
 	}
 	return db.Insert(q)
 }
 
-func (db DB) GetAggTransfersVolume(filter filters.Agg) (items []smodels.AggItem, err error) {
+func (db DB) GetAggTransfersVolume(filter filters.TransfersAgg) (items []smodels.AggItem, err error) {
 	q := squirrel.Select(
 		"sum(trf_amount) AS value",
 		fmt.Sprintf("toDateTime(%s(trf_created_at)) AS time", filter.AggFunc()),
@@ -45,6 +47,19 @@ func (db DB) GetAggTransfersVolume(filter filters.Agg) (items []smodels.AggItem,
 	if !filter.To.IsZero() {
 		q = q.Where(squirrel.LtOrEq{"trf_created_at": filter.To.Time})
 	}
+	if len(filter.Addresses) != 0 {
+		// transfers between the addresses of the same set are not counted
+		in := squirrel.And{squirrel.Eq{"trf_to": filter.Addresses}, squirrel.NotEq{"trf_from": filter.Addresses}}
+		out := squirrel.And{squirrel.Eq{"trf_from": filter.Addresses}, squirrel.NotEq{"trf_to": filter.Addresses}}
+		switch filter.Direction {
+		case filters.TransfersDirectionIn:
+			q = q.Where(in)
+		case filters.TransfersDirectionOut:
+			q = q.Where(out)
+		default:
+			q = q.Where(squirrel.Or{in, out})
+		}
+	}
 	err = db.Find(&items, q)
 	return items, err
 }
@@ -58,3 +73,107 @@ func (db DB) GetTransferVolume(filter filters.TimeRange) (total decimal.Decimal,
 	err = db.FindFirst(&total, q)
 	return total, err
 }
+
+func (db DB) GetTransfers(filter filters.Transfers) (items []dmodels.Transfer, err error) {
+	q := squirrel.Select("*").From(dmodels.TransfersTable).OrderBy("trf_created_at desc", "trf_id")
+	q = transfersQuery(filter, q)
+	if filter.Limit != 0 {
+		q = q.Limit(filter.Limit)
+	}
+	if filter.Offset != 0 {
+		q = q.Offset(filter.Offset)
+	}
+	err = db.Find(&items, q)
+	for i := range items {
+		items[i].TxHash = strings.TrimRight(items[i].TxHash, "\x00")
+		items[i].From = strings.TrimRight(items[i].From, "\x00")
+		items[i].To = strings.TrimRight(items[i].To, "\x00")
+	}
+	return items, err
+}
+
+func (db DB) GetTransfersTotal(filter filters.Transfers) (total uint64, err error) {
+	q := squirrel.Select("count(*) as total").From(dmodels.TransfersTable)
+	q = transfersQuery(filter, q)
+	err = db.FindFirst(&total, q)
+	return total, err
+}
+
+func transfersQuery(filter filters.Transfers, q squirrel.SelectBuilder) squirrel.SelectBuilder {
+	q = q.Where("notEmpty(trf_from)")
+	q = filter.TimeRange.Query("trf_created_at", q)
+	if filter.Address != "" {
+		q = q.Where(squirrel.Or{squirrel.Eq{"trf_from": filter.Address}, squirrel.Eq{"trf_to": filter.Address}})
+	}
+	if filter.Sender != "" {
+		q = q.Where(squirrel.Eq{"trf_from": filter.Sender})
+	}
+	if filter.Recipient != "" {
+		q = q.Where(squirrel.Eq{"trf_to": filter.Recipient})
+	}
+	if filter.Currency != "" {
+		q = q.Where(squirrel.Eq{"trf_currency": filter.Currency})
+	}
+	if !filter.MinAmount.IsZero() {
+		q = q.Where(squirrel.GtOrEq{"trf_amount": filter.MinAmount})
+	}
+	if !filter.MaxAmount.IsZero() {
+		q = q.Where(squirrel.LtOrEq{"trf_amount": filter.MaxAmount})
+	}
+	return q
+}
+
+// GetCounterparties returns the addresses the address has transferred atoms with, ordered by the total volume
+func (db DB) GetCounterparties(filter filters.Counterparties) (items []dmodels.Counterparty, err error) {
+	out := squirrel.Select("trf_to as address", "trf_amount as sent", "toDecimal128(0, 18) as received").
+		From(dmodels.TransfersTable).
+		Where(squirrel.Eq{"trf_from": filter.Address, "trf_currency": config.Currency})
+	out = filter.TimeRange.Query("trf_created_at", out)
+	in := squirrel.Select("trf_from as address", "toDecimal128(0, 18) as sent", "trf_amount as received").
+		From(dmodels.TransfersTable).
+		Where(squirrel.Eq{"trf_to": filter.Address, "trf_currency": config.Currency}).
+		Where("notEmpty(trf_from)")
+	in = filter.TimeRange.Query("trf_created_at", in)
+	inSQL, inArgs, err := in.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	q := squirrel.Select("address", "sum(sent) as sent", "sum(received) as received", "count() as transfers").
+		FromSelect(out.Suffix("UNION ALL "+inSQL, inArgs...), "t").
+		Where(squirrel.NotEq{"address": filter.Address}).
+		GroupBy("address").
+		OrderBy("sent + received desc")
+	if filter.Limit != 0 {
+		q = q.Limit(filter.Limit)
+	}
+	err = db.Find(&items, q)
+	for i := range items {
+		items[i].Address = strings.TrimRight(items[i].Address, "\x00")
+	}
+	return items, err
+}
+
+// GetTransferEdges returns the largest totals of the transfers sent (or received) by each of the addresses
+func (db DB) GetTransferEdges(filter filters.TransferEdges) (items []dmodels.TransferEdge, err error) {
+	side, limitBy := "trf_from", "sender"
+	if filter.Direction == filters.TransfersDirectionIn {
+		side, limitBy = "trf_to", "recipient"
+	}
+	q := squirrel.Select("trf_from as sender", "trf_to as recipient", "sum(trf_amount) as amount", "count() as transfers").
+		From(dmodels.TransfersTable).
+		Where(squirrel.Eq{side: filter.Addresses, "trf_currency": config.Currency}).
+		Where("notEmpty(trf_from)").
+		Where("trf_from != trf_to").
+		GroupBy("sender", "recipient").
+		OrderBy("amount desc")
+	q = filter.TimeRange.Query("trf_created_at", q)
+	if filter.Limit != 0 {
+		q = q.Suffix(fmt.Sprintf("LIMIT %d BY %s", filter.Limit, limitBy))
+	}
+	err = db.Find(&items, q)
+	for i := range items {
+		items[i].Sender = strings.TrimRight(items[i].Sender, "\x00")
+		items[i].Recipient = strings.TrimRight(items[i].Recipient, "\x00")
+	}
+	return items, err
+}
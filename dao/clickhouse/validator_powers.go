@@ -0,0 +1,84 @@
+package clickhouse
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/everstake/cosmoscan-api/smodels"
+)
+
+func (db DB) CreateValidatorPowers(powers []dmodels.ValidatorPower) error {
+	if len(powers) == 0 {
+		return nil
+	}
+	q := squirrel.Insert(dmodels.ValidatorPowersTable).Columns(
+		"vlp_id", "vlp_height", "vlp_validator", "vlp_power", "vlp_proposer_priority", "vlp_created_at",
+	)
+	for _, power := range powers {
+		if power.ID == "" {
+			return derrors.InvalidArgument("field ID can not be empty")
+		}
+		if power.Height == 0 {
+			return derrors.InvalidArgument("field Height can not be zero")
+		}
+		if power.Validator == "" {
+			return derrors.InvalidArgument("field Validator can not be empty")
+		}
+		if power.CreatedAt.IsZero() {
+			return derrors.InvalidArgument("field CreatedAt can not be 0")
+		}
+		q = q.Values(power.ID, power.Height, power.Validator, power.Power, power.ProposerPriority, power.CreatedAt)
+	}
+	return db.Insert(q)
+}
+
+// GetValidatorsPowers returns the validator set as it was at filter.To (the latest one by default)
+func (db DB) GetValidatorsPowers(filter filters.ValidatorPowers) (powers []dmodels.ValidatorPower, err error) {
+	q := squirrel.Select(
+		"vlp_validator as validator",
+		"argMax(vlp_power, vlp_height) as power",
+		"argMax(vlp_proposer_priority, vlp_height) as priority",
+		"max(vlp_height) as height",
+		"max(vlp_created_at) as created_at",
+	).From(dmodels.ValidatorPowersTable).GroupBy("validator")
+	if len(filter.Validators) != 0 {
+		q = q.Where(squirrel.Eq{"vlp_validator": filter.Validators})
+	}
+	if !filter.To.IsZero() {
+		q = q.Where(squirrel.LtOrEq{"vlp_created_at": filter.To.Time})
+	}
+	q = squirrel.Select(
+		"validator as vlp_validator",
+		"power as vlp_power",
+		"priority as vlp_proposer_priority",
+		"height as vlp_height",
+		"created_at as vlp_created_at",
+	).FromSelect(q, "t").Where(squirrel.Gt{"power": 0}).OrderBy("power desc")
+	err = db.Find(&powers, q)
+	return powers, err
+}
+
+// GetValidatorPowerChanges returns the changes of the validator set between filter.From and filter.To in order of the heights
+func (db DB) GetValidatorPowerChanges(filter filters.ValidatorPowers) (powers []dmodels.ValidatorPower, err error) {
+	q := squirrel.Select("*").From(dmodels.ValidatorPowersTable).OrderBy("vlp_height")
+	if len(filter.Validators) != 0 {
+		q = q.Where(squirrel.Eq{"vlp_validator": filter.Validators})
+	}
+	if !filter.From.IsZero() {
+		q = q.Where(squirrel.GtOrEq{"vlp_created_at": filter.From.Time})
+	}
+	if !filter.To.IsZero() {
+		q = q.Where(squirrel.LtOrEq{"vlp_created_at": filter.To.Time})
+	}
+	err = db.Find(&powers, q)
+	return powers, err
+}
+
+// GetAggValidatorPower returns the last voting power of the validator in each period with changes
+func (db DB) GetAggValidatorPower(filter filters.ValidatorPowerAgg) (items []smodels.AggItem, err error) {
+	q := filter.BuildQuery("argMax(vlp_power, vlp_height)", "vlp_created_at", dmodels.ValidatorPowersTable).
+		Where(squirrel.Eq{"vlp_validator": filter.Validator})
+	err = db.Find(&items, q)
+	return items, err
+}
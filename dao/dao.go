@@ -13,6 +13,10 @@ import (
 	"time"
 )
 
+// ParserHeightCacheKey stores the height of the latest block committed by the parser,
+// it changes every time new blocks are saved
+const ParserHeightCacheKey = "parser_height"
+
 type (
 	DAO interface {
 		Mysql
@@ -30,9 +34,34 @@ type (
 		GetAccount(address string) (account dmodels.Account, err error)
 		GetAccounts(filter filters.Accounts) (accounts []dmodels.Account, err error)
 		GetAccountsTotal(filter filters.Accounts) (total uint64, err error)
+		GetAccountsDistribution(filter filters.AccountsDistribution) (buckets []dmodels.AccountsBucket, err error)
+		CreateVestingAccounts(accounts []dmodels.VestingAccount) error
+		GetVestingAccounts(filter filters.VestingAccounts) (accounts []dmodels.VestingAccount, err error)
 		CreateProposals(proposals []dmodels.Proposal) error
 		GetProposals(filter filters.Proposals) (proposals []dmodels.Proposal, err error)
 		UpdateProposal(proposal dmodels.Proposal) error
+		CreateAPIKey(key dmodels.APIKey) (id uint64, err error)
+		UpdateAPIKey(key dmodels.APIKey) error
+		GetAPIKeys(filter filters.APIKeys) (keys []dmodels.APIKey, err error)
+		GetAPIKey(filter filters.APIKeys) (key dmodels.APIKey, err error)
+		IncAPIKeyUsages(usages []dmodels.APIKeyUsage) error
+		GetAPIKeyUsages(filter filters.APIKeyUsages) (usages []dmodels.APIKeyUsage, err error)
+		SaveAddressLabels(labels []dmodels.AddressLabel) error
+		GetAddressLabels(filter filters.AddressLabels) (labels []dmodels.AddressLabel, err error)
+		GetAddressLabelsTotal(filter filters.AddressLabels) (total uint64, err error)
+		DeleteAddressLabel(address string) error
+		CreateWebhook(webhook dmodels.Webhook) (id uint64, err error)
+		UpdateWebhook(webhook dmodels.Webhook) error
+		GetWebhooks(filter filters.Webhooks) (webhooks []dmodels.Webhook, err error)
+		GetWebhook(filter filters.Webhooks) (webhook dmodels.Webhook, err error)
+		DeleteWebhook(id uint64) error
+		CreateWebhookDeliveries(deliveries []dmodels.WebhookDelivery) error
+		GetWebhookDeliveries(filter filters.WebhookDeliveries) (deliveries []dmodels.WebhookDelivery, err error)
+		ClaimWebhookDelivery(delivery dmodels.WebhookDelivery, until time.Time) (ok bool, err error)
+		UpdateWebhookDelivery(delivery dmodels.WebhookDelivery) error
+		DeleteWebhookDeliveries(filter filters.WebhookDeliveries) error
+		GetPriceBackfill(currency string) (backfill dmodels.PriceBackfill, err error)
+		SavePriceBackfill(backfill dmodels.PriceBackfill) error
 	}
 	Clickhouse interface {
 		CreateBlocks(blocks []dmodels.Block) error
@@ -40,16 +69,15 @@ type (
 		GetBlocksCount(filter filters.Blocks) (total uint64, err error)
 		GetTransactions(filter filters.Transactions) (items []dmodels.Transaction, err error)
 		GetTransactionsCount(filter filters.Transactions) (total uint64, err error)
-		GetAggBlocksCount(filter filters.Agg) (items []smodels.AggItem, err error)
-		GetAggBlocksDelay(filter filters.Agg) (items []smodels.AggItem, err error)
 		GetAvgBlocksDelay(filter filters.TimeRange) (delay float64, err error)
-		GetAggUniqBlockValidators(filter filters.Agg) (items []smodels.AggItem, err error)
 		CreateTransactions(transactions []dmodels.Transaction) error
-		GetAggOperationsCount(filter filters.Agg) (items []smodels.AggItem, err error)
-		GetAggTransactionsFee(filter filters.Agg) (items []smodels.AggItem, err error)
 		GetTransactionsFeeVolume(filter filters.TimeRange) (total decimal.Decimal, err error)
 		GetTransactionsHighestFee(filter filters.TimeRange) (total decimal.Decimal, err error)
-		GetAggTransfersVolume(filter filters.Agg) (items []smodels.AggItem, err error)
+		GetAggTransfersVolume(filter filters.TransfersAgg) (items []smodels.AggItem, err error)
+		GetTransfers(filter filters.Transfers) (items []dmodels.Transfer, err error)
+		GetTransfersTotal(filter filters.Transfers) (total uint64, err error)
+		GetCounterparties(filter filters.Counterparties) (items []dmodels.Counterparty, err error)
+		GetTransferEdges(filter filters.TransferEdges) (items []dmodels.TransferEdge, err error)
 		CreateTransfers(transfers []dmodels.Transfer) error
 		GetTransferVolume(filter filters.TimeRange) (total decimal.Decimal, err error)
 		CreateDelegations(delegations []dmodels.Delegation) error
@@ -61,18 +89,29 @@ type (
 		GetAggUndelegationsVolume(filter filters.Agg) (items []smodels.AggItem, err error)
 		CreateDelegatorRewards(rewards []dmodels.DelegatorReward) error
 		CreateValidatorRewards(rewards []dmodels.ValidatorReward) error
+		GetValidatorsDelegatorRewards(filter filters.ValidatorRewards) (items []dmodels.ValidatorAmount, err error)
+		GetValidatorsCommissions(filter filters.ValidatorRewards) (items []dmodels.ValidatorAmount, err error)
 		CreateProposalDeposits(deposits []dmodels.ProposalDeposit) error
 		GetProposalDeposits(filter filters.ProposalDeposits) (deposits []dmodels.ProposalDeposit, err error)
 		CreateProposalVotes(votes []dmodels.ProposalVote) error
 		GetProposalVotes(filter filters.ProposalVotes) (votes []dmodels.ProposalVote, err error)
-		GetAggProposalVotes(filter filters.Agg, id []uint64) (items []smodels.AggItem, err error)
+		GetAggProposalVotes(filter filters.ProposalVotesAgg) (items []smodels.AggItem, err error)
 		GetTotalVotesByAddress(address string) (total uint64, err error)
+		CreateProposalTallySnapshots(snapshots []dmodels.ProposalTallySnapshot) error
+		GetProposalTallySnapshots(filter filters.ProposalTallySnapshots) (snapshots []dmodels.ProposalTallySnapshot, err error)
 		CreateHistoricalStates(states []dmodels.HistoricalState) error
 		GetHistoricalStates(state filters.HistoricalState) (states []dmodels.HistoricalState, err error)
 		GetAggHistoricalStatesByField(filter filters.Agg, field string) (items []smodels.AggItem, err error)
 		GetActiveAccounts(filter filters.ActiveAccounts) (addresses []string, err error)
 		CreateBalanceUpdates(updates []dmodels.BalanceUpdate) error
 		GetBalanceUpdate(filter filters.BalanceUpdates) (updates []dmodels.BalanceUpdate, err error)
+		GetAggBalanceUpdates(filter filters.BalanceUpdatesAgg) (updates []dmodels.BalanceUpdate, err error)
+		CreateSupplySnapshots(snapshots []dmodels.SupplySnapshot) error
+		GetAggSupplySnapshots(filter filters.Agg) (snapshots []dmodels.SupplySnapshot, err error)
+		CreatePrices(prices []dmodels.Price) error
+		GetPrices(filter filters.Prices) (prices []dmodels.Price, err error)
+		GetAggPrices(filter filters.PricesAgg) (items []smodels.AggItem, err error)
+		GetBalanceUpdatesAddresses() (addresses []string, err error)
 		CreateJailers(jailers []dmodels.Jailer) error
 		GetJailersTotal() (total uint64, err error)
 		CreateStats(stats []dmodels.Stat) (err error)
@@ -80,8 +119,11 @@ type (
 		CreateHistoryProposals(proposals []dmodels.HistoryProposal) error
 		GetHistoryProposals(filter filters.HistoryProposals) (proposals []dmodels.HistoryProposal, err error)
 		GetAggValidators33Power(filter filters.Agg) (items []smodels.AggItem, err error)
-		GetAggWhaleAccounts(filter filters.Agg) (items []smodels.AggItem, err error)
+		GetAggStats(filter filters.StatsAgg) (items []smodels.AggItem, err error)
+		GetMetric(metric dmodels.Metric, filter filters.Metric) (items []smodels.AggItem, err error)
+		GetMetricTotal(metric dmodels.Metric, filter filters.Metric) (total decimal.Decimal, err error)
 		GetProposedBlocksTotal(filter filters.BlocksProposed) (total uint64, err error)
+		GetProposedBlocksHeights(filter filters.BlocksProposed) (heights []uint64, err error)
 		GetVotingPower(filter filters.VotingPower) (volume decimal.Decimal, err error)
 		GetAvgOperationsPerBlock(filter filters.Agg) (items []smodels.AggItem, err error)
 		CreateMissedBlocks(blocks []dmodels.MissedBlock) error
@@ -89,14 +131,25 @@ type (
 		GetMostJailedValidators() (items []dmodels.ValidatorValue, err error)
 		GetValidatorsDelegatorsTotal() (values []dmodels.ValidatorValue, err error)
 		GetMissedBlocksCount(filter filters.MissedBlocks) (total uint64, err error)
+		GetMissedBlocksHeights(filter filters.MissedBlocks) (heights []uint64, err error)
+		GetValidatorsMissedBlocks(filter filters.MissedBlocks) (items []dmodels.ValidatorValue, err error)
+		CreateValidatorPowers(powers []dmodels.ValidatorPower) error
+		GetValidatorsPowers(filter filters.ValidatorPowers) (powers []dmodels.ValidatorPower, err error)
+		GetValidatorPowerChanges(filter filters.ValidatorPowers) (powers []dmodels.ValidatorPower, err error)
+		GetAggValidatorPower(filter filters.ValidatorPowerAgg) (items []smodels.AggItem, err error)
 		GetValidatorDelegators(filter filters.ValidatorDelegators) (items []dmodels.ValidatorDelegator, err error)
 		GetValidatorDelegatorsTotal(filter filters.ValidatorDelegators) (total uint64, err error)
+		GetDelegationStakes(filter filters.DelegationStakes) (items []dmodels.DelegationStake, err error)
+		GetRedelegations(filter filters.Redelegations) (items []dmodels.Redelegation, err error)
+		GetValidatorsStake(filter filters.DelegationStakes) (items []dmodels.DelegationStake, err error)
 		CreateAccountTxs(accountTxs []dmodels.AccountTx) error
+		GetAccountEvents(filter filters.AccountEvents, fn func(event dmodels.AccountEvent) error) error
 	}
 
 	Cache interface {
 		CacheSet(key string, data interface{}, duration time.Duration)
 		CacheGet(key string) (data interface{}, found bool)
+		CacheLoad(key string, duration time.Duration, load func() (interface{}, error)) (data interface{}, err error)
 	}
 
 	daoImpl struct {
@@ -109,15 +162,27 @@ type (
 func NewDAO(cfg config.Config) (DAO, error) {
 	mysqlDB, err := mysql.NewDB(cfg.Mysql)
 	if err != nil {
-		return nil, fmt.Errorf("mysql.NewDB: %s", err.Error())
+		return nil, fmt.Errorf("mysql.NewDB: %w", err)
 	}
 	ch, err := clickhouse.NewDB(cfg.Clickhouse)
 	if err != nil {
-		return nil, fmt.Errorf("clickhouse.NewDB: %s", err.Error())
+		return nil, fmt.Errorf("clickhouse.NewDB: %w", err)
+	}
+	var c Cache
+	switch cfg.Cache.Backend {
+	case "", config.CacheBackendMemory:
+		c = cache.New()
+	case config.CacheBackendRedis:
+		c, err = cache.NewRedis(cfg.Cache.Redis)
+		if err != nil {
+			return nil, fmt.Errorf("cache.NewRedis: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown cache backend: %s", cfg.Cache.Backend)
 	}
 	return daoImpl{
 		Mysql:      mysqlDB,
 		Clickhouse: ch,
-		Cache:      cache.New(),
+		Cache:      c,
 	}, nil
 }
@@ -1,6 +1,92 @@
 package derrors
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// Codes of typed errors, they are exposed by the API and must stay stable
 const (
-	ErrNotFound  = "ERR_NOT_FOUND"
-	ErrDuplicate = "ERR_DUPLICATE"
+	CodeNotFound            = "not_found"
+	CodeInvalidArgument     = "invalid_argument"
+	CodeUpstreamUnavailable = "upstream_unavailable"
+	CodeTimeout             = "timeout"
+	CodeDuplicate           = "duplicate"
+)
+
+var (
+	ErrNotFound  = New(CodeNotFound, "not found")
+	ErrDuplicate = New(CodeDuplicate, "duplicate")
 )
+
+// Error is an error with a machine-readable code, errors.Is matches errors with the same code
+type Error struct {
+	Code string
+	Msg  string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Msg
+	}
+	if e.Msg == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %s", e.Msg, e.Err.Error())
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+func New(code string, msg string) error {
+	return &Error{Code: code, Msg: msg}
+}
+
+func Wrap(code string, err error, msg string) error {
+	return &Error{Code: code, Msg: msg, Err: err}
+}
+
+func NotFound(format string, args ...interface{}) error {
+	return New(CodeNotFound, fmt.Sprintf(format, args...))
+}
+
+func InvalidArgument(format string, args ...interface{}) error {
+	return New(CodeInvalidArgument, fmt.Sprintf(format, args...))
+}
+
+// Code returns the code of the first typed error in the chain or an empty string
+func Code(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
+
+// FromConnection classifies errors of calls to databases and nodes: timeouts and network failures
+// become typed errors, other errors are returned as is
+func FromConnection(err error) error {
+	if err == nil || Code(err) != "" {
+		return err
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return Wrap(CodeTimeout, err, "")
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return Wrap(CodeTimeout, err, "")
+		}
+		return Wrap(CodeUpstreamUnavailable, err, "")
+	}
+	return err
+}
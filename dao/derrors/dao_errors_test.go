@@ -0,0 +1,38 @@
+package derrors
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestCode(t *testing.T) {
+	err := fmt.Errorf("service: %w", fmt.Errorf("dao: %w", ErrNotFound))
+	if Code(err) != CodeNotFound {
+		t.Errorf("expected %s, got %s", CodeNotFound, Code(err))
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("wrapped error must match ErrNotFound")
+	}
+	if errors.Is(err, ErrDuplicate) {
+		t.Error("errors with different codes must not match")
+	}
+	if Code(errors.New("plain")) != "" {
+		t.Error("plain errors have no code")
+	}
+}
+
+func TestFromConnection(t *testing.T) {
+	timeout := &net.DNSError{Err: "timeout", IsTimeout: true}
+	if code := Code(FromConnection(fmt.Errorf("query: %w", timeout))); code != CodeTimeout {
+		t.Errorf("expected %s, got %s", CodeTimeout, code)
+	}
+	refused := &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+	if code := Code(FromConnection(refused)); code != CodeUpstreamUnavailable {
+		t.Errorf("expected %s, got %s", CodeUpstreamUnavailable, code)
+	}
+	if code := Code(FromConnection(errors.New("syntax error"))); code != "" {
+		t.Errorf("expected no code, got %s", code)
+	}
+}
@@ -0,0 +1,9 @@
+package filters
+
+type AccountEvents struct {
+	TimeRange
+	Address string `schema:"-"`
+	Fiat    bool   `schema:"fiat"`
+	// Currency is the quote currency of the fiat values
+	Currency string `schema:"currency"`
+}
@@ -5,12 +5,34 @@ import (
 	"time"
 )
 
+const (
+	AccountsSortBalance = "balance"
+	AccountsSortStake   = "stake"
+	AccountsSortTotal   = "total"
+)
+
 type Accounts struct {
-	LtTotalAmount decimal.Decimal
-	GtTotalAmount decimal.Decimal
+	Addresses     []string        `schema:"-"`
+	LtTotalAmount decimal.Decimal `schema:"-"`
+	GtTotalAmount decimal.Decimal `schema:"-"`
+	// CreatedBefore counts only the accounts created before the time
+	CreatedBefore time.Time `schema:"-"`
+	Sort          string    `schema:"sort"`
+	Limit         uint64    `schema:"limit"`
+	Offset        uint64    `schema:"offset"`
 }
 
 type ActiveAccounts struct {
 	From time.Time
 	To   time.Time
 }
+
+type AccountsDistribution struct {
+	Base uint64 `schema:"base"`
+}
+
+type AccountsDistributionAgg struct {
+	Agg
+	Metric string `schema:"-"`
+	Bucket string `schema:"bucket"`
+}
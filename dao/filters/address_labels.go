@@ -0,0 +1,9 @@
+package filters
+
+type AddressLabels struct {
+	Addresses  []string `schema:"-"`
+	Entities   []string `schema:"entity"`
+	Categories []string `schema:"category"`
+	Limit      uint64   `schema:"limit"`
+	Offset     uint64   `schema:"offset"`
+}
@@ -50,11 +50,14 @@ func (agg *Agg) Validate() error {
 	if agg.From.IsZero() {
 		agg.From = dmodels.NewTime(time.Now().Add(-limit.defaultRange))
 		agg.To = dmodels.NewTime(time.Now())
-	} else {
-		d := agg.To.Sub(agg.From.Time)
-		if d > limit.maxRange {
-			return fmt.Errorf("over max limit range")
-		}
+		return nil
+	}
+	// an open range ends now, so it is limited too
+	if agg.To.IsZero() {
+		agg.To = dmodels.NewTime(time.Now())
+	}
+	if agg.To.Sub(agg.From.Time) > limit.maxRange {
+		return fmt.Errorf("over max limit range")
 	}
 	return nil
 }
@@ -89,3 +92,42 @@ func (agg *Agg) BuildQuery(aggValue string, timeColumn string, table string) squ
 	}
 	return q
 }
+
+// Periods returns the start times of the periods between From and To the way AggFunc groups them
+func (agg *Agg) Periods() (periods []time.Time) {
+	to := agg.To.Time
+	if to.IsZero() {
+		to = time.Now()
+	}
+	for t := agg.periodStart(agg.From.Time.UTC()); !t.After(to); t = agg.nextPeriod(t) {
+		periods = append(periods, t)
+	}
+	return periods
+}
+
+func (agg *Agg) periodStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch agg.By {
+	case AggByHour:
+		return t.Truncate(time.Hour)
+	case AggByWeek:
+		return day.AddDate(0, 0, -int(day.Weekday()))
+	case AggByMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+func (agg *Agg) nextPeriod(t time.Time) time.Time {
+	switch agg.By {
+	case AggByHour:
+		return t.Add(time.Hour)
+	case AggByWeek:
+		return t.AddDate(0, 0, 7)
+	case AggByMonth:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
@@ -0,0 +1,14 @@
+package filters
+
+import "github.com/everstake/cosmoscan-api/dmodels"
+
+type APIKeys struct {
+	ID      uint64
+	KeyHash string
+}
+
+type APIKeyUsages struct {
+	KeyID uint64       `schema:"-"`
+	From  dmodels.Time `schema:"from"`
+	To    dmodels.Time `schema:"to"`
+}
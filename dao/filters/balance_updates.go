@@ -1,6 +1,15 @@
 package filters
 
+import "github.com/everstake/cosmoscan-api/dmodels"
+
 type BalanceUpdates struct {
-	Limit  uint64
-	Offset uint64
+	Address string
+	To      dmodels.Time
+	Limit   uint64
+	Offset  uint64
+}
+
+type BalanceUpdatesAgg struct {
+	Agg
+	Address string `schema:"-"`
 }
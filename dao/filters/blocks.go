@@ -6,5 +6,7 @@ type Blocks struct {
 }
 
 type BlocksProposed struct {
-	Proposers []string
+	Proposers  []string
+	FromHeight uint64
+	ToHeight   uint64
 }
@@ -0,0 +1,9 @@
+package filters
+
+import "github.com/everstake/cosmoscan-api/dmodels"
+
+type DelegationStakes struct {
+	Delegators []string
+	Validators []string
+	To         dmodels.Time
+}
@@ -15,3 +15,9 @@ type ValidatorDelegators struct {
 	Limit     uint64 `schema:"limit"`
 	Offset    uint64 `schema:"offset"`
 }
+
+type Redelegations struct {
+	Delegator string
+	Limit     uint64
+	Offset    uint64
+}
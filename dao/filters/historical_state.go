@@ -1,6 +1,10 @@
 package filters
 
+import "github.com/everstake/cosmoscan-api/dmodels"
+
 type HistoricalState struct {
+	From   dmodels.Time
+	To     dmodels.Time
 	Limit  uint64
 	Offset uint64
 }
@@ -0,0 +1,136 @@
+package filters
+
+import (
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	metricDefaultBucket = "1d"
+	metricDefaultTZ     = "UTC"
+	metricDefaultPoints = 30
+	metricMaxPoints     = 1000
+)
+
+var (
+	bucketRegexp = regexp.MustCompile(`^([1-9][0-9]{0,3})([mhdwM])$`)
+	// bucketUnits are the clickhouse interval units and the approximate durations of the bucket units
+	bucketUnits = map[string]struct {
+		interval string
+		duration time.Duration
+	}{
+		"m": {interval: "MINUTE", duration: time.Minute},
+		"h": {interval: "HOUR", duration: time.Hour},
+		"d": {interval: "DAY", duration: time.Hour * 24},
+		"w": {interval: "WEEK", duration: time.Hour * 24 * 7},
+		"M": {interval: "MONTH", duration: time.Hour * 24 * 30},
+	}
+	aggBuckets = map[string]string{
+		AggByHour:  "1h",
+		AggByDay:   "1d",
+		AggByWeek:  "1w",
+		AggByMonth: "1M",
+	}
+)
+
+// Metric is the query of the time series of the metric, Bucket is the size of the time bucket
+// like 15m, 6h, 1d, 1w or 1M, TZ is the IANA time zone of the buckets
+type Metric struct {
+	Name    string       `schema:"-"`
+	Bucket  string       `schema:"bucket"`
+	Agg     string       `schema:"agg"`
+	TZ      string       `schema:"tz"`
+	From    dmodels.Time `schema:"from"`
+	To      dmodels.Time `schema:"to"`
+	Compare bool         `schema:"compare"`
+	// Legacy is the query of the legacy agg routes, its range is validated by Agg and is open without To
+	Legacy bool `schema:"-"`
+}
+
+// MetricFromAgg makes the metric query of the validated agg query of the legacy routes
+func MetricFromAgg(name string, agg Agg) Metric {
+	return Metric{
+		Name:   name,
+		Bucket: aggBuckets[agg.By],
+		TZ:     metricDefaultTZ,
+		From:   agg.From,
+		To:     agg.To,
+		Legacy: true,
+	}
+}
+
+func (m *Metric) Validate() error {
+	if m.Bucket == "" {
+		m.Bucket = metricDefaultBucket
+	}
+	size, unit, err := parseBucket(m.Bucket)
+	if err != nil {
+		return err
+	}
+	if m.TZ == "" {
+		m.TZ = metricDefaultTZ
+	}
+	if _, err := time.LoadLocation(m.TZ); err != nil {
+		return fmt.Errorf("unknown time zone")
+	}
+	if m.To.IsZero() {
+		m.To = dmodels.NewTime(time.Now())
+	}
+	bucket := bucketUnits[unit].duration * time.Duration(size)
+	if m.From.IsZero() {
+		m.From = dmodels.NewTime(m.To.Add(-bucket * metricDefaultPoints))
+	}
+	if !m.From.Before(m.To.Time) {
+		return fmt.Errorf("`from` should be before `to`")
+	}
+	if m.To.Sub(m.From.Time)/bucket > metricMaxPoints {
+		return fmt.Errorf("over max limit of %d buckets", metricMaxPoints)
+	}
+	return nil
+}
+
+// BucketColumn returns the start of the bucket of the time column in the time zone as the time column,
+// single hours, days, weeks (from sunday) and months are grouped the way AggFunc does
+func (m *Metric) BucketColumn(timeColumn string) squirrel.Sqlizer {
+	return squirrel.Expr(fmt.Sprintf("toDateTime(%s, ?) AS time", m.bucketStart(timeColumn, "?")), m.TZ, m.TZ)
+}
+
+// RangeColumn returns the column the time range is applied to, the legacy agg routes of some metrics
+// apply it to the start of the bucket, their buckets are always in UTC
+func (m *Metric) RangeColumn(metric dmodels.Metric) string {
+	if m.Legacy && metric.LegacyBucketRange {
+		tz := fmt.Sprintf("'%s'", metricDefaultTZ)
+		return fmt.Sprintf("toDateTime(%s, %s)", m.bucketStart(metric.TimeColumn, tz), tz)
+	}
+	return metric.TimeColumn
+}
+
+// bucketStart returns the start of the bucket of the time column in the time zone expression
+func (m *Metric) bucketStart(timeColumn string, tz string) string {
+	size, unit, _ := parseBucket(m.Bucket)
+	switch {
+	case size == 1 && unit == "h":
+		return fmt.Sprintf("toStartOfHour(%s, %s)", timeColumn, tz)
+	case size == 1 && unit == "d":
+		return fmt.Sprintf("toStartOfDay(%s, %s)", timeColumn, tz)
+	case size == 1 && unit == "w":
+		return fmt.Sprintf("toStartOfWeek(%s, 0, %s)", timeColumn, tz)
+	case size == 1 && unit == "M":
+		return fmt.Sprintf("toStartOfMonth(%s, %s)", timeColumn, tz)
+	default:
+		return fmt.Sprintf("toStartOfInterval(%s, INTERVAL %d %s, %s)", timeColumn, size, bucketUnits[unit].interval, tz)
+	}
+}
+
+func parseBucket(bucket string) (size uint64, unit string, err error) {
+	matches := bucketRegexp.FindStringSubmatch(bucket)
+	if matches == nil {
+		return 0, "", fmt.Errorf("invalid bucket")
+	}
+	size, _ = strconv.ParseUint(matches[1], 10, 64)
+	return size, matches[2], nil
+}
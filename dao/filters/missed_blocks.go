@@ -2,4 +2,6 @@ package filters
 
 type MissedBlocks struct {
 	Validators []string
+	FromHeight uint64
+	ToHeight   uint64
 }
@@ -0,0 +1,16 @@
+package filters
+
+import "github.com/everstake/cosmoscan-api/dmodels"
+
+type Prices struct {
+	Currency string
+	From     dmodels.Time
+	To       dmodels.Time
+	Limit    uint64
+	Offset   uint64
+}
+
+type PricesAgg struct {
+	Agg
+	Currency string `schema:"currency"`
+}
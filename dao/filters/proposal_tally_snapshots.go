@@ -0,0 +1,6 @@
+package filters
+
+type ProposalTallySnapshots struct {
+	TimeRange
+	ProposalID uint64 `schema:"-"`
+}
@@ -3,6 +3,13 @@ package filters
 type ProposalVotes struct {
 	ProposalID uint64   `schema:"proposal_id"`
 	Voters     []string `schema:"voters"`
+	Option     string   `schema:"option"`
 	Limit      uint64   `schema:"limit"`
 	Offset     uint64   `schema:"offset"`
 }
+
+type ProposalVotesAgg struct {
+	Agg
+	ProposalID []uint64 `schema:"proposal_id"`
+	Option     string   `schema:"option"`
+}
@@ -2,6 +2,7 @@ package filters
 
 type Proposals struct {
 	ID     []uint64 `schema:"id"`
+	Type   []string `schema:"type"`
 	Limit  uint64   `schema:"limit"`
 	Offset uint64   `schema:"offset"`
 }
@@ -0,0 +1,7 @@
+package filters
+
+type ValidatorRewards struct {
+	TimeRange
+	Validators []string
+	Delegators []string
+}
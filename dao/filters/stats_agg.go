@@ -0,0 +1,12 @@
+package filters
+
+type StatsAgg struct {
+	Agg
+	Title string `schema:"-"`
+}
+
+type DecentralizationAgg struct {
+	Agg
+	Metric   string `schema:"-"`
+	Entities bool   `schema:"entities"`
+}
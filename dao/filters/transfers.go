@@ -0,0 +1,52 @@
+package filters
+
+import "github.com/shopspring/decimal"
+
+const (
+	TransfersDirectionIn  = "in"
+	TransfersDirectionOut = "out"
+)
+
+type TransfersAgg struct {
+	Agg
+	Entities   []string `schema:"entity"`
+	Categories []string `schema:"category"`
+	Direction  string   `schema:"direction"`
+	// Addresses are resolved from the entities and categories
+	Addresses []string `schema:"-"`
+}
+
+type Transfers struct {
+	TimeRange
+	// Address is either the sender or the recipient
+	Address   string          `schema:"address"`
+	Sender    string          `schema:"sender"`
+	Recipient string          `schema:"recipient"`
+	Currency  string          `schema:"currency"`
+	MinAmount decimal.Decimal `schema:"min_amount"`
+	MaxAmount decimal.Decimal `schema:"max_amount"`
+	Limit     uint64          `schema:"limit"`
+	Offset    uint64          `schema:"offset"`
+}
+
+type Counterparties struct {
+	TimeRange
+	Address string `schema:"-"`
+	Limit   uint64 `schema:"limit"`
+}
+
+type TransferEdges struct {
+	TimeRange
+	// Addresses are the senders of the outgoing edges or the recipients of the incoming edges
+	Addresses []string
+	Direction string
+	// Limit is the max number of the edges per address
+	Limit uint64
+}
+
+type Trace struct {
+	TimeRange
+	Address   string `schema:"address"`
+	Depth     uint64 `schema:"depth"`
+	Direction string `schema:"direction"`
+}
@@ -0,0 +1,14 @@
+package filters
+
+import "github.com/everstake/cosmoscan-api/dmodels"
+
+type ValidatorPowers struct {
+	Validators []string
+	From       dmodels.Time
+	To         dmodels.Time
+}
+
+type ValidatorPowerAgg struct {
+	Agg
+	Validator string `schema:"-"`
+}
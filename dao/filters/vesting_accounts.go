@@ -0,0 +1,5 @@
+package filters
+
+type VestingAccounts struct {
+	Addresses []string
+}
@@ -0,0 +1,18 @@
+package filters
+
+import "time"
+
+type Webhooks struct {
+	ID       uint64
+	APIKeyID uint64
+	Active   bool
+}
+
+type WebhookDeliveries struct {
+	WebhookID     uint64    `schema:"-"`
+	Status        string    `schema:"status"`
+	DueBefore     time.Time `schema:"-"`
+	CreatedBefore time.Time `schema:"-"`
+	Limit         uint64    `schema:"limit"`
+	Offset        uint64    `schema:"offset"`
+}
@@ -1,8 +1,8 @@
 package mysql
 
 import (
-	"fmt"
 	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/everstake/cosmoscan-api/dao/filters"
 	"github.com/everstake/cosmoscan-api/dmodels"
 )
@@ -20,10 +20,10 @@ func (m DB) CreateAccounts(accounts []dmodels.Account) error {
 	)
 	for _, account := range accounts {
 		if account.Address == "" {
-			return fmt.Errorf("field Address is empty")
+			return derrors.InvalidArgument("field Address is empty")
 		}
 		if account.CreatedAt.IsZero() {
-			return fmt.Errorf("field CreatedAt is empty")
+			return derrors.InvalidArgument("field CreatedAt is empty")
 		}
 		q = q.Values(
 			account.Address,
@@ -51,12 +51,29 @@ func (m DB) UpdateAccount(account dmodels.Account) error {
 
 func (m DB) GetAccounts(filter filters.Accounts) (accounts []dmodels.Account, err error) {
 	q := squirrel.Select("*").From(dmodels.AccountsTable)
+	if len(filter.Addresses) != 0 {
+		q = q.Where(squirrel.Eq{"acc_address": filter.Addresses})
+	}
 	if !filter.GtTotalAmount.IsZero() {
 		q = q.Where(squirrel.Gt{"acc_balance + acc_stake": filter.GtTotalAmount})
 	}
 	if !filter.LtTotalAmount.IsZero() {
 		q = q.Where(squirrel.Lt{"acc_balance + acc_stake": filter.LtTotalAmount})
 	}
+	switch filter.Sort {
+	case filters.AccountsSortBalance:
+		q = q.OrderBy("acc_balance DESC", "acc_address")
+	case filters.AccountsSortStake:
+		q = q.OrderBy("acc_stake DESC", "acc_address")
+	case filters.AccountsSortTotal:
+		q = q.OrderBy("acc_balance + acc_stake + acc_unbonding DESC", "acc_address")
+	}
+	if filter.Limit != 0 {
+		q = q.Limit(filter.Limit)
+	}
+	if filter.Offset != 0 {
+		q = q.Offset(filter.Offset)
+	}
 	err = m.find(&accounts, q)
 	return accounts, err
 }
@@ -69,6 +86,9 @@ func (m DB) GetAccountsTotal(filter filters.Accounts) (total uint64, err error)
 	if !filter.LtTotalAmount.IsZero() {
 		q = q.Where(squirrel.Lt{"acc_balance + acc_stake + acc_unbonding": filter.LtTotalAmount})
 	}
+	if !filter.CreatedBefore.IsZero() {
+		q = q.Where(squirrel.Lt{"acc_created_at": filter.CreatedBefore})
+	}
 	err = m.first(&total, q)
 	return total, err
 }
@@ -78,3 +98,17 @@ func (m DB) GetAccount(address string) (account dmodels.Account, err error) {
 	err = m.first(&account, q)
 	return account, err
 }
+
+// GetAccountsDistribution returns the number of the accounts and their holdings per log-scale bucket of the total amount,
+// the bucket Exp holds amounts in [base^Exp, base^(Exp+1)), amounts below 1 are put to the bucket -1
+func (m DB) GetAccountsDistribution(filter filters.AccountsDistribution) (buckets []dmodels.AccountsBucket, err error) {
+	q := squirrel.Select().
+		Column(squirrel.Expr("CAST(GREATEST(FLOOR(LOG(?, acc_balance + acc_stake + acc_unbonding) + 1e-9), -1) AS SIGNED) AS exp", filter.Base)).
+		Columns("count(*) AS holders", "sum(acc_balance + acc_stake + acc_unbonding) AS amount").
+		From(dmodels.AccountsTable).
+		Where(squirrel.Gt{"acc_balance + acc_stake + acc_unbonding": 0}).
+		GroupBy("exp").
+		OrderBy("exp")
+	err = m.find(&buckets, q)
+	return buckets, err
+}
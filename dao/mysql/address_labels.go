@@ -0,0 +1,77 @@
+package mysql
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+)
+
+// SaveAddressLabels creates the labels or replaces the existing labels of the same addresses
+func (m DB) SaveAddressLabels(labels []dmodels.AddressLabel) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	q := squirrel.Insert(dmodels.AddressLabelsTable).Columns(
+		"adl_address",
+		"adl_entity",
+		"adl_category",
+		"adl_name",
+		"adl_created_at",
+		"adl_updated_at",
+	)
+	for _, label := range labels {
+		if label.Address == "" {
+			return derrors.InvalidArgument("field Address is empty")
+		}
+		if label.Entity == "" {
+			return derrors.InvalidArgument("field Entity is empty")
+		}
+		if label.Category == "" {
+			return derrors.InvalidArgument("field Category is empty")
+		}
+		q = q.Values(label.Address, label.Entity, label.Category, label.Name, label.CreatedAt, label.UpdatedAt)
+	}
+	q = q.Suffix("ON DUPLICATE KEY UPDATE adl_entity = VALUES(adl_entity), adl_category = VALUES(adl_category), " +
+		"adl_name = VALUES(adl_name), adl_updated_at = VALUES(adl_updated_at)")
+	_, err := m.insert(q)
+	return err
+}
+
+func (m DB) GetAddressLabels(filter filters.AddressLabels) (labels []dmodels.AddressLabel, err error) {
+	q := squirrel.Select("*").From(dmodels.AddressLabelsTable).OrderBy("adl_entity", "adl_address")
+	q = addressLabelsQuery(filter, q)
+	if filter.Limit != 0 {
+		q = q.Limit(filter.Limit)
+	}
+	if filter.Offset != 0 {
+		q = q.Offset(filter.Offset)
+	}
+	err = m.find(&labels, q)
+	return labels, err
+}
+
+func (m DB) GetAddressLabelsTotal(filter filters.AddressLabels) (total uint64, err error) {
+	q := squirrel.Select("count(*)").From(dmodels.AddressLabelsTable)
+	q = addressLabelsQuery(filter, q)
+	err = m.first(&total, q)
+	return total, err
+}
+
+func (m DB) DeleteAddressLabel(address string) error {
+	q := squirrel.Delete(dmodels.AddressLabelsTable).Where(squirrel.Eq{"adl_address": address})
+	return m.delete(q)
+}
+
+func addressLabelsQuery(filter filters.AddressLabels, q squirrel.SelectBuilder) squirrel.SelectBuilder {
+	if len(filter.Addresses) != 0 {
+		q = q.Where(squirrel.Eq{"adl_address": filter.Addresses})
+	}
+	if len(filter.Entities) != 0 {
+		q = q.Where(squirrel.Eq{"adl_entity": filter.Entities})
+	}
+	if len(filter.Categories) != 0 {
+		q = q.Where(squirrel.Eq{"adl_category": filter.Categories})
+	}
+	return q
+}
@@ -0,0 +1,103 @@
+package mysql
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+)
+
+func (m DB) CreateAPIKey(key dmodels.APIKey) (id uint64, err error) {
+	if key.KeyHash == "" {
+		return 0, derrors.InvalidArgument("field KeyHash is empty")
+	}
+	q := squirrel.Insert(dmodels.APIKeysTable).SetMap(map[string]interface{}{
+		"apk_name":        key.Name,
+		"apk_key_hash":    key.KeyHash,
+		"apk_rate":        key.Rate,
+		"apk_burst":       key.Burst,
+		"apk_daily_quota": key.DailyQuota,
+		"apk_active":      key.Active,
+		"apk_created_at":  key.CreatedAt,
+	})
+	return m.insert(q)
+}
+
+func (m DB) UpdateAPIKey(key dmodels.APIKey) error {
+	q := squirrel.Update(dmodels.APIKeysTable).
+		Where(squirrel.Eq{"apk_id": key.ID}).
+		SetMap(map[string]interface{}{
+			"apk_name":        key.Name,
+			"apk_rate":        key.Rate,
+			"apk_burst":       key.Burst,
+			"apk_daily_quota": key.DailyQuota,
+			"apk_active":      key.Active,
+		})
+	return m.update(q)
+}
+
+func (m DB) GetAPIKeys(filter filters.APIKeys) (keys []dmodels.APIKey, err error) {
+	q := squirrel.Select("*").From(dmodels.APIKeysTable).OrderBy("apk_id")
+	if filter.ID != 0 {
+		q = q.Where(squirrel.Eq{"apk_id": filter.ID})
+	}
+	if filter.KeyHash != "" {
+		q = q.Where(squirrel.Eq{"apk_key_hash": filter.KeyHash})
+	}
+	err = m.find(&keys, q)
+	return keys, err
+}
+
+func (m DB) GetAPIKey(filter filters.APIKeys) (key dmodels.APIKey, err error) {
+	q := squirrel.Select("*").From(dmodels.APIKeysTable)
+	if filter.ID != 0 {
+		q = q.Where(squirrel.Eq{"apk_id": filter.ID})
+	}
+	if filter.KeyHash != "" {
+		q = q.Where(squirrel.Eq{"apk_key_hash": filter.KeyHash})
+	}
+	err = m.first(&key, q)
+	return key, err
+}
+
+// IncAPIKeyUsages adds the given counters to the stored daily usage of each key
+func (m DB) IncAPIKeyUsages(usages []dmodels.APIKeyUsage) error {
+	if len(usages) == 0 {
+		return nil
+	}
+	q := squirrel.Insert(dmodels.APIKeyUsagesTable).Columns(
+		"aku_key_id",
+		"aku_date",
+		"aku_requests",
+		"aku_cost",
+	)
+	for _, usage := range usages {
+		if usage.KeyID == 0 {
+			return derrors.InvalidArgument("field KeyID is empty")
+		}
+		q = q.Values(
+			usage.KeyID,
+			usage.Date.Format("2006-01-02"),
+			usage.Requests,
+			usage.Cost,
+		)
+	}
+	q = q.Suffix("ON DUPLICATE KEY UPDATE aku_requests = aku_requests + VALUES(aku_requests), aku_cost = aku_cost + VALUES(aku_cost)")
+	_, err := m.insert(q)
+	return err
+}
+
+func (m DB) GetAPIKeyUsages(filter filters.APIKeyUsages) (usages []dmodels.APIKeyUsage, err error) {
+	q := squirrel.Select("*").From(dmodels.APIKeyUsagesTable).OrderBy("aku_date")
+	if filter.KeyID != 0 {
+		q = q.Where(squirrel.Eq{"aku_key_id": filter.KeyID})
+	}
+	if !filter.From.IsZero() {
+		q = q.Where(squirrel.GtOrEq{"aku_date": filter.From.Time.Format("2006-01-02")})
+	}
+	if !filter.To.IsZero() {
+		q = q.Where(squirrel.LtOrEq{"aku_date": filter.To.Time.Format("2006-01-02")})
+	}
+	err = m.find(&usages, q)
+	return usages, err
+}
@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	dbsql "database/sql"
 	"errors"
 	"fmt"
 	"github.com/Masterminds/squirrel"
@@ -76,7 +77,7 @@ func (m DB) find(dest interface{}, sb squirrel.SelectBuilder) error {
 	}
 	err = m.db.Select(dest, sql, args...)
 	if err != nil {
-		return err
+		return derrors.FromConnection(err)
 	}
 	return nil
 }
@@ -88,10 +89,10 @@ func (m DB) first(dest interface{}, sb squirrel.SelectBuilder) error {
 	}
 	err = m.db.Get(dest, sql, args...)
 	if err != nil {
-		if err.Error() == "sql: no rows in result set" {
-			return errors.New(derrors.ErrNotFound)
+		if errors.Is(err, dbsql.ErrNoRows) {
+			return derrors.ErrNotFound
 		}
-		return err
+		return derrors.FromConnection(err)
 	}
 	return nil
 }
@@ -105,9 +106,9 @@ func (m DB) insert(sb squirrel.InsertBuilder) (id uint64, err error) {
 	if err != nil {
 		mErr, ok := err.(*mysql.MySQLError)
 		if ok && mErr.Number == 1062 {
-			return 0, errors.New(derrors.ErrDuplicate)
+			return 0, derrors.ErrDuplicate
 		}
-		return id, err
+		return id, derrors.FromConnection(err)
 	}
 	lastID, err := result.LastInsertId()
 	if err != nil {
@@ -122,9 +123,21 @@ func (m DB) update(sb squirrel.UpdateBuilder) (err error) {
 		return err
 	}
 	_, err = m.db.Exec(sql, args...)
+	if err != nil {
+		return derrors.FromConnection(err)
+	}
+	return nil
+}
+
+func (m DB) delete(sb squirrel.DeleteBuilder) (err error) {
+	sql, args, err := sb.ToSql()
 	if err != nil {
 		return err
 	}
+	_, err = m.db.Exec(sql, args...)
+	if err != nil {
+		return derrors.FromConnection(err)
+	}
 	return nil
 }
 
@@ -0,0 +1,36 @@
+-- Migrations are applied in lexical order of their ids and sql-migrate sorts
+-- numeric ids before all others, so every migration added after init.sql
+-- must use the "m<NNN>_" prefix to be placed after it.
+
+-- +migrate Up
+create table if not exists api_keys
+(
+    apk_id          int unsigned auto_increment
+        primary key,
+    apk_name        varchar(255)                         not null,
+    apk_key_hash    char(64)                             not null,
+    apk_rate        decimal(10, 2) default 0.00          not null,
+    apk_burst       int unsigned   default 0             not null,
+    apk_daily_quota int unsigned   default 0             not null,
+    apk_active      tinyint(1)     default 1             not null,
+    apk_created_at  datetime       default CURRENT_TIMESTAMP not null,
+    constraint api_keys_apk_key_hash_uindex
+        unique (apk_key_hash)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci;
+
+create table if not exists api_key_usages
+(
+    aku_key_id   int unsigned                   not null,
+    aku_date     date                           not null,
+    aku_requests bigint unsigned default 0      not null,
+    aku_cost     bigint unsigned default 0      not null,
+    primary key (aku_key_id, aku_date)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci;
+
+-- +migrate Down
+drop table api_key_usages;
+drop table api_keys;
@@ -0,0 +1,9 @@
+-- +migrate Up
+alter table proposals
+    add pro_metadata varchar(1024) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci default '' not null after pro_description,
+    add pro_messages json null after pro_metadata;
+
+-- +migrate Down
+alter table proposals
+    drop column pro_metadata,
+    drop column pro_messages;
@@ -0,0 +1,19 @@
+-- +migrate Up
+alter table proposals
+    add pro_quorum_reached    tinyint(1)     default 0          not null,
+    add pro_threshold_reached tinyint(1)     default 0          not null,
+    add pro_vetoed            tinyint(1)     default 0          not null,
+    add pro_projected_outcome varchar(255)   default ''         not null,
+    add pro_quorum_needed     decimal(20, 8) default 0.00000000 not null,
+    add pro_yes_needed        decimal(20, 8) default 0.00000000 not null,
+    add pro_veto_needed       decimal(20, 8) default 0.00000000 not null;
+
+-- +migrate Down
+alter table proposals
+    drop column pro_quorum_reached,
+    drop column pro_threshold_reached,
+    drop column pro_vetoed,
+    drop column pro_projected_outcome,
+    drop column pro_quorum_needed,
+    drop column pro_yes_needed,
+    drop column pro_veto_needed;
@@ -0,0 +1,7 @@
+-- +migrate Up
+alter table proposals
+    add pro_contents json null after pro_messages;
+
+-- +migrate Down
+alter table proposals
+    drop column pro_contents;
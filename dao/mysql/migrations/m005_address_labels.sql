@@ -0,0 +1,19 @@
+-- +migrate Up
+create table if not exists address_labels
+(
+    adl_address    varchar(255)                       not null
+        primary key,
+    adl_entity     varchar(255)                       not null,
+    adl_category   varchar(64)                        not null,
+    adl_name       varchar(255) default ''            not null,
+    adl_created_at datetime     default CURRENT_TIMESTAMP not null,
+    adl_updated_at datetime     default CURRENT_TIMESTAMP not null
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci;
+
+create index address_labels_adl_entity_index
+    on address_labels (adl_entity);
+
+-- +migrate Down
+drop table address_labels;
@@ -0,0 +1,20 @@
+-- +migrate Up
+create table if not exists vesting_accounts
+(
+    vac_address          varchar(255)                       not null
+        primary key,
+    vac_type             varchar(32)                        not null,
+    vac_original_vesting decimal(20, 8)                     not null,
+    vac_start_time       datetime                           not null,
+    vac_end_time         datetime                           not null,
+    vac_tx_hash          varchar(64)  default ''            not null,
+    vac_created_at       datetime     default CURRENT_TIMESTAMP not null
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci;
+
+create index vesting_accounts_vac_end_time_index
+    on vesting_accounts (vac_end_time);
+
+-- +migrate Down
+drop table vesting_accounts;
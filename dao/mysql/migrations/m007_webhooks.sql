@@ -0,0 +1,50 @@
+-- +migrate Up
+create table if not exists webhooks
+(
+    whk_id             int unsigned auto_increment
+        primary key,
+    whk_api_key_id     int unsigned                       not null,
+    whk_url            varchar(2048)                      not null,
+    whk_secret         char(64)                           not null,
+    whk_events         text                               not null,
+    whk_addresses      text                               not null,
+    whk_validators     text                               not null,
+    whk_cons_addresses text                               not null,
+    whk_min_amount     decimal(20, 8) default 0.00000000  not null,
+    whk_active         tinyint(1)     default 1           not null,
+    whk_created_at     datetime       default CURRENT_TIMESTAMP not null,
+    whk_updated_at     datetime       default CURRENT_TIMESTAMP not null
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci;
+
+create index webhooks_whk_api_key_id_index
+    on webhooks (whk_api_key_id);
+
+create table if not exists webhook_deliveries
+(
+    wdl_id              bigint unsigned auto_increment
+        primary key,
+    wdl_webhook_id      int unsigned                       not null,
+    wdl_event_id        char(40)                           not null,
+    wdl_event           varchar(64)                        not null,
+    wdl_payload         mediumtext                         not null,
+    wdl_status          varchar(16)                        not null,
+    wdl_attempts        int unsigned default 0             not null,
+    wdl_response_code   int unsigned default 0             not null,
+    wdl_error           varchar(1024) default ''           not null,
+    wdl_next_attempt_at datetime                           not null,
+    wdl_created_at      datetime     default CURRENT_TIMESTAMP not null,
+    wdl_updated_at      datetime     default CURRENT_TIMESTAMP not null,
+    constraint webhook_deliveries_wdl_webhook_id_wdl_event_id_uindex
+        unique (wdl_webhook_id, wdl_event_id)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci;
+
+create index webhook_deliveries_wdl_status_wdl_next_attempt_at_index
+    on webhook_deliveries (wdl_status, wdl_next_attempt_at);
+
+-- +migrate Down
+drop table webhook_deliveries;
+drop table webhooks;
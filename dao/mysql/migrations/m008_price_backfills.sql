@@ -0,0 +1,12 @@
+-- +migrate Up
+create table if not exists price_backfills
+(
+    pbf_currency varchar(16) not null
+        primary key,
+    pbf_time     datetime    not null
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci;
+
+-- +migrate Down
+drop table price_backfills;
@@ -0,0 +1,29 @@
+package mysql
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
+	"github.com/everstake/cosmoscan-api/dmodels"
+)
+
+func (m DB) GetPriceBackfill(currency string) (backfill dmodels.PriceBackfill, err error) {
+	q := squirrel.Select("*").From(dmodels.PriceBackfillsTable).
+		Where(squirrel.Eq{"pbf_currency": currency})
+	err = m.first(&backfill, q)
+	return backfill, err
+}
+
+func (m DB) SavePriceBackfill(backfill dmodels.PriceBackfill) error {
+	if backfill.Currency == "" {
+		return derrors.InvalidArgument("field Currency is empty")
+	}
+	if backfill.Time.IsZero() {
+		return derrors.InvalidArgument("field Time is empty")
+	}
+	q := squirrel.Insert(dmodels.PriceBackfillsTable).
+		Columns("pbf_currency", "pbf_time").
+		Values(backfill.Currency, backfill.Time).
+		Suffix("ON DUPLICATE KEY UPDATE pbf_time = VALUES(pbf_time)")
+	_, err := m.insert(q)
+	return err
+}
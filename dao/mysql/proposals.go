@@ -1,8 +1,8 @@
 package mysql
 
 import (
-	"fmt"
 	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/everstake/cosmoscan-api/dao/filters"
 	"github.com/everstake/cosmoscan-api/dmodels"
 )
@@ -19,6 +19,9 @@ func (m DB) CreateProposals(proposals []dmodels.Proposal) error {
 		"pro_type",
 		"pro_title",
 		"pro_description",
+		"pro_metadata",
+		"pro_messages",
+		"pro_contents",
 		"pro_status",
 		"pro_votes_yes",
 		"pro_votes_abstain",
@@ -33,10 +36,17 @@ func (m DB) CreateProposals(proposals []dmodels.Proposal) error {
 		"pro_participation_rate",
 		"pro_turnout",
 		"pro_activity",
+		"pro_quorum_reached",
+		"pro_threshold_reached",
+		"pro_vetoed",
+		"pro_projected_outcome",
+		"pro_quorum_needed",
+		"pro_yes_needed",
+		"pro_veto_needed",
 	)
 	for _, p := range proposals {
 		if p.ID == 0 {
-			return fmt.Errorf("invalid ProposalID")
+			return derrors.InvalidArgument("invalid ProposalID")
 		}
 
 		q = q.Values(
@@ -47,6 +57,9 @@ func (m DB) CreateProposals(proposals []dmodels.Proposal) error {
 			p.Type,
 			p.Title,
 			p.Description,
+			p.Metadata,
+			p.Messages,
+			p.Contents,
 			p.Status,
 			p.VotesYes,
 			p.VotesAbstain,
@@ -61,6 +74,13 @@ func (m DB) CreateProposals(proposals []dmodels.Proposal) error {
 			p.ParticipationRate,
 			p.Turnout,
 			p.Activity,
+			p.QuorumReached,
+			p.ThresholdReached,
+			p.Vetoed,
+			p.ProjectedOutcome,
+			p.QuorumNeeded,
+			p.YesNeeded,
+			p.VetoNeeded,
 		)
 	}
 	_, err := m.insert(q)
@@ -72,6 +92,9 @@ func (m DB) GetProposals(filter filters.Proposals) (proposals []dmodels.Proposal
 	if len(filter.ID) != 0 {
 		q = q.Where(squirrel.Eq{"pro_id": filter.ID})
 	}
+	if len(filter.Type) != 0 {
+		q = q.Where(squirrel.Eq{"pro_type": filter.Type})
+	}
 	if filter.Limit != 0 {
 		q = q.Limit(filter.Limit)
 	}
@@ -90,6 +113,9 @@ func (m DB) UpdateProposal(proposal dmodels.Proposal) error {
 		"pro_type":               proposal.Type,
 		"pro_title":              proposal.Title,
 		"pro_description":        proposal.Description,
+		"pro_metadata":           proposal.Metadata,
+		"pro_messages":           proposal.Messages,
+		"pro_contents":           proposal.Contents,
 		"pro_status":             proposal.Status,
 		"pro_votes_yes":          proposal.VotesYes,
 		"pro_votes_abstain":      proposal.VotesAbstain,
@@ -104,6 +130,13 @@ func (m DB) UpdateProposal(proposal dmodels.Proposal) error {
 		"pro_participation_rate": proposal.ParticipationRate,
 		"pro_turnout":            proposal.Turnout,
 		"pro_activity":           proposal.Activity,
+		"pro_quorum_reached":     proposal.QuorumReached,
+		"pro_threshold_reached":  proposal.ThresholdReached,
+		"pro_vetoed":             proposal.Vetoed,
+		"pro_projected_outcome":  proposal.ProjectedOutcome,
+		"pro_quorum_needed":      proposal.QuorumNeeded,
+		"pro_yes_needed":         proposal.YesNeeded,
+		"pro_veto_needed":        proposal.VetoNeeded,
 	}
 	q := squirrel.Update(dmodels.ProposalsTable).
 		Where(squirrel.Eq{"pro_id": proposal.ID}).
@@ -1,8 +1,8 @@
 package mysql
 
 import (
-	"fmt"
 	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/everstake/cosmoscan-api/dmodels"
 )
 
@@ -29,7 +29,7 @@ func (m DB) CreateValidators(validators []dmodels.Validator) error {
 	)
 	for _, validator := range validators {
 		if validator.ConsAddress == "" {
-			return fmt.Errorf("ConsAddress is empty")
+			return derrors.InvalidArgument("ConsAddress is empty")
 		}
 		q = q.Values(
 			validator.ConsAddress,
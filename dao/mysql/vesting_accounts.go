@@ -0,0 +1,55 @@
+package mysql
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+)
+
+func (m DB) CreateVestingAccounts(accounts []dmodels.VestingAccount) error {
+	if len(accounts) == 0 {
+		return nil
+	}
+	q := squirrel.Insert(dmodels.VestingAccountsTable).Columns(
+		"vac_address",
+		"vac_type",
+		"vac_original_vesting",
+		"vac_start_time",
+		"vac_end_time",
+		"vac_tx_hash",
+		"vac_created_at",
+	)
+	for _, account := range accounts {
+		if account.Address == "" {
+			return derrors.InvalidArgument("field Address is empty")
+		}
+		if account.EndTime.IsZero() {
+			return derrors.InvalidArgument("field EndTime is empty")
+		}
+		if account.CreatedAt.IsZero() {
+			return derrors.InvalidArgument("field CreatedAt is empty")
+		}
+		q = q.Values(
+			account.Address,
+			account.Type,
+			account.OriginalVesting,
+			account.StartTime,
+			account.EndTime,
+			account.TxHash,
+			account.CreatedAt,
+		)
+	}
+	q = q.Suffix("ON DUPLICATE KEY UPDATE vac_address=vac_address")
+	_, err := m.insert(q)
+	return err
+}
+
+func (m DB) GetVestingAccounts(filter filters.VestingAccounts) (accounts []dmodels.VestingAccount, err error) {
+	q := squirrel.Select("*").From(dmodels.VestingAccountsTable).OrderBy("vac_end_time")
+	if len(filter.Addresses) != 0 {
+		q = q.Where(squirrel.Eq{"vac_address": filter.Addresses})
+	}
+	err = m.find(&accounts, q)
+	return accounts, err
+}
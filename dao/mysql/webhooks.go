@@ -0,0 +1,210 @@
+package mysql
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"time"
+)
+
+func (m DB) CreateWebhook(webhook dmodels.Webhook) (id uint64, err error) {
+	if webhook.APIKeyID == 0 {
+		return 0, derrors.InvalidArgument("field APIKeyID is empty")
+	}
+	if webhook.URL == "" {
+		return 0, derrors.InvalidArgument("field URL is empty")
+	}
+	q := squirrel.Insert(dmodels.WebhooksTable).SetMap(map[string]interface{}{
+		"whk_api_key_id":     webhook.APIKeyID,
+		"whk_url":            webhook.URL,
+		"whk_secret":         webhook.Secret,
+		"whk_events":         webhook.Events,
+		"whk_addresses":      webhook.Addresses,
+		"whk_validators":     webhook.Validators,
+		"whk_cons_addresses": webhook.ConsAddresses,
+		"whk_min_amount":     webhook.MinAmount,
+		"whk_active":         webhook.Active,
+		"whk_created_at":     webhook.CreatedAt,
+		"whk_updated_at":     webhook.UpdatedAt,
+	})
+	return m.insert(q)
+}
+
+func (m DB) UpdateWebhook(webhook dmodels.Webhook) error {
+	q := squirrel.Update(dmodels.WebhooksTable).
+		Where(squirrel.Eq{"whk_id": webhook.ID}).
+		SetMap(map[string]interface{}{
+			"whk_url":            webhook.URL,
+			"whk_events":         webhook.Events,
+			"whk_addresses":      webhook.Addresses,
+			"whk_validators":     webhook.Validators,
+			"whk_cons_addresses": webhook.ConsAddresses,
+			"whk_min_amount":     webhook.MinAmount,
+			"whk_active":         webhook.Active,
+			"whk_updated_at":     webhook.UpdatedAt,
+		})
+	return m.update(q)
+}
+
+func (m DB) GetWebhooks(filter filters.Webhooks) (webhooks []dmodels.Webhook, err error) {
+	q := squirrel.Select("*").From(dmodels.WebhooksTable).OrderBy("whk_id")
+	q = webhooksQuery(filter, q)
+	err = m.find(&webhooks, q)
+	return webhooks, err
+}
+
+func (m DB) GetWebhook(filter filters.Webhooks) (webhook dmodels.Webhook, err error) {
+	q := squirrel.Select("*").From(dmodels.WebhooksTable)
+	q = webhooksQuery(filter, q)
+	err = m.first(&webhook, q)
+	return webhook, err
+}
+
+func (m DB) DeleteWebhook(id uint64) error {
+	q := squirrel.Delete(dmodels.WebhooksTable).Where(squirrel.Eq{"whk_id": id})
+	return m.delete(q)
+}
+
+func webhooksQuery(filter filters.Webhooks, q squirrel.SelectBuilder) squirrel.SelectBuilder {
+	if filter.ID != 0 {
+		q = q.Where(squirrel.Eq{"whk_id": filter.ID})
+	}
+	if filter.APIKeyID != 0 {
+		q = q.Where(squirrel.Eq{"whk_api_key_id": filter.APIKeyID})
+	}
+	if filter.Active {
+		q = q.Where(squirrel.Eq{"whk_active": true})
+	}
+	return q
+}
+
+// webhookDeliveriesBatch keeps the inserts of the deliveries below the placeholders limit of mysql
+const webhookDeliveriesBatch = 1000
+
+// CreateWebhookDeliveries skips the deliveries of the events which were already delivered to the webhook
+func (m DB) CreateWebhookDeliveries(deliveries []dmodels.WebhookDelivery) error {
+	for _, delivery := range deliveries {
+		if delivery.WebhookID == 0 {
+			return derrors.InvalidArgument("field WebhookID is empty")
+		}
+		if delivery.EventID == "" {
+			return derrors.InvalidArgument("field EventID is empty")
+		}
+	}
+	for i := 0; i < len(deliveries); i += webhookDeliveriesBatch {
+		endOfPart := i + webhookDeliveriesBatch
+		if endOfPart > len(deliveries) {
+			endOfPart = len(deliveries)
+		}
+		err := m.createWebhookDeliveries(deliveries[i:endOfPart])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m DB) createWebhookDeliveries(deliveries []dmodels.WebhookDelivery) error {
+	q := squirrel.Insert(dmodels.WebhookDeliveriesTable).Options("IGNORE").Columns(
+		"wdl_webhook_id",
+		"wdl_event_id",
+		"wdl_event",
+		"wdl_payload",
+		"wdl_status",
+		"wdl_attempts",
+		"wdl_next_attempt_at",
+		"wdl_created_at",
+		"wdl_updated_at",
+	)
+	for _, delivery := range deliveries {
+		q = q.Values(
+			delivery.WebhookID,
+			delivery.EventID,
+			delivery.Event,
+			string(delivery.Payload),
+			delivery.Status,
+			delivery.Attempts,
+			delivery.NextAttemptAt,
+			delivery.CreatedAt,
+			delivery.UpdatedAt,
+		)
+	}
+	_, err := m.insert(q)
+	return err
+}
+
+func (m DB) GetWebhookDeliveries(filter filters.WebhookDeliveries) (deliveries []dmodels.WebhookDelivery, err error) {
+	q := squirrel.Select("*").From(dmodels.WebhookDeliveriesTable)
+	if filter.WebhookID != 0 {
+		q = q.Where(squirrel.Eq{"wdl_webhook_id": filter.WebhookID})
+	}
+	if filter.Status != "" {
+		q = q.Where(squirrel.Eq{"wdl_status": filter.Status})
+	}
+	if !filter.DueBefore.IsZero() {
+		q = q.Where(squirrel.LtOrEq{"wdl_next_attempt_at": filter.DueBefore}).OrderBy("wdl_next_attempt_at")
+	} else {
+		q = q.OrderBy("wdl_id DESC")
+	}
+	if filter.Limit != 0 {
+		q = q.Limit(filter.Limit)
+	}
+	if filter.Offset != 0 {
+		q = q.Offset(filter.Offset)
+	}
+	err = m.find(&deliveries, q)
+	return deliveries, err
+}
+
+// ClaimWebhookDelivery postpones the next attempt of the due delivery until the given time,
+// it returns false if the delivery was already claimed by another replica
+func (m DB) ClaimWebhookDelivery(delivery dmodels.WebhookDelivery, until time.Time) (ok bool, err error) {
+	sql, args, err := squirrel.Update(dmodels.WebhookDeliveriesTable).
+		Set("wdl_next_attempt_at", until).
+		Where(squirrel.Eq{
+			"wdl_id":              delivery.ID,
+			"wdl_status":          dmodels.WebhookDeliveryPending,
+			"wdl_next_attempt_at": delivery.NextAttemptAt,
+		}).ToSql()
+	if err != nil {
+		return false, err
+	}
+	result, err := m.db.Exec(sql, args...)
+	if err != nil {
+		return false, derrors.FromConnection(err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func (m DB) UpdateWebhookDelivery(delivery dmodels.WebhookDelivery) error {
+	q := squirrel.Update(dmodels.WebhookDeliveriesTable).
+		Where(squirrel.Eq{"wdl_id": delivery.ID}).
+		SetMap(map[string]interface{}{
+			"wdl_status":          delivery.Status,
+			"wdl_attempts":        delivery.Attempts,
+			"wdl_response_code":   delivery.ResponseCode,
+			"wdl_error":           delivery.Error,
+			"wdl_next_attempt_at": delivery.NextAttemptAt,
+			"wdl_updated_at":      delivery.UpdatedAt,
+		})
+	return m.update(q)
+}
+
+func (m DB) DeleteWebhookDeliveries(filter filters.WebhookDeliveries) error {
+	q := squirrel.Delete(dmodels.WebhookDeliveriesTable)
+	if filter.WebhookID != 0 {
+		q = q.Where(squirrel.Eq{"wdl_webhook_id": filter.WebhookID})
+	}
+	if filter.Status != "" {
+		q = q.Where(squirrel.Eq{"wdl_status": filter.Status})
+	}
+	if !filter.CreatedBefore.IsZero() {
+		q = q.Where(squirrel.Lt{"wdl_created_at": filter.CreatedBefore})
+	}
+	return m.delete(q)
+}
@@ -14,3 +14,9 @@ type Account struct {
 	Unbonding decimal.Decimal `db:"acc_unbonding"`
 	CreatedAt time.Time       `db:"acc_created_at"`
 }
+
+type AccountsBucket struct {
+	Exp     int64           `db:"exp"`
+	Holders uint64          `db:"holders"`
+	Amount  decimal.Decimal `db:"amount"`
+}
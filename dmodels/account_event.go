@@ -0,0 +1,27 @@
+package dmodels
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+const (
+	AccountEventTransferIn  = "transfer_in"
+	AccountEventTransferOut = "transfer_out"
+	AccountEventDelegate    = "delegate"
+	AccountEventUndelegate  = "undelegate"
+	AccountEventRedelegate  = "redelegate"
+	AccountEventReward      = "reward"
+	AccountEventFee         = "fee"
+)
+
+// AccountEvent is a single economic event of an account (transfer, delegation, reward withdrawal or fee),
+// the counterparty of a redelegation is the destination validator
+type AccountEvent struct {
+	Type         string          `db:"type" json:"type"`
+	TxHash       string          `db:"tx_hash" json:"tx_hash"`
+	Amount       decimal.Decimal `db:"amount" json:"amount"`
+	Currency     string          `db:"currency" json:"currency"`
+	Counterparty string          `db:"counterparty" json:"counterparty"`
+	Price        decimal.Decimal `db:"price" json:"price"`
+	CreatedAt    Time            `db:"created_at" json:"created_at"`
+}
@@ -0,0 +1,33 @@
+package dmodels
+
+import "time"
+
+const AddressLabelsTable = "address_labels"
+
+const (
+	LabelCategoryExchange      = "exchange"
+	LabelCategoryValidator     = "validator"
+	LabelCategoryCommunityPool = "community_pool"
+	LabelCategoryBridge        = "bridge"
+	LabelCategoryModule        = "module"
+	LabelCategoryOther         = "other"
+)
+
+var LabelCategories = []string{
+	LabelCategoryExchange,
+	LabelCategoryValidator,
+	LabelCategoryCommunityPool,
+	LabelCategoryBridge,
+	LabelCategoryModule,
+	LabelCategoryOther,
+}
+
+// AddressLabel maps an address to the entity owning it
+type AddressLabel struct {
+	Address   string    `db:"adl_address" json:"address"`
+	Entity    string    `db:"adl_entity" json:"entity"`
+	Category  string    `db:"adl_category" json:"category"`
+	Name      string    `db:"adl_name" json:"name"`
+	CreatedAt time.Time `db:"adl_created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"adl_updated_at" json:"updated_at"`
+}
@@ -0,0 +1,29 @@
+package dmodels
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+const (
+	APIKeysTable      = "api_keys"
+	APIKeyUsagesTable = "api_key_usages"
+)
+
+type APIKey struct {
+	ID         uint64          `db:"apk_id" json:"id"`
+	Name       string          `db:"apk_name" json:"name"`
+	KeyHash    string          `db:"apk_key_hash" json:"-"`
+	Rate       decimal.Decimal `db:"apk_rate" json:"rate"`
+	Burst      uint64          `db:"apk_burst" json:"burst"`
+	DailyQuota uint64          `db:"apk_daily_quota" json:"daily_quota"`
+	Active     bool            `db:"apk_active" json:"active"`
+	CreatedAt  time.Time       `db:"apk_created_at" json:"created_at"`
+}
+
+type APIKeyUsage struct {
+	KeyID    uint64    `db:"aku_key_id" json:"key_id"`
+	Date     time.Time `db:"aku_date" json:"date"`
+	Requests uint64    `db:"aku_requests" json:"requests"`
+	Cost     uint64    `db:"aku_cost" json:"cost"`
+}
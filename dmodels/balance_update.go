@@ -13,5 +13,6 @@ type BalanceUpdate struct {
 	Stake     decimal.Decimal `db:"bau_stake"`
 	Balance   decimal.Decimal `db:"bau_balance"`
 	Unbonding decimal.Decimal `db:"bau_unbonding"`
+	Rewards   decimal.Decimal `db:"bau_rewards"`
 	CreatedAt time.Time       `db:"bau_created_at"`
 }
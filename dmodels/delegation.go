@@ -7,11 +7,14 @@ import (
 
 const DelegationsTable = "delegations"
 
+// Delegation is a change of the stake of the delegator, a redelegation is stored as the negative delegation
+// of the source validator and the positive one of the destination, Counterparty is the other validator of them
 type Delegation struct {
-	ID        string          `db:"dlg_id"`
-	TxHash    string          `db:"dlg_tx_hash"`
-	Delegator string          `db:"dlg_delegator"`
-	Validator string          `db:"dlg_validator"`
-	Amount    decimal.Decimal `db:"dlg_amount"`
-	CreatedAt time.Time       `db:"dlg_created_at"`
+	ID           string          `db:"dlg_id"`
+	TxHash       string          `db:"dlg_tx_hash"`
+	Delegator    string          `db:"dlg_delegator"`
+	Validator    string          `db:"dlg_validator"`
+	Amount       decimal.Decimal `db:"dlg_amount"`
+	Counterparty string          `db:"dlg_counterparty"`
+	CreatedAt    time.Time       `db:"dlg_created_at"`
 }
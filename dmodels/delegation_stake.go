@@ -0,0 +1,13 @@
+package dmodels
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+type DelegationStake struct {
+	Delegator string          `db:"delegator"`
+	Validator string          `db:"validator"`
+	Amount    decimal.Decimal `db:"amount"`
+	Since     time.Time       `db:"since"`
+}
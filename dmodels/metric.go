@@ -0,0 +1,147 @@
+package dmodels
+
+import (
+	"fmt"
+	"github.com/everstake/cosmoscan-api/config"
+)
+
+// aggregations of the metric values per time bucket
+const (
+	MetricAggSum   = "sum"
+	MetricAggAvg   = "avg"
+	MetricAggMin   = "min"
+	MetricAggMax   = "max"
+	MetricAggCount = "count"
+	MetricAggUniq  = "uniq"
+	MetricAggP50   = "p50"
+	MetricAggP95   = "p95"
+	MetricAggP99   = "p99"
+)
+
+const (
+	MetricTransactionsFee     = "transactions_fee"
+	MetricTransactionsCount   = "transactions_count"
+	MetricTransactionsGasUsed = "transactions_gas_used"
+	MetricOperationsCount     = "operations_count"
+	MetricBlocksCount         = "blocks_count"
+	MetricBlocksDelay         = "blocks_delay"
+	MetricBlockValidators     = "block_validators"
+	MetricTransfersVolume     = "transfers_volume"
+	MetricDelegationsVolume   = "delegations_volume"
+	MetricUndelegationsVolume = "undelegations_volume"
+	MetricWhaleAccounts       = "whale_accounts"
+	MetricBondedRatio         = "bonded_ratio"
+	MetricPrice               = "price"
+)
+
+// Metric describes the time series of a table, Value is the expression of the row value
+// which is aggregated per time bucket by one of the Aggs, the first one is the default
+type Metric struct {
+	Table      string
+	TimeColumn string
+	Value      string
+	Where      string
+	Aggs       []string
+	// Atom means that the values are amounts in atom
+	Atom bool
+	// LegacyBucketRange means that the legacy agg route of the metric filters the range by the start of the bucket
+	LegacyBucketRange bool
+}
+
+var (
+	amountAggs   = []string{MetricAggSum, MetricAggAvg, MetricAggMin, MetricAggMax, MetricAggCount, MetricAggP50, MetricAggP95, MetricAggP99}
+	snapshotAggs = []string{MetricAggMax, MetricAggMin, MetricAggAvg}
+)
+
+// Metrics is the registry of the metrics served by name
+var Metrics = map[string]Metric{
+	MetricTransactionsFee: {
+		Table:      TransactionsTable,
+		TimeColumn: "trn_created_at",
+		Value:      "trn_fee",
+		Aggs:       amountAggs,
+		Atom:       true,
+	},
+	MetricTransactionsCount: {
+		Table:      TransactionsTable,
+		TimeColumn: "trn_created_at",
+		Value:      "trn_hash",
+		Aggs:       []string{MetricAggCount},
+	},
+	MetricTransactionsGasUsed: {
+		Table:      TransactionsTable,
+		TimeColumn: "trn_created_at",
+		Value:      "toDecimal64(trn_gas_used, 0)",
+		Aggs:       amountAggs,
+	},
+	MetricOperationsCount: {
+		Table:      TransactionsTable,
+		TimeColumn: "trn_created_at",
+		Value:      "toDecimal64(trn_messages, 0)",
+		Aggs:       []string{MetricAggSum, MetricAggAvg, MetricAggMax, MetricAggP50, MetricAggP95, MetricAggP99},
+	},
+	MetricBlocksCount: {
+		Table:      BlocksTable,
+		TimeColumn: "blk_created_at",
+		Value:      "blk_id",
+		Aggs:       []string{MetricAggCount},
+	},
+	MetricBlocksDelay: {
+		Table:             fmt.Sprintf("%s AS b1 JOIN %s AS b2 ON b1.blk_id = toUInt64(plus(b2.blk_id, 1))", BlocksTable, BlocksTable),
+		TimeColumn:        "b1.blk_created_at",
+		Value:             "toDecimal64(toUnixTimestamp(b1.blk_created_at) - toUnixTimestamp(b2.blk_created_at), 0)",
+		Where:             "b1.blk_id > 2",
+		Aggs:              []string{MetricAggAvg, MetricAggMin, MetricAggMax, MetricAggP50, MetricAggP95, MetricAggP99},
+		LegacyBucketRange: true,
+	},
+	MetricBlockValidators: {
+		Table:      BlocksTable,
+		TimeColumn: "blk_created_at",
+		Value:      "blk_proposer",
+		Aggs:       []string{MetricAggUniq},
+	},
+	MetricTransfersVolume: {
+		Table:      TransfersTable,
+		TimeColumn: "trf_created_at",
+		Value:      "trf_amount",
+		Where:      fmt.Sprintf("notEmpty(trf_from) AND trf_currency = '%s'", config.Currency),
+		Aggs:       amountAggs,
+		Atom:       true,
+	},
+	MetricDelegationsVolume: {
+		Table:      DelegationsTable,
+		TimeColumn: "dlg_created_at",
+		Value:      "dlg_amount",
+		Where:      "dlg_amount > 0",
+		Aggs:       amountAggs,
+		Atom:       true,
+	},
+	MetricUndelegationsVolume: {
+		Table:      DelegationsTable,
+		TimeColumn: "dlg_created_at",
+		Value:      "abs(dlg_amount)",
+		Where:      "dlg_amount < 0",
+		Aggs:       amountAggs,
+		Atom:       true,
+	},
+	MetricWhaleAccounts: {
+		Table:      StatsTable,
+		TimeColumn: "stt_created_at",
+		Value:      "toDecimal128OrZero(stt_value, 18)",
+		Where:      fmt.Sprintf("stt_title = '%s'", StatsTotalWhaleAccounts),
+		Aggs:       snapshotAggs,
+	},
+	MetricBondedRatio: {
+		Table:      HistoricalStates,
+		TimeColumn: "his_created_at",
+		Value:      "his_staked_ratio",
+		Aggs:       []string{MetricAggAvg, MetricAggMin, MetricAggMax},
+	},
+	MetricPrice: {
+		Table:      PricesTable,
+		TimeColumn: "prc_time",
+		Value:      "prc_price",
+		Where:      fmt.Sprintf("prc_currency = '%s'", config.QuoteCurrency),
+		Aggs:       []string{MetricAggAvg, MetricAggMin, MetricAggMax},
+	},
+}
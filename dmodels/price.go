@@ -0,0 +1,17 @@
+package dmodels
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+const PricesTable = "prices"
+
+// Price is the atom price in the quote currency at the start of the hour, Volume is the 24h trading volume in the same currency
+type Price struct {
+	ID       string          `db:"prc_id" json:"-"`
+	Currency string          `db:"prc_currency" json:"currency"`
+	Price    decimal.Decimal `db:"prc_price" json:"price"`
+	Volume   decimal.Decimal `db:"prc_volume" json:"volume"`
+	Source   string          `db:"prc_source" json:"source"`
+	Time     Time            `db:"prc_time" json:"time"`
+}
@@ -0,0 +1,11 @@
+package dmodels
+
+import "time"
+
+const PriceBackfillsTable = "price_backfills"
+
+// PriceBackfill is the cursor of the price history of the currency, the history is saved up to Time
+type PriceBackfill struct {
+	Currency string    `db:"pbf_currency"`
+	Time     time.Time `db:"pbf_time"`
+}
@@ -5,28 +5,50 @@ import (
 	"github.com/shopspring/decimal"
 )
 
-const ProposalsTable = "proposals"
+const (
+	ProposalsTable = "proposals"
+
+	ProposalOutcomePassed   = "Passed"
+	ProposalOutcomeRejected = "Rejected"
+	ProposalOutcomeVetoed   = "Vetoed"
+	ProposalOutcomeNoQuorum = "NoQuorum"
+)
 
 type Proposal struct {
-	ID                uint64          `db:"pro_id" json:"id"`
-	TxHash            string          `db:"pro_tx_hash" json:"tx_hash"`
-	Type              string          `db:"pro_type" json:"type"`
-	Proposer          string          `db:"pro_proposer" json:"proposer"`
-	ProposerAddress   string          `db:"pro_proposer_address" json:"proposer_address"`
-	Title             string          `db:"pro_title" json:"title"`
-	Description       string          `db:"pro_description" json:"description"`
-	Status            string          `db:"pro_status" json:"status"`
-	VotesYes          decimal.Decimal `db:"pro_votes_yes" json:"votes_yes"`
-	VotesAbstain      decimal.Decimal `db:"pro_votes_abstain" json:"votes_abstain"`
-	VotesNo           decimal.Decimal `db:"pro_votes_no" json:"votes_no"`
-	VotesNoWithVeto   decimal.Decimal `db:"pro_votes_no_with_veto" json:"votes_no_with_veto"`
-	SubmitTime        Time            `db:"pro_submit_time" json:"submit_time"`
-	DepositEndTime    Time            `db:"pro_deposit_end_time" json:"deposit_end_time"`
-	TotalDeposits     decimal.Decimal `db:"pro_total_deposits" json:"total_deposits"`
-	VotingStartTime   Time            `db:"pro_voting_start_time" json:"voting_start_time"`
-	VotingEndTime     Time            `db:"pro_voting_end_time" json:"voting_end_time"`
-	Voters            uint64          `db:"pro_voters" json:"voters"`
-	ParticipationRate decimal.Decimal `db:"pro_participation_rate" json:"participation_rate"`
-	Turnout           decimal.Decimal `db:"pro_turnout" json:"turnout"`
-	Activity          json.RawMessage `db:"pro_activity" json:"activity"`
+	ID                uint64           `db:"pro_id" json:"id"`
+	TxHash            string           `db:"pro_tx_hash" json:"tx_hash"`
+	Type              string           `db:"pro_type" json:"type"`
+	Proposer          string           `db:"pro_proposer" json:"proposer"`
+	ProposerAddress   string           `db:"pro_proposer_address" json:"proposer_address"`
+	Title             string           `db:"pro_title" json:"title"`
+	Description       string           `db:"pro_description" json:"description"`
+	Metadata          string           `db:"pro_metadata" json:"metadata"`
+	Messages          json.RawMessage  `db:"pro_messages" json:"messages"`
+	Contents          ProposalContents `db:"pro_contents" json:"contents"`
+	Status            string           `db:"pro_status" json:"status"`
+	VotesYes          decimal.Decimal  `db:"pro_votes_yes" json:"votes_yes" unit:"atom"`
+	VotesAbstain      decimal.Decimal  `db:"pro_votes_abstain" json:"votes_abstain" unit:"atom"`
+	VotesNo           decimal.Decimal  `db:"pro_votes_no" json:"votes_no" unit:"atom"`
+	VotesNoWithVeto   decimal.Decimal  `db:"pro_votes_no_with_veto" json:"votes_no_with_veto" unit:"atom"`
+	SubmitTime        Time             `db:"pro_submit_time" json:"submit_time"`
+	DepositEndTime    Time             `db:"pro_deposit_end_time" json:"deposit_end_time"`
+	TotalDeposits     decimal.Decimal  `db:"pro_total_deposits" json:"total_deposits" unit:"atom"`
+	VotingStartTime   Time             `db:"pro_voting_start_time" json:"voting_start_time"`
+	VotingEndTime     Time             `db:"pro_voting_end_time" json:"voting_end_time"`
+	Voters            uint64           `db:"pro_voters" json:"voters"`
+	ParticipationRate decimal.Decimal  `db:"pro_participation_rate" json:"participation_rate"`
+	Turnout           decimal.Decimal  `db:"pro_turnout" json:"turnout"`
+	Activity          json.RawMessage  `db:"pro_activity" json:"activity"`
+	ProposalOutcome
+}
+
+// ProposalOutcome is the result the proposal would have if the voting ended now
+type ProposalOutcome struct {
+	QuorumReached    bool            `db:"pro_quorum_reached" json:"quorum_reached"`
+	ThresholdReached bool            `db:"pro_threshold_reached" json:"threshold_reached"`
+	Vetoed           bool            `db:"pro_vetoed" json:"vetoed"`
+	ProjectedOutcome string          `db:"pro_projected_outcome" json:"projected_outcome"`
+	QuorumNeeded     decimal.Decimal `db:"pro_quorum_needed" json:"quorum_needed" unit:"atom"`
+	YesNeeded        decimal.Decimal `db:"pro_yes_needed" json:"yes_needed" unit:"atom"`
+	VetoNeeded       decimal.Decimal `db:"pro_veto_needed" json:"veto_needed" unit:"atom"`
 }
@@ -0,0 +1,94 @@
+package dmodels
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	ProposalTypeText               = "TextProposal"
+	ProposalTypeParameterChange    = "ParameterChangeProposal"
+	ProposalTypeSoftwareUpgrade    = "SoftwareUpgradeProposal"
+	ProposalTypeCancelUpgrade      = "CancelSoftwareUpgradeProposal"
+	ProposalTypeCommunityPoolSpend = "CommunityPoolSpendProposal"
+)
+
+// ProposalContents is stored as a json array, legacy proposals have a single content
+// while gov v1 proposals have one per message
+type ProposalContents []ProposalContent
+
+type (
+	// ProposalContent holds the type specific fields of the proposal content
+	ProposalContent struct {
+		Type      string        `json:"type"`
+		Changes   []ParamChange `json:"changes,omitempty"`
+		Plan      *UpgradePlan  `json:"plan,omitempty"`
+		Recipient string        `json:"recipient,omitempty"`
+		Amount    []Coin        `json:"amount,omitempty"`
+	}
+
+	ParamChange struct {
+		Subspace string `json:"subspace"`
+		Key      string `json:"key"`
+		Value    string `json:"value"`
+		// OldValue is the value before the proposal, it is empty when the proposal was not seen before execution
+		OldValue string `json:"old_value"`
+	}
+
+	UpgradePlan struct {
+		Name   string `json:"name"`
+		Height uint64 `json:"height"`
+		Info   string `json:"info"`
+		// BlocksLeft and EstimatedTime are computed on request for upcoming upgrades
+		BlocksLeft    uint64 `json:"blocks_left,omitempty"`
+		EstimatedTime *Time  `json:"estimated_time,omitempty"`
+	}
+
+	Coin struct {
+		Denom  string          `json:"denom"`
+		Amount decimal.Decimal `json:"amount"`
+	}
+)
+
+func (c ProposalContents) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (c *ProposalContents) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type %T", src)
+	}
+	if len(data) == 0 {
+		*c = nil
+		return nil
+	}
+	return json.Unmarshal(data, c)
+}
+
+// Plan returns the software upgrade plan of the proposal if there is one
+func (c ProposalContents) Plan() *UpgradePlan {
+	for _, content := range c {
+		if content.Plan != nil {
+			return content.Plan
+		}
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package dmodels
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+const ProposalTallySnapshotsTable = "proposal_tally_snapshots"
+
+type ProposalTallySnapshot struct {
+	ID               string          `db:"pts_id" json:"-"`
+	ProposalID       uint64          `db:"pts_proposal_id" json:"proposal_id"`
+	Yes              decimal.Decimal `db:"pts_yes" json:"yes" unit:"atom"`
+	Abstain          decimal.Decimal `db:"pts_abstain" json:"abstain" unit:"atom"`
+	No               decimal.Decimal `db:"pts_no" json:"no" unit:"atom"`
+	NoWithVeto       decimal.Decimal `db:"pts_no_with_veto" json:"no_with_veto" unit:"atom"`
+	Bonded           decimal.Decimal `db:"pts_bonded" json:"bonded" unit:"atom"`
+	Turnout          decimal.Decimal `db:"pts_turnout" json:"turnout"`
+	QuorumReached    bool            `db:"pts_quorum_reached" json:"quorum_reached"`
+	ThresholdReached bool            `db:"pts_threshold_reached" json:"threshold_reached"`
+	Vetoed           bool            `db:"pts_vetoed" json:"vetoed"`
+	ProjectedOutcome string          `db:"pts_projected_outcome" json:"projected_outcome"`
+	QuorumNeeded     decimal.Decimal `db:"pts_quorum_needed" json:"quorum_needed" unit:"atom"`
+	YesNeeded        decimal.Decimal `db:"pts_yes_needed" json:"yes_needed" unit:"atom"`
+	VetoNeeded       decimal.Decimal `db:"pts_veto_needed" json:"veto_needed" unit:"atom"`
+	CreatedAt        Time            `db:"pts_created_at" json:"created_at"`
+}
@@ -1,12 +1,47 @@
 package dmodels
 
-const ProposalVotesTable = "proposal_votes"
+import (
+	"github.com/shopspring/decimal"
+)
+
+const (
+	ProposalVotesTable = "proposal_votes"
+
+	VoteOptionYes        = "Yes"
+	VoteOptionAbstain    = "Abstain"
+	VoteOptionNo         = "No"
+	VoteOptionNoWithVeto = "NoWithVeto"
+	// VoteOptionWeighted marks a vote split between several options
+	VoteOptionWeighted = "Weighted"
+)
 
 type ProposalVote struct {
-	ID         string `db:"prv_id" json:"-"`
-	ProposalID uint64 `db:"prv_proposal_id" json:"proposal_id"`
-	Voter      string `db:"prv_voter" json:"voter"`
-	TxHash     string `db:"prv_tx_hash" json:"tx_hash"`
-	Option     string `db:"prv_option" json:"option"`
-	CreatedAt  Time   `db:"prv_created_at" json:"created_at"`
+	ID               string          `db:"prv_id" json:"-"`
+	ProposalID       uint64          `db:"prv_proposal_id" json:"proposal_id"`
+	Voter            string          `db:"prv_voter" json:"voter"`
+	TxHash           string          `db:"prv_tx_hash" json:"tx_hash"`
+	Option           string          `db:"prv_option" json:"option"`
+	WeightYes        decimal.Decimal `db:"prv_weight_yes" json:"weight_yes"`
+	WeightAbstain    decimal.Decimal `db:"prv_weight_abstain" json:"weight_abstain"`
+	WeightNo         decimal.Decimal `db:"prv_weight_no" json:"weight_no"`
+	WeightNoWithVeto decimal.Decimal `db:"prv_weight_no_with_veto" json:"weight_no_with_veto"`
+	CreatedAt        Time            `db:"prv_created_at" json:"created_at"`
+}
+
+// Weights returns the weight of each option of the vote
+func (v ProposalVote) Weights() map[string]decimal.Decimal {
+	return map[string]decimal.Decimal{
+		VoteOptionYes:        v.WeightYes,
+		VoteOptionAbstain:    v.WeightAbstain,
+		VoteOptionNo:         v.WeightNo,
+		VoteOptionNoWithVeto: v.WeightNoWithVeto,
+	}
+}
+
+// SameChoice returns true if both votes split the power between options in the same way
+func (v ProposalVote) SameChoice(vote ProposalVote) bool {
+	return v.WeightYes.Equal(vote.WeightYes) &&
+		v.WeightAbstain.Equal(vote.WeightAbstain) &&
+		v.WeightNo.Equal(vote.WeightNo) &&
+		v.WeightNoWithVeto.Equal(vote.WeightNoWithVeto)
 }
@@ -0,0 +1,14 @@
+package dmodels
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+type Redelegation struct {
+	TxHash       string          `db:"tx_hash"`
+	ValidatorSrc string          `db:"validator_src"`
+	ValidatorDst string          `db:"validator_dst"`
+	Amount       decimal.Decimal `db:"amount"`
+	CreatedAt    time.Time       `db:"created_at"`
+}
@@ -31,3 +31,46 @@ type Stat struct {
 	Value     decimal.Decimal `db:"stt_value"`
 	CreatedAt time.Time       `db:"stt_created_at"`
 }
+
+// decentralization metrics of the validator set, stored as stats titled by DecentralizationStatTitle
+const (
+	DecentralizationNakamoto33 = "nakamoto_33"
+	DecentralizationNakamoto66 = "nakamoto_66"
+	DecentralizationGini       = "gini"
+	DecentralizationHHI        = "hhi"
+	DecentralizationTop10Share = "top_10_share"
+	DecentralizationTop20Share = "top_20_share"
+)
+
+var DecentralizationMetrics = []string{
+	DecentralizationNakamoto33,
+	DecentralizationNakamoto66,
+	DecentralizationGini,
+	DecentralizationHHI,
+	DecentralizationTop10Share,
+	DecentralizationTop20Share,
+}
+
+// DecentralizationStatTitle returns the stat title of the metric computed over validators or over entities
+func DecentralizationStatTitle(metric string, entities bool) string {
+	if entities {
+		return "decentralization_entities_" + metric
+	}
+	return "decentralization_" + metric
+}
+
+// metrics of the accounts distribution buckets, stored as stats titled by AccountsDistributionStatTitle
+const (
+	AccountsDistributionHolders = "holders"
+	AccountsDistributionAmount  = "amount"
+)
+
+var AccountsDistributionMetrics = []string{
+	AccountsDistributionHolders,
+	AccountsDistributionAmount,
+}
+
+// AccountsDistributionStatTitle returns the stat title of the metric of the bucket starting at the amount
+func AccountsDistributionStatTitle(metric string, bucket decimal.Decimal) string {
+	return "accounts_distribution_" + metric + "_" + bucket.String()
+}
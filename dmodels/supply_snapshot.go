@@ -0,0 +1,21 @@
+package dmodels
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+const SupplySnapshotsTable = "supply_snapshots"
+
+// SupplySnapshot is the breakdown of the total supply, Total = Liquid + Bonded + Unbonding + CommunityPool + ModuleAccounts + VestingLocked
+type SupplySnapshot struct {
+	ID             string          `db:"sup_id" json:"-"`
+	Total          decimal.Decimal `db:"sup_total" json:"total" unit:"atom"`
+	Liquid         decimal.Decimal `db:"sup_liquid" json:"liquid" unit:"atom"`
+	Bonded         decimal.Decimal `db:"sup_bonded" json:"bonded" unit:"atom"`
+	Unbonding      decimal.Decimal `db:"sup_unbonding" json:"unbonding" unit:"atom"`
+	CommunityPool  decimal.Decimal `db:"sup_community_pool" json:"community_pool" unit:"atom"`
+	ModuleAccounts decimal.Decimal `db:"sup_module_accounts" json:"module_accounts" unit:"atom"`
+	VestingLocked  decimal.Decimal `db:"sup_vesting_locked" json:"vesting_locked" unit:"atom"`
+	Circulating    decimal.Decimal `db:"sup_circulating" json:"circulating" unit:"atom"`
+	CreatedAt      Time            `db:"sup_created_at" json:"created_at"`
+}
@@ -16,3 +16,18 @@ type Transfer struct {
 	Currency  string          `db:"trf_currency"`
 	CreatedAt time.Time       `db:"trf_created_at"`
 }
+
+type Counterparty struct {
+	Address   string          `db:"address"`
+	Sent      decimal.Decimal `db:"sent"`
+	Received  decimal.Decimal `db:"received"`
+	Transfers uint64          `db:"transfers"`
+}
+
+// TransferEdge is the total of the transfers from the sender to the recipient
+type TransferEdge struct {
+	Sender    string          `db:"sender"`
+	Recipient string          `db:"recipient"`
+	Amount    decimal.Decimal `db:"amount"`
+	Transfers uint64          `db:"transfers"`
+}
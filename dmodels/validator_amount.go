@@ -0,0 +1,8 @@
+package dmodels
+
+import "github.com/shopspring/decimal"
+
+type ValidatorAmount struct {
+	Validator string          `db:"validator" json:"validator"`
+	Amount    decimal.Decimal `db:"amount" json:"amount"`
+}
@@ -4,7 +4,7 @@ import "github.com/shopspring/decimal"
 
 type ValidatorDelegator struct {
 	Delegator string          `json:"delegator"`
-	Amount    decimal.Decimal `json:"amount"`
+	Amount    decimal.Decimal `json:"amount" unit:"atom"`
 	Since     Time            `json:"since"`
-	Delta     decimal.Decimal `json:"delta"`
+	Delta     decimal.Decimal `json:"delta" unit:"atom"`
 }
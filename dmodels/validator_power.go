@@ -0,0 +1,18 @@
+package dmodels
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+const ValidatorPowersTable = "validator_powers"
+
+// ValidatorPower is a change of the consensus validator set, zero power means the validator has left the set
+type ValidatorPower struct {
+	ID               string          `db:"vlp_id"`
+	Height           uint64          `db:"vlp_height"`
+	Validator        string          `db:"vlp_validator"`
+	Power            decimal.Decimal `db:"vlp_power"`
+	ProposerPriority int64           `db:"vlp_proposer_priority"`
+	CreatedAt        time.Time       `db:"vlp_created_at"`
+}
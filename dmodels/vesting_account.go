@@ -0,0 +1,38 @@
+package dmodels
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+const VestingAccountsTable = "vesting_accounts"
+
+const (
+	// VestingTypeContinuous unlocks the tokens linearly between the start and the end time
+	VestingTypeContinuous = "continuous"
+	// VestingTypeDelayed unlocks all the tokens at the end time
+	VestingTypeDelayed = "delayed"
+)
+
+type VestingAccount struct {
+	Address         string          `db:"vac_address"`
+	Type            string          `db:"vac_type"`
+	OriginalVesting decimal.Decimal `db:"vac_original_vesting"`
+	StartTime       time.Time       `db:"vac_start_time"`
+	EndTime         time.Time       `db:"vac_end_time"`
+	TxHash          string          `db:"vac_tx_hash"`
+	CreatedAt       time.Time       `db:"vac_created_at"`
+}
+
+// Locked returns the amount of the original vesting which is still locked at the time
+func (v VestingAccount) Locked(at time.Time) decimal.Decimal {
+	if !at.Before(v.EndTime) {
+		return decimal.Zero
+	}
+	if v.Type == VestingTypeDelayed || !at.After(v.StartTime) {
+		return v.OriginalVesting
+	}
+	left := decimal.New(int64(v.EndTime.Sub(at)/time.Second), 0)
+	total := decimal.New(int64(v.EndTime.Sub(v.StartTime)/time.Second), 0)
+	return v.OriginalVesting.Mul(left).Div(total).Truncate(6)
+}
@@ -0,0 +1,32 @@
+package dmodels
+
+import (
+	"github.com/shopspring/decimal"
+	"testing"
+	"time"
+)
+
+func TestVestingAccountLocked(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour * 100)
+	original := decimal.New(1000, 0)
+	continuous := VestingAccount{Type: VestingTypeContinuous, OriginalVesting: original, StartTime: start, EndTime: end}
+	delayed := VestingAccount{Type: VestingTypeDelayed, OriginalVesting: original, StartTime: start, EndTime: end}
+	tests := []struct {
+		name     string
+		account  VestingAccount
+		at       time.Time
+		expected decimal.Decimal
+	}{
+		{name: "continuous before start", account: continuous, at: start.Add(-time.Hour), expected: original},
+		{name: "continuous in the middle", account: continuous, at: start.Add(time.Hour * 25), expected: decimal.New(750, 0)},
+		{name: "continuous at end", account: continuous, at: end, expected: decimal.Zero},
+		{name: "delayed before end", account: delayed, at: end.Add(-time.Second), expected: original},
+		{name: "delayed after end", account: delayed, at: end.Add(time.Second), expected: decimal.Zero},
+	}
+	for _, test := range tests {
+		if locked := test.account.Locked(test.at); !locked.Equal(test.expected) {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, locked)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package dmodels
+
+import (
+	"crypto/sha1"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/everstake/cosmoscan-api/config"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+const (
+	WebhooksTable          = "webhooks"
+	WebhookDeliveriesTable = "webhook_deliveries"
+)
+
+const (
+	WebhookEventTransfer              = "transfer"
+	WebhookEventValidatorJailed       = "validator_jailed"
+	WebhookEventValidatorUnjailed     = "validator_unjailed"
+	WebhookEventValidatorMissedBlocks = "validator_missed_blocks"
+	WebhookEventProposalStatus        = "proposal_status"
+)
+
+var WebhookEvents = []string{
+	WebhookEventTransfer,
+	WebhookEventValidatorJailed,
+	WebhookEventValidatorUnjailed,
+	WebhookEventValidatorMissedBlocks,
+	WebhookEventProposalStatus,
+}
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// StringList is stored as a json array
+type StringList []string
+
+type (
+	// Webhook is a subscription of the api key to the events, Addresses filter the transfers by the sender
+	// or the recipient, Validators (operator addresses) filter the validator events and MinAmount filters
+	// the atom transfers by amount, empty filters match all the events except the transfers,
+	// those need the addresses or a positive MinAmount
+	Webhook struct {
+		ID         uint64     `db:"whk_id" json:"id"`
+		APIKeyID   uint64     `db:"whk_api_key_id" json:"-"`
+		URL        string     `db:"whk_url" json:"url"`
+		Secret     string     `db:"whk_secret" json:"-"`
+		Events     StringList `db:"whk_events" json:"events"`
+		Addresses  StringList `db:"whk_addresses" json:"addresses"`
+		Validators StringList `db:"whk_validators" json:"validators"`
+		// ConsAddresses are the hex consensus addresses of the Validators, blocks are signed by them
+		ConsAddresses StringList      `db:"whk_cons_addresses" json:"-"`
+		MinAmount     decimal.Decimal `db:"whk_min_amount" json:"min_amount"`
+		Active        bool            `db:"whk_active" json:"active"`
+		CreatedAt     time.Time       `db:"whk_created_at" json:"created_at"`
+		UpdatedAt     time.Time       `db:"whk_updated_at" json:"updated_at"`
+	}
+
+	// WebhookEvent is sent as the payload of the delivery, Addresses, Validators, Amount and Currency are matched
+	// with the filters of the webhooks
+	WebhookEvent struct {
+		ID         string          `json:"id"`
+		Type       string          `json:"type"`
+		Data       interface{}     `json:"data"`
+		CreatedAt  time.Time       `json:"created_at"`
+		Addresses  []string        `json:"-"`
+		Validators []string        `json:"-"`
+		Amount     decimal.Decimal `json:"-"`
+		Currency   string          `json:"-"`
+	}
+
+	// WebhookDelivery is the event to be sent to the webhook, failed attempts are retried at NextAttemptAt
+	WebhookDelivery struct {
+		ID            uint64          `db:"wdl_id" json:"id"`
+		WebhookID     uint64          `db:"wdl_webhook_id" json:"webhook_id"`
+		EventID       string          `db:"wdl_event_id" json:"event_id"`
+		Event         string          `db:"wdl_event" json:"event"`
+		Payload       json.RawMessage `db:"wdl_payload" json:"payload"`
+		Status        string          `db:"wdl_status" json:"status"`
+		Attempts      uint64          `db:"wdl_attempts" json:"attempts"`
+		ResponseCode  uint64          `db:"wdl_response_code" json:"response_code"`
+		Error         string          `db:"wdl_error" json:"error"`
+		NextAttemptAt time.Time       `db:"wdl_next_attempt_at" json:"next_attempt_at"`
+		CreatedAt     time.Time       `db:"wdl_created_at" json:"created_at"`
+		UpdatedAt     time.Time       `db:"wdl_updated_at" json:"updated_at"`
+	}
+)
+
+// NewWebhookEvent makes the event with the id of the type and the key, the same event emitted twice
+// (by another replica or after a restart) is delivered once
+func NewWebhookEvent(eventType string, key string, data interface{}, createdAt time.Time) WebhookEvent {
+	hash := sha1.Sum([]byte(eventType + "." + key))
+	return WebhookEvent{
+		ID:        hex.EncodeToString(hash[:]),
+		Type:      eventType,
+		Data:      data,
+		CreatedAt: createdAt,
+	}
+}
+
+// Matches reports whether the event passes the filters of the webhook
+func (w Webhook) Matches(event WebhookEvent) bool {
+	if !containsString(w.Events, event.Type) {
+		return false
+	}
+	if len(event.Addresses) != 0 && len(w.Addresses) != 0 && !containsAnyString(w.Addresses, event.Addresses) {
+		return false
+	}
+	if len(event.Validators) != 0 && len(w.Validators) != 0 &&
+		!containsAnyString(w.Validators, event.Validators) && !containsAnyString(w.ConsAddresses, event.Validators) {
+		return false
+	}
+	if event.Type == WebhookEventTransfer && !w.matchesTransferAmount(event) {
+		return false
+	}
+	return true
+}
+
+// matchesTransferAmount keeps a webhook without addresses from getting every transfer,
+// MinAmount is in atoms so transfers of other currencies are never above it
+func (w Webhook) matchesTransferAmount(event WebhookEvent) bool {
+	if !w.MinAmount.IsPositive() {
+		return len(w.Addresses) != 0
+	}
+	return event.Currency == config.Currency && event.Amount.GreaterThanOrEqual(w.MinAmount)
+}
+
+// NewWebhookDeliveries makes the pending deliveries of the events to the active webhooks they match
+func NewWebhookDeliveries(webhooks []Webhook, events []WebhookEvent) (deliveries []WebhookDelivery, err error) {
+	now := time.Now()
+	for _, event := range events {
+		var payload []byte
+		for _, webhook := range webhooks {
+			if !webhook.Active || !webhook.Matches(event) {
+				continue
+			}
+			if payload == nil {
+				payload, err = json.Marshal(event)
+				if err != nil {
+					return nil, fmt.Errorf("json.Marshal: %w", err)
+				}
+			}
+			deliveries = append(deliveries, WebhookDelivery{
+				WebhookID:     webhook.ID,
+				EventID:       event.ID,
+				Event:         event.Type,
+				Payload:       payload,
+				Status:        WebhookDeliveryPending,
+				NextAttemptAt: now,
+				CreatedAt:     now,
+				UpdatedAt:     now,
+			})
+		}
+	}
+	return deliveries, nil
+}
+
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (l *StringList) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type %T", src)
+	}
+	if len(data) == 0 {
+		*l = nil
+		return nil
+	}
+	return json.Unmarshal(data, l)
+}
+
+func containsString(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAnyString(items []string, values []string) bool {
+	for _, v := range values {
+		if containsString(items, v) {
+			return true
+		}
+	}
+	return false
+}
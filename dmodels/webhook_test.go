@@ -0,0 +1,70 @@
+package dmodels
+
+import (
+	"github.com/everstake/cosmoscan-api/config"
+	"github.com/shopspring/decimal"
+	"testing"
+	"time"
+)
+
+func TestWebhookMatches(t *testing.T) {
+	transfer := NewWebhookEvent(WebhookEventTransfer, "1", nil, time.Now())
+	transfer.Addresses = []string{"cosmos1from", "cosmos1to"}
+	transfer.Amount = decimal.New(100, 0)
+	transfer.Currency = config.Currency
+	ibcTransfer := transfer
+	ibcTransfer.Currency = "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2"
+	missed := NewWebhookEvent(WebhookEventValidatorMissedBlocks, "2", nil, time.Now())
+	missed.Validators = []string{"CONS"}
+	proposal := NewWebhookEvent(WebhookEventProposalStatus, "3", nil, time.Now())
+	tests := []struct {
+		name     string
+		webhook  Webhook
+		event    WebhookEvent
+		expected bool
+	}{
+		{name: "other event", webhook: Webhook{Events: StringList{WebhookEventProposalStatus}}, event: transfer, expected: false},
+		{name: "no filters of transfer", webhook: Webhook{Events: StringList{WebhookEventTransfer}}, event: transfer, expected: false},
+		{name: "watched recipient", webhook: Webhook{Events: StringList{WebhookEventTransfer}, Addresses: StringList{"cosmos1to"}}, event: transfer, expected: true},
+		{name: "other address", webhook: Webhook{Events: StringList{WebhookEventTransfer}, Addresses: StringList{"cosmos1other"}}, event: transfer, expected: false},
+		{name: "below min amount", webhook: Webhook{Events: StringList{WebhookEventTransfer}, MinAmount: decimal.New(101, 0)}, event: transfer, expected: false},
+		{name: "above min amount", webhook: Webhook{Events: StringList{WebhookEventTransfer}, MinAmount: decimal.New(100, 0)}, event: transfer, expected: true},
+		{name: "min amount of other currency", webhook: Webhook{Events: StringList{WebhookEventTransfer}, MinAmount: decimal.New(1, 0)}, event: ibcTransfer, expected: false},
+		{name: "watched address of other currency", webhook: Webhook{Events: StringList{WebhookEventTransfer}, Addresses: StringList{"cosmos1from"}}, event: ibcTransfer, expected: true},
+		{name: "validator filter of transfer", webhook: Webhook{Events: StringList{WebhookEventTransfer}, Validators: StringList{"cosmosvaloper1"}, MinAmount: decimal.New(1, 0)}, event: transfer, expected: true},
+		{name: "consensus address", webhook: Webhook{Events: StringList{WebhookEventValidatorMissedBlocks}, Validators: StringList{"cosmosvaloper1"}, ConsAddresses: StringList{"CONS"}}, event: missed, expected: true},
+		{name: "other validator", webhook: Webhook{Events: StringList{WebhookEventValidatorMissedBlocks}, Validators: StringList{"cosmosvaloper2"}, ConsAddresses: StringList{"OTHER"}}, event: missed, expected: false},
+		{name: "address filter of proposal", webhook: Webhook{Events: StringList{WebhookEventProposalStatus}, Addresses: StringList{"cosmos1to"}}, event: proposal, expected: true},
+	}
+	for _, test := range tests {
+		if matches := test.webhook.Matches(test.event); matches != test.expected {
+			t.Errorf("%s: expected %t, got %t", test.name, test.expected, matches)
+		}
+	}
+}
+
+func TestNewWebhookDeliveries(t *testing.T) {
+	event := NewWebhookEvent(WebhookEventProposalStatus, "1.VotingPeriod", map[string]uint64{"proposal_id": 1}, time.Now())
+	if event.ID != NewWebhookEvent(WebhookEventProposalStatus, "1.VotingPeriod", nil, time.Now()).ID {
+		t.Errorf("expected the same id of the same event")
+	}
+	webhooks := []Webhook{
+		{ID: 1, Events: StringList{WebhookEventProposalStatus}, Active: true},
+		{ID: 2, Events: StringList{WebhookEventProposalStatus}, Active: false},
+		{ID: 3, Events: StringList{WebhookEventTransfer}, Active: true},
+	}
+	deliveries, err := NewWebhookDeliveries(webhooks, []WebhookEvent{event})
+	if err != nil {
+		t.Fatalf("NewWebhookDeliveries: %s", err.Error())
+	}
+	if len(deliveries) != 1 || deliveries[0].WebhookID != 1 {
+		t.Fatalf("expected a delivery to the webhook 1, got %+v", deliveries)
+	}
+	if deliveries[0].EventID != event.ID || deliveries[0].Status != WebhookDeliveryPending {
+		t.Errorf("unexpected delivery %+v", deliveries[0])
+	}
+	expected := `{"id":"` + event.ID + `","type":"proposal_status","data":{"proposal_id":1},"created_at":`
+	if payload := string(deliveries[0].Payload); len(payload) < len(expected) || payload[:len(expected)] != expected {
+		t.Errorf("unexpected payload %s", payload)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/everstake/cosmoscan-api/services/scheduler"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 )
 
@@ -39,17 +40,30 @@ func main() {
 
 	sch.AddProcessWithInterval(s.UpdateValidatorsMap, time.Minute*10)
 	sch.AddProcessWithInterval(s.UpdateProposals, time.Minute*15)
+	sch.AddProcessWithInterval(s.MakeProposalTallySnapshots, time.Hour)
+	sch.AddProcessWithInterval(s.MakeSupplySnapshot, time.Hour)
+	sch.AddProcessWithInterval(s.UpdatePrices, time.Hour)
 	sch.AddProcessWithInterval(s.UpdateValidators, time.Minute*15)
+	sch.AddProcessWithInterval(s.FlushAPIKeysUsage, time.Minute)
+	sch.AddProcessWithInterval(s.DeliverWebhooks, time.Second*10)
 	sch.EveryDayAt(s.MakeUpdateBalances, 1, 0)
 	sch.EveryDayAt(s.MakeStats, 2, 0)
+	sch.EveryDayAt(s.MakeDecentralizationStats, 0, 5)
+	sch.EveryDayAt(s.MakeAccountsDistributionStats, 3, 0)
+	sch.EveryDayAt(s.RepairLastDataGaps, 4, 0)
+	sch.EveryDayAt(s.CleanWebhookDeliveries, 5, 0)
 
+	go s.WarmUpCache()
+	if cfg.LabelsFile != "" {
+		go s.ImportAddressLabelsFile(cfg.LabelsFile)
+	}
 	go s.KeepHistoricalState()
 
-	g := modules.NewGroup(apiServer, sch, prs)
+	g := modules.NewGroup(apiServer, sch, prs).WithStopTimeout(apiServer.ShutdownTimeout() + time.Second)
 	g.Run()
 
-	interrupt := make(chan os.Signal)
-	signal.Notify(interrupt, os.Interrupt, os.Kill)
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
 
 	<-interrupt
 	g.Stop()
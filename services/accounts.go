@@ -1,15 +1,33 @@
 package services
 
 import (
-	"context"
+	"crypto/sha1"
+	"encoding/hex"
 	"fmt"
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/everstake/cosmoscan-api/dao/filters"
 	"github.com/everstake/cosmoscan-api/dmodels"
 	"github.com/everstake/cosmoscan-api/log"
 	"github.com/everstake/cosmoscan-api/smodels"
+	"github.com/shopspring/decimal"
+	"strings"
+	"sync"
 	"time"
 )
 
+const (
+	balanceUpdatesBatch = 5000
+
+	richListMaxLimit = 100
+
+	accountsDistributionCacheKey = "accounts_distribution"
+	accountsDistributionBase     = 10
+	maxAccountsDistributionBase  = 1000
+)
+
+// MakeUpdateBalances refreshes the balances of all the accounts and saves a daily balance snapshot
+// for the accounts whose balances have changed or which keep accruing staking rewards
 func (s *ServiceFacade) MakeUpdateBalances() {
 	tn := time.Now()
 	accounts, err := s.dao.GetAccounts(filters.Accounts{})
@@ -17,26 +35,62 @@ func (s *ServiceFacade) MakeUpdateBalances() {
 		log.Error("MakeUpdateBalances: dao.GetAccounts: %s", err.Error())
 		return
 	}
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	tracked, err := s.dao.GetBalanceUpdatesAddresses()
+	if err != nil {
+		log.Error("MakeUpdateBalances: dao.GetBalanceUpdatesAddresses: %s", err.Error())
+		return
+	}
+	trackedMap := make(map[string]bool, len(tracked))
+	for _, address := range tracked {
+		trackedMap[address] = true
+	}
+	day := tn.UTC().Truncate(time.Hour * 24)
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		updates []dmodels.BalanceUpdate
+		saved   int
+	)
+	// flush saves the snapshots by batches while the accounts are fetched,
+	// the snapshots of a failed flush are kept and retried when the next batch is collected
+	flush := func(batch int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(updates) == 0 || batch != 0 && len(updates)%batch != 0 {
+			return nil
+		}
+		err := s.dao.CreateBalanceUpdates(updates)
+		if err != nil {
+			return err
+		}
+		saved += len(updates)
+		updates = updates[:0]
+		return nil
+	}
 	fetchers := 5
 	accountsCh := make(chan dmodels.Account)
 	for i := 0; i < fetchers; i++ {
+		wg.Add(1)
 		go func() {
-			for {
-				select {
-				case acc := <-accountsCh:
-					for {
-						err := s.updateAccount(acc)
+			defer wg.Done()
+			for acc := range accountsCh {
+				for {
+					update, err := s.updateAccount(acc, !trackedMap[acc.Address], day)
+					if err != nil {
+						log.Warn("MakeSmartUpdateBalances: updateAccount: %s", err.Error())
+						<-time.After(time.Second * 2)
+						continue
+					}
+					if update != nil {
+						mu.Lock()
+						updates = append(updates, *update)
+						mu.Unlock()
+						err = flush(balanceUpdatesBatch)
 						if err != nil {
-							log.Warn("MakeSmartUpdateBalances: updateAccount: %s", err.Error())
-							time.After(time.Second * 2)
-							continue
+							log.Error("MakeUpdateBalances: dao.CreateBalanceUpdates: %s", err.Error())
 						}
-						break
 					}
-				case <-ctx.Done():
-					return
+					break
 				}
 			}
 		}()
@@ -44,58 +98,329 @@ func (s *ServiceFacade) MakeUpdateBalances() {
 	for _, acc := range accounts {
 		accountsCh <- acc
 	}
-	<-time.After(time.Second * 5)
-	log.Info("MakeUpdateBalances finished, duration: %s", time.Now().Sub(tn))
+	close(accountsCh)
+	wg.Wait()
+	err = flush(0)
+	if err != nil {
+		log.Error("MakeUpdateBalances: dao.CreateBalanceUpdates: %s", err.Error())
+		return
+	}
+	log.Info("MakeUpdateBalances finished, duration: %s, snapshots: %d", time.Now().Sub(tn), saved)
 }
 
-func (s *ServiceFacade) updateAccount(account dmodels.Account) error {
+// updateAccount updates the stored balances of the account and returns the balance snapshot of the day
+// when the balances have changed, the account has no snapshots yet or it has a stake which accrues rewards
+func (s *ServiceFacade) updateAccount(account dmodels.Account, untracked bool, day time.Time) (*dmodels.BalanceUpdate, error) {
 	balance, err := s.node.GetBalance(account.Address)
 	if err != nil {
-		return fmt.Errorf("node.GetBalance: %s", err.Error())
+		return nil, fmt.Errorf("node.GetBalance: %w", err)
 	}
 	stake, err := s.node.GetStake(account.Address)
 	if err != nil {
-		return fmt.Errorf("node.GetStake: %s", err.Error())
+		return nil, fmt.Errorf("node.GetStake: %w", err)
 	}
-	if balance.Equal(account.Balance) && stake.Equal(account.Stake) {
-		return nil
+	changed := !balance.Equal(account.Balance) || !stake.Equal(account.Stake)
+	if !changed && !untracked && stake.IsZero() {
+		return nil, nil
 	}
 	unbonding, err := s.node.GetUnbonding(account.Address)
 	if err != nil {
-		return fmt.Errorf("node.GetUnbonding: %s", err.Error())
+		return nil, fmt.Errorf("node.GetUnbonding: %w", err)
+	}
+	rewards := decimal.Zero
+	if !stake.IsZero() {
+		rewards, err = s.node.GetStakeRewards(account.Address)
+		if err != nil {
+			return nil, fmt.Errorf("node.GetStakeRewards: %w", err)
+		}
+	}
+	if changed || !unbonding.Equal(account.Unbonding) {
+		account.Balance = balance
+		account.Stake = stake
+		account.Unbonding = unbonding
+		err = s.dao.UpdateAccount(account)
+		if err != nil {
+			return nil, fmt.Errorf("dao.UpdateAccount: %w", err)
+		}
 	}
-	account.Balance = balance
-	account.Stake = stake
-	account.Unbonding = unbonding
-	err = s.dao.UpdateAccount(account)
+	hash := sha1.Sum([]byte(fmt.Sprintf("%s.%s", account.Address, day.String())))
+	return &dmodels.BalanceUpdate{
+		ID:        hex.EncodeToString(hash[:]),
+		Address:   account.Address,
+		Stake:     stake,
+		Balance:   balance,
+		Unbonding: unbonding,
+		Rewards:   rewards,
+		CreatedAt: day,
+	}, nil
+}
+
+// GetAccountBalanceHistory returns the balances of the account at the end of every period
+// from the daily snapshots along with their value at the average price of the period in the currency
+func (s *ServiceFacade) GetAccountBalanceHistory(address string, currency string, filter filters.Agg) (items []smodels.BalanceHistoryItem, err error) {
+	if _, err := types.AccAddressFromBech32(address); err != nil {
+		return nil, derrors.Wrap(derrors.CodeInvalidArgument, err, "invalid address")
+	}
+	currency, err = s.quoteCurrency(currency)
 	if err != nil {
-		return fmt.Errorf("dao.UpdateAccount: %s", err.Error())
+		return nil, err
 	}
-	return nil
+	updates, err := s.dao.GetAggBalanceUpdates(filters.BalanceUpdatesAgg{Agg: filter, Address: address})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetAggBalanceUpdates: %w", err)
+	}
+	initial, err := s.dao.GetBalanceUpdate(filters.BalanceUpdates{
+		Address: address,
+		To:      dmodels.NewTime(filter.From.Add(-time.Second)),
+		Limit:   1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetBalanceUpdate: %w", err)
+	}
+	prices, err := s.dao.GetAggPrices(filters.PricesAgg{Agg: filter, Currency: currency})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetAggPrices: %w", err)
+	}
+	var last dmodels.BalanceUpdate
+	if len(initial) != 0 {
+		last = initial[0]
+	}
+	updatesMap := make(map[int64]dmodels.BalanceUpdate)
+	for _, update := range updates {
+		updatesMap[update.CreatedAt.Unix()] = update
+	}
+	pricesMap := make(map[int64]decimal.Decimal)
+	for _, item := range prices {
+		pricesMap[item.Time.Unix()] = item.Value
+	}
+	price := decimal.Zero
+	for _, period := range filter.Periods() {
+		if update, ok := updatesMap[period.Unix()]; ok {
+			last = update
+		}
+		if p, ok := pricesMap[period.Unix()]; ok {
+			price = p
+		}
+		total := last.Balance.Add(last.Stake).Add(last.Unbonding).Add(last.Rewards)
+		items = append(items, smodels.BalanceHistoryItem{
+			Time:       dmodels.NewTime(period),
+			Balance:    last.Balance,
+			Stake:      last.Stake,
+			Unbonding:  last.Unbonding,
+			Rewards:    last.Rewards,
+			Total:      total,
+			Price:      price,
+			TotalValue: total.Mul(price).Truncate(2),
+		})
+	}
+	return items, nil
 }
 
 func (s *ServiceFacade) GetAccount(address string) (account smodels.Account, err error) {
+	if _, err := types.AccAddressFromBech32(address); err != nil {
+		return account, derrors.Wrap(derrors.CodeInvalidArgument, err, "invalid address")
+	}
 	balance, err := s.node.GetBalance(address)
 	if err != nil {
-		return account, fmt.Errorf("node.GetBalance: %s", err.Error())
+		return account, fmt.Errorf("node.GetBalance: %w", nodeLookupError(err, "account"))
 	}
 	stake, err := s.node.GetStake(address)
 	if err != nil {
-		return account, fmt.Errorf("node.GetStake: %s", err.Error())
+		return account, fmt.Errorf("node.GetStake: %w", nodeLookupError(err, "account"))
 	}
 	unbonding, err := s.node.GetUnbonding(address)
 	if err != nil {
-		return account, fmt.Errorf("node.GetUnbonding: %s", err.Error())
+		return account, fmt.Errorf("node.GetUnbonding: %w", nodeLookupError(err, "account"))
 	}
 	rewards, err := s.node.GetStakeRewards(address)
 	if err != nil {
-		return account, fmt.Errorf("node.GetStakeRewards: %s", err.Error())
+		return account, fmt.Errorf("node.GetStakeRewards: %w", nodeLookupError(err, "account"))
 	}
-	return smodels.Account{
+	account = smodels.Account{
 		Address:     address,
 		Balance:     balance,
 		Delegated:   stake,
 		Unbonding:   unbonding,
 		StakeReward: rewards,
-	}, nil
+	}
+	labels, err := s.getAddressLabels(address)
+	if err != nil {
+		return account, fmt.Errorf("getAddressLabels: %w", err)
+	}
+	if label, ok := labels[address]; ok {
+		account.Label = &label
+	}
+	return account, nil
+}
+
+func (s *ServiceFacade) ExportAccountEvents(filter filters.AccountEvents, fn func(event dmodels.AccountEvent) error) (err error) {
+	filter.Currency, err = s.quoteCurrency(filter.Currency)
+	if err != nil {
+		return err
+	}
+	err = s.dao.GetAccountEvents(filter, fn)
+	if err != nil {
+		return fmt.Errorf("dao.GetAccountEvents: %w", err)
+	}
+	return nil
+}
+
+// GetRichList returns the accounts sorted by the balance, the stake or the total amount with their share of the supply
+func (s *ServiceFacade) GetRichList(filter filters.Accounts) (resp smodels.PaginatableResponse, err error) {
+	switch filter.Sort {
+	case "":
+		filter.Sort = filters.AccountsSortTotal
+	case filters.AccountsSortBalance, filters.AccountsSortStake, filters.AccountsSortTotal:
+	default:
+		return resp, derrors.InvalidArgument("unknown sort, use one of: %s, %s, %s",
+			filters.AccountsSortBalance, filters.AccountsSortStake, filters.AccountsSortTotal)
+	}
+	if filter.Limit == 0 || filter.Limit > richListMaxLimit {
+		filter.Limit = richListMaxLimit
+	}
+	accounts, err := s.dao.GetAccounts(filter)
+	if err != nil {
+		return resp, fmt.Errorf("dao.GetAccounts: %w", err)
+	}
+	total, err := s.dao.GetAccountsTotal(filters.Accounts{})
+	if err != nil {
+		return resp, fmt.Errorf("dao.GetAccountsTotal: %w", err)
+	}
+	supply, err := s.node.GetTotalSupply()
+	if err != nil {
+		return resp, fmt.Errorf("node.GetTotalSupply: %w", err)
+	}
+	addresses := make([]string, len(accounts))
+	for i, account := range accounts {
+		addresses[i] = account.Address
+	}
+	labels, err := s.getAddressLabels(addresses...)
+	if err != nil {
+		return resp, fmt.Errorf("getAddressLabels: %w", err)
+	}
+	items := make([]smodels.RichListItem, len(accounts))
+	for i, account := range accounts {
+		item := smodels.RichListItem{
+			Address:   account.Address,
+			Balance:   account.Balance,
+			Stake:     account.Stake,
+			Unbonding: account.Unbonding,
+			Total:     account.Balance.Add(account.Stake).Add(account.Unbonding),
+		}
+		if !supply.IsZero() {
+			item.Share = item.Total.Div(supply).Mul(hundred).Truncate(4)
+		}
+		if label, ok := labels[account.Address]; ok {
+			item.Label = &label
+		}
+		items[i] = item
+	}
+	return smodels.PaginatableResponse{Items: items, Total: total}, nil
+}
+
+// GetAccountsDistribution returns the number of the holders and their holdings per log-scale bucket of the total amount
+func (s *ServiceFacade) GetAccountsDistribution(filter filters.AccountsDistribution) (buckets []smodels.DistributionBucket, err error) {
+	if filter.Base == 0 {
+		filter.Base = accountsDistributionBase
+	}
+	if filter.Base < 2 || filter.Base > maxAccountsDistributionBase {
+		return nil, derrors.InvalidArgument("base should be between 2 and %d", maxAccountsDistributionBase)
+	}
+	key := fmt.Sprintf("%s.%d", accountsDistributionCacheKey, filter.Base)
+	data, err := s.dao.CacheLoad(key, time.Minute*10, func() (interface{}, error) {
+		items, err := s.dao.GetAccountsDistribution(filter)
+		if err != nil {
+			return nil, fmt.Errorf("dao.GetAccountsDistribution: %w", err)
+		}
+		return distributionBuckets(items, filter.Base), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data.([]smodels.DistributionBucket), nil
+}
+
+// MakeAccountsDistributionStats saves the holders and the holdings of the default distribution buckets at the start of the day
+func (s *ServiceFacade) MakeAccountsDistributionStats() {
+	y, m, d := time.Now().UTC().Date()
+	startOfToday := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	items, err := s.dao.GetAccountsDistribution(filters.AccountsDistribution{Base: accountsDistributionBase})
+	if err != nil {
+		log.Error("MakeAccountsDistributionStats: dao.GetAccountsDistribution: %s", err.Error())
+		return
+	}
+	var stats []dmodels.Stat
+	for _, bucket := range distributionBuckets(items, accountsDistributionBase) {
+		values := map[string]decimal.Decimal{
+			dmodels.AccountsDistributionHolders: decimal.New(int64(bucket.Holders), 0),
+			dmodels.AccountsDistributionAmount:  bucket.Amount,
+		}
+		for _, metric := range dmodels.AccountsDistributionMetrics {
+			title := dmodels.AccountsDistributionStatTitle(metric, bucket.From)
+			hash := sha1.Sum([]byte(fmt.Sprintf("%s.%s", title, startOfToday.String())))
+			stats = append(stats, dmodels.Stat{
+				ID:        hex.EncodeToString(hash[:]),
+				Title:     title,
+				Value:     values[metric],
+				CreatedAt: startOfToday,
+			})
+		}
+	}
+	err = s.dao.CreateStats(stats)
+	if err != nil {
+		log.Error("MakeAccountsDistributionStats: dao.CreateStats: %s", err.Error())
+	}
+}
+
+// GetAggAccountsDistribution returns the daily history of the metric of the default distribution bucket starting at filter.Bucket
+func (s *ServiceFacade) GetAggAccountsDistribution(filter filters.AccountsDistributionAgg) (items []smodels.AggItem, err error) {
+	found := false
+	for _, metric := range dmodels.AccountsDistributionMetrics {
+		found = found || metric == filter.Metric
+	}
+	if !found {
+		return nil, derrors.InvalidArgument("unknown metric, use one of: %s", strings.Join(dmodels.AccountsDistributionMetrics, ", "))
+	}
+	bucket, err := decimal.NewFromString(filter.Bucket)
+	if err != nil {
+		return nil, derrors.Wrap(derrors.CodeInvalidArgument, err, "invalid bucket")
+	}
+	items, err = s.dao.GetAggStats(filters.StatsAgg{
+		Agg:   filter.Agg,
+		Title: dmodels.AccountsDistributionStatTitle(filter.Metric, bucket),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetAggStats: %w", err)
+	}
+	return items, nil
+}
+
+// distributionBuckets fills the gaps between the stored buckets, the bucket -1 holds the amounts below 1
+func distributionBuckets(items []dmodels.AccountsBucket, base uint64) []smodels.DistributionBucket {
+	buckets := make([]smodels.DistributionBucket, 0)
+	if len(items) == 0 {
+		return buckets
+	}
+	total := decimal.Zero
+	itemsMap := make(map[int64]dmodels.AccountsBucket)
+	for _, item := range items {
+		total = total.Add(item.Amount)
+		itemsMap[item.Exp] = item
+	}
+	b := decimal.New(int64(base), 0)
+	for exp := int64(-1); exp <= items[len(items)-1].Exp; exp++ {
+		bucket := smodels.DistributionBucket{To: b.Pow(decimal.New(exp+1, 0))}
+		if exp >= 0 {
+			bucket.From = b.Pow(decimal.New(exp, 0))
+		}
+		if item, ok := itemsMap[exp]; ok {
+			bucket.Holders = item.Holders
+			bucket.Amount = item.Amount
+		}
+		if !total.IsZero() {
+			bucket.Share = bucket.Amount.Div(total).Mul(hundred).Truncate(2)
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets
 }
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/shopspring/decimal"
+	"testing"
+)
+
+func TestDistributionBuckets(t *testing.T) {
+	d := decimal.RequireFromString
+	items := []dmodels.AccountsBucket{
+		{Exp: -1, Holders: 4, Amount: d("2")},
+		{Exp: 1, Holders: 2, Amount: d("48")},
+		{Exp: 2, Holders: 1, Amount: d("150")},
+	}
+	expected := []struct {
+		from    decimal.Decimal
+		to      decimal.Decimal
+		holders uint64
+		share   decimal.Decimal
+	}{
+		{from: d("0"), to: d("1"), holders: 4, share: d("1")},
+		{from: d("1"), to: d("10"), holders: 0, share: d("0")},
+		{from: d("10"), to: d("100"), holders: 2, share: d("24")},
+		{from: d("100"), to: d("1000"), holders: 1, share: d("75")},
+	}
+	buckets := distributionBuckets(items, 10)
+	if len(buckets) != len(expected) {
+		t.Fatalf("unexpected number of buckets: %d", len(buckets))
+	}
+	for i, e := range expected {
+		b := buckets[i]
+		if !b.From.Equal(e.from) || !b.To.Equal(e.to) || b.Holders != e.holders || !b.Share.Equal(e.share) {
+			t.Errorf("bucket %d: unexpected %+v", i, b)
+		}
+	}
+	if len(distributionBuckets(nil, 10)) != 0 {
+		t.Errorf("expected no buckets without accounts")
+	}
+}
+
+func TestUnlockedAccountsTotal(t *testing.T) {
+	d := decimal.RequireFromString
+	accounts := []dmodels.Account{
+		{Address: "cosmos1a", Balance: d("200000"), Stake: d("200000")},
+		{Address: "cosmos1b", Balance: d("0.5"), Stake: d("1")},
+		{Address: "cosmos1c", Balance: d("500000")},
+	}
+	locked := map[string]decimal.Decimal{"cosmos1a": d("150000"), "cosmos1b": d("1"), "cosmos1c": d("1")}
+	// a is not a whale without the locked amount, c still is
+	if total := unlockedAccountsTotal(10, filters.Accounts{GtTotalAmount: d("300000")}, accounts, locked); total != 9 {
+		t.Errorf("whales: expected 9, got %d", total)
+	}
+	// b has 0.5 unlocked
+	if total := unlockedAccountsTotal(10, filters.Accounts{LtTotalAmount: d("1")}, accounts, locked); total != 11 {
+		t.Errorf("small accounts: expected 11, got %d", total)
+	}
+}
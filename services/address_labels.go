@@ -0,0 +1,289 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/everstake/cosmoscan-api/log"
+	"github.com/everstake/cosmoscan-api/smodels"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	addressLabelsCacheKey = "address_labels"
+	// saveAddressLabelsBatch keeps the inserts of the labels below the placeholders limit of mysql
+	saveAddressLabelsBatch = 1000
+
+	LabelsFormatJSON = "json"
+	LabelsFormatCSV  = "csv"
+)
+
+func (s *ServiceFacade) GetAddressLabels(filter filters.AddressLabels) (resp smodels.PaginatableResponse, err error) {
+	if filter.Limit == 0 || filter.Limit > 500 {
+		filter.Limit = 500
+	}
+	labels, err := s.dao.GetAddressLabels(filter)
+	if err != nil {
+		return resp, fmt.Errorf("dao.GetAddressLabels: %w", err)
+	}
+	total, err := s.dao.GetAddressLabelsTotal(filter)
+	if err != nil {
+		return resp, fmt.Errorf("dao.GetAddressLabelsTotal: %w", err)
+	}
+	return smodels.PaginatableResponse{Items: labels, Total: total}, nil
+}
+
+// GetEntities returns the labeled entities with the number of their addresses
+func (s *ServiceFacade) GetEntities() (entities []smodels.Entity, err error) {
+	labels, err := s.getAddressLabelsMap()
+	if err != nil {
+		return nil, fmt.Errorf("getAddressLabelsMap: %w", err)
+	}
+	mp := make(map[string]smodels.Entity)
+	for _, label := range labels {
+		entity := mp[label.Entity]
+		entity.Name = label.Entity
+		entity.Category = label.Category
+		entity.Addresses++
+		mp[label.Entity] = entity
+	}
+	for _, entity := range mp {
+		entities = append(entities, entity)
+	}
+	sort.Slice(entities, func(i, j int) bool {
+		return entities[i].Name < entities[j].Name
+	})
+	return entities, nil
+}
+
+func (s *ServiceFacade) SaveAddressLabel(params smodels.AddressLabelParams) (label dmodels.AddressLabel, err error) {
+	labels, err := makeAddressLabels([]smodels.AddressLabelParams{params})
+	if err != nil {
+		return label, err
+	}
+	err = s.saveAddressLabels(labels)
+	if err != nil {
+		return label, fmt.Errorf("saveAddressLabels: %w", err)
+	}
+	return labels[0], nil
+}
+
+func (s *ServiceFacade) DeleteAddressLabel(address string) error {
+	err := s.dao.DeleteAddressLabel(address)
+	if err != nil {
+		return fmt.Errorf("dao.DeleteAddressLabel: %w", err)
+	}
+	s.refreshAddressLabels()
+	return nil
+}
+
+// ImportAddressLabels saves the labels from a json array or a csv file with the address,entity,category,name header
+func (s *ServiceFacade) ImportAddressLabels(format string, r io.Reader) (count uint64, err error) {
+	var params []smodels.AddressLabelParams
+	switch format {
+	case LabelsFormatJSON:
+		err = json.NewDecoder(r).Decode(&params)
+		if err != nil {
+			return 0, derrors.Wrap(derrors.CodeInvalidArgument, err, "invalid json")
+		}
+	case LabelsFormatCSV:
+		params, err = parseAddressLabelsCSV(r)
+		if err != nil {
+			return 0, derrors.Wrap(derrors.CodeInvalidArgument, err, "invalid csv")
+		}
+	default:
+		return 0, derrors.InvalidArgument("unknown format: %s", format)
+	}
+	labels, err := makeAddressLabels(params)
+	if err != nil {
+		return 0, err
+	}
+	err = s.saveAddressLabels(labels)
+	if err != nil {
+		return 0, fmt.Errorf("saveAddressLabels: %w", err)
+	}
+	return uint64(len(labels)), nil
+}
+
+// ImportAddressLabelsFile imports the labels file configured by labels_file, the format is taken from the extension
+func (s *ServiceFacade) ImportAddressLabelsFile(path string) {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Error("ImportAddressLabelsFile: os.Open: %s", err.Error())
+		return
+	}
+	defer file.Close()
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	count, err := s.ImportAddressLabels(format, file)
+	if err != nil {
+		log.Error("ImportAddressLabelsFile: ImportAddressLabels: %s", err.Error())
+		return
+	}
+	log.Info("Imported %d address labels from %s", count, path)
+}
+
+// getAddressLabels returns the labels of the given addresses which have them
+func (s *ServiceFacade) getAddressLabels(addresses ...string) (map[string]dmodels.AddressLabel, error) {
+	labels, err := s.getAddressLabelsMap()
+	if err != nil {
+		return nil, fmt.Errorf("getAddressLabelsMap: %w", err)
+	}
+	result := make(map[string]dmodels.AddressLabel)
+	for _, address := range addresses {
+		if label, ok := labels[address]; ok {
+			result[address] = label
+		}
+	}
+	return result, nil
+}
+
+// getLabeledAddresses returns the addresses of the entities and categories
+func (s *ServiceFacade) getLabeledAddresses(entities []string, categories []string) (addresses []string, err error) {
+	labels, err := s.getAddressLabelsMap()
+	if err != nil {
+		return nil, fmt.Errorf("getAddressLabelsMap: %w", err)
+	}
+	contains := func(items []string, item string) bool {
+		for _, i := range items {
+			if strings.EqualFold(i, item) {
+				return true
+			}
+		}
+		return false
+	}
+	for address, label := range labels {
+		if len(entities) != 0 && !contains(entities, label.Entity) {
+			continue
+		}
+		if len(categories) != 0 && !contains(categories, label.Category) {
+			continue
+		}
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+	return addresses, nil
+}
+
+func (s *ServiceFacade) getAddressLabelsMap() (map[string]dmodels.AddressLabel, error) {
+	data, err := s.dao.CacheLoad(addressLabelsCacheKey, time.Minute*10, func() (interface{}, error) {
+		return s.makeAddressLabelsMap()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("makeAddressLabelsMap: %w", err)
+	}
+	return data.(map[string]dmodels.AddressLabel), nil
+}
+
+func (s *ServiceFacade) makeAddressLabelsMap() (map[string]dmodels.AddressLabel, error) {
+	labels, err := s.dao.GetAddressLabels(filters.AddressLabels{})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetAddressLabels: %w", err)
+	}
+	mp := make(map[string]dmodels.AddressLabel)
+	for _, label := range labels {
+		mp[label.Address] = label
+	}
+	return mp, nil
+}
+
+func (s *ServiceFacade) saveAddressLabels(labels []dmodels.AddressLabel) error {
+	for i := 0; i < len(labels); i += saveAddressLabelsBatch {
+		endOfPart := i + saveAddressLabelsBatch
+		if i+saveAddressLabelsBatch > len(labels) {
+			endOfPart = len(labels)
+		}
+		err := s.dao.SaveAddressLabels(labels[i:endOfPart])
+		if err != nil {
+			s.refreshAddressLabels()
+			return fmt.Errorf("dao.SaveAddressLabels: %w", err)
+		}
+	}
+	s.refreshAddressLabels()
+	return nil
+}
+
+func (s *ServiceFacade) refreshAddressLabels() {
+	mp, err := s.makeAddressLabelsMap()
+	if err != nil {
+		log.Error("refreshAddressLabels: makeAddressLabelsMap: %s", err.Error())
+		return
+	}
+	s.dao.CacheSet(addressLabelsCacheKey, mp, time.Minute*10)
+}
+
+func makeAddressLabels(params []smodels.AddressLabelParams) (labels []dmodels.AddressLabel, err error) {
+	now := time.Now()
+	for i, p := range params {
+		label := dmodels.AddressLabel{
+			Address:   strings.TrimSpace(p.Address),
+			Entity:    strings.TrimSpace(p.Entity),
+			Category:  strings.ToLower(strings.TrimSpace(p.Category)),
+			Name:      strings.TrimSpace(p.Name),
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if label.Address == "" || label.Entity == "" {
+			return nil, derrors.InvalidArgument("label %d: address and entity are required", i+1)
+		}
+		if label.Category == "" {
+			label.Category = dmodels.LabelCategoryOther
+		}
+		found := false
+		for _, c := range dmodels.LabelCategories {
+			found = found || c == label.Category
+		}
+		if !found {
+			return nil, derrors.InvalidArgument("label %d: unknown category %s, use one of: %s",
+				i+1, label.Category, strings.Join(dmodels.LabelCategories, ", "))
+		}
+		labels = append(labels, label)
+	}
+	return labels, nil
+}
+
+func parseAddressLabelsCSV(r io.Reader) (params []smodels.AddressLabelParams, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("reader.ReadAll: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["address"]; !ok {
+		return nil, fmt.Errorf("address column not found")
+	}
+	if _, ok := columns["entity"]; !ok {
+		return nil, fmt.Errorf("entity column not found")
+	}
+	value := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return record[i]
+	}
+	for _, record := range records[1:] {
+		params = append(params, smodels.AddressLabelParams{
+			Address:  value(record, "address"),
+			Entity:   value(record, "entity"),
+			Category: value(record, "category"),
+			Name:     value(record, "name"),
+		})
+	}
+	return params, nil
+}
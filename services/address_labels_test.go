@@ -0,0 +1,40 @@
+package services
+
+import (
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"strings"
+	"testing"
+)
+
+func TestParseAddressLabelsCSV(t *testing.T) {
+	data := "Entity,Address,Category\n" +
+		"Binance,cosmos1binance,Exchange\n" +
+		"Community pool,cosmos1pool,\n"
+	params, err := parseAddressLabelsCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("parseAddressLabelsCSV: %s", err.Error())
+	}
+	labels, err := makeAddressLabels(params)
+	if err != nil {
+		t.Fatalf("makeAddressLabels: %s", err.Error())
+	}
+	if len(labels) != 2 {
+		t.Fatalf("expected 2 labels, got %d", len(labels))
+	}
+	if labels[0].Address != "cosmos1binance" || labels[0].Entity != "Binance" || labels[0].Category != dmodels.LabelCategoryExchange {
+		t.Errorf("unexpected label %+v", labels[0])
+	}
+	if labels[1].Category != dmodels.LabelCategoryOther {
+		t.Errorf("expected the default category, got %s", labels[1].Category)
+	}
+
+	_, err = parseAddressLabelsCSV(strings.NewReader("address,name\ncosmos1,x\n"))
+	if err == nil {
+		t.Errorf("expected an error for the csv without entity column")
+	}
+	params[0].Category = "casino"
+	_, err = makeAddressLabels(params)
+	if err == nil {
+		t.Errorf("expected an error for the unknown category")
+	}
+}
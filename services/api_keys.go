@@ -0,0 +1,235 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/everstake/cosmoscan-api/log"
+	"github.com/everstake/cosmoscan-api/smodels"
+	"sync"
+	"time"
+)
+
+const (
+	apiKeyCacheKeyPrefix = "api_key_"
+	apiKeyLength         = 32
+)
+
+// apiKeysUsage accumulates usage of api keys in memory until it is flushed to the database
+type apiKeysUsage struct {
+	mu      *sync.Mutex
+	date    time.Time
+	pending map[uint64]dmodels.APIKeyUsage
+	stored  map[uint64]uint64
+	// leftover keeps counters of the previous day which weren't flushed before the day changed
+	leftover []dmodels.APIKeyUsage
+}
+
+func newAPIKeysUsage() *apiKeysUsage {
+	return &apiKeysUsage{
+		mu:      &sync.Mutex{},
+		date:    startOfDay(time.Now()),
+		pending: make(map[uint64]dmodels.APIKeyUsage),
+		stored:  make(map[uint64]uint64),
+	}
+}
+
+func (s *ServiceFacade) CreateAPIKey(params smodels.APIKeyParams) (key smodels.IssuedAPIKey, err error) {
+	b := make([]byte, apiKeyLength)
+	_, err = rand.Read(b)
+	if err != nil {
+		return key, fmt.Errorf("rand.Read: %w", err)
+	}
+	key.Key = hex.EncodeToString(b)
+	key.APIKey = dmodels.APIKey{
+		Name:       params.Name,
+		KeyHash:    hashAPIKey(key.Key),
+		Rate:       params.Rate,
+		Burst:      params.Burst,
+		DailyQuota: params.DailyQuota,
+		Active:     true,
+		CreatedAt:  time.Now(),
+	}
+	key.ID, err = s.dao.CreateAPIKey(key.APIKey)
+	if err != nil {
+		return key, fmt.Errorf("dao.CreateAPIKey: %w", err)
+	}
+	return key, nil
+}
+
+func (s *ServiceFacade) UpdateAPIKey(id uint64, params smodels.APIKeyParams) (key dmodels.APIKey, err error) {
+	key, err = s.dao.GetAPIKey(filters.APIKeys{ID: id})
+	if err != nil {
+		return key, fmt.Errorf("dao.GetAPIKey: %w", err)
+	}
+	key.Name = params.Name
+	key.Rate = params.Rate
+	key.Burst = params.Burst
+	key.DailyQuota = params.DailyQuota
+	key.Active = params.Active
+	err = s.dao.UpdateAPIKey(key)
+	if err != nil {
+		return key, fmt.Errorf("dao.UpdateAPIKey: %w", err)
+	}
+	s.dao.CacheSet(apiKeyCacheKeyPrefix+key.KeyHash, key, time.Minute)
+	return key, nil
+}
+
+func (s *ServiceFacade) GetAPIKeys() (keys []dmodels.APIKey, err error) {
+	keys, err = s.dao.GetAPIKeys(filters.APIKeys{})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetAPIKeys: %w", err)
+	}
+	return keys, nil
+}
+
+func (s *ServiceFacade) GetAPIKeyUsages(filter filters.APIKeyUsages) (usages []dmodels.APIKeyUsage, err error) {
+	usages, err = s.dao.GetAPIKeyUsages(filter)
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetAPIKeyUsages: %w", err)
+	}
+	return usages, nil
+}
+
+// GetAPIKeyByToken looks up an api key by its plain value, lookups are cached for a minute
+func (s *ServiceFacade) GetAPIKeyByToken(token string) (key dmodels.APIKey, found bool, err error) {
+	hash := hashAPIKey(token)
+	data, found := s.dao.CacheGet(apiKeyCacheKeyPrefix + hash)
+	if found {
+		key, found = data.(dmodels.APIKey)
+		return key, found, nil
+	}
+	keys, err := s.dao.GetAPIKeys(filters.APIKeys{KeyHash: hash})
+	if err != nil {
+		return key, false, fmt.Errorf("dao.GetAPIKeys: %w", err)
+	}
+	if len(keys) == 0 {
+		s.dao.CacheSet(apiKeyCacheKeyPrefix+hash, false, time.Minute)
+		return key, false, nil
+	}
+	s.dao.CacheSet(apiKeyCacheKeyPrefix+hash, keys[0], time.Minute)
+	return keys[0], true, nil
+}
+
+// UseAPIKeyQuota counts a request of the given cost against the daily quota of the key.
+// It returns false without counting the request if the quota is exhausted.
+// The usage of the other replicas is seen after their flush and the next reload (FlushAPIKeysUsage).
+func (s *ServiceFacade) UseAPIKeyQuota(key dmodels.APIKey, cost uint64) (ok bool, err error) {
+	u := s.apiKeysUsage
+	today := startOfDay(time.Now())
+	u.mu.Lock()
+	u.rotate(today)
+	_, loaded := u.stored[key.ID]
+	u.mu.Unlock()
+	if !loaded {
+		usages, err := s.dao.GetAPIKeyUsages(filters.APIKeyUsages{
+			KeyID: key.ID,
+			From:  dmodels.NewTime(today),
+			To:    dmodels.NewTime(today),
+		})
+		if err != nil {
+			return false, fmt.Errorf("dao.GetAPIKeyUsages: %w", err)
+		}
+		var storedCost uint64
+		for _, usage := range usages {
+			storedCost += usage.Cost
+		}
+		u.mu.Lock()
+		if _, ok := u.stored[key.ID]; !ok && u.date.Equal(today) {
+			u.stored[key.ID] = storedCost
+		}
+		u.mu.Unlock()
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.rotate(today)
+	usage := u.pending[key.ID]
+	if key.DailyQuota > 0 && u.stored[key.ID]+usage.Cost+cost > key.DailyQuota {
+		return false, nil
+	}
+	usage.KeyID = key.ID
+	usage.Date = today
+	usage.Requests++
+	usage.Cost += cost
+	u.pending[key.ID] = usage
+	return true, nil
+}
+
+// FlushAPIKeysUsage persists the usage counted since the previous flush and reloads the stored usage of the day,
+// so the usage of the other replicas is taken into account by the quotas from the next flush on
+func (s *ServiceFacade) FlushAPIKeysUsage() {
+	u := s.apiKeysUsage
+	today := startOfDay(time.Now())
+	u.mu.Lock()
+	u.rotate(today)
+	usages := u.leftover
+	for _, usage := range u.pending {
+		usages = append(usages, usage)
+	}
+	u.leftover = nil
+	u.pending = make(map[uint64]dmodels.APIKeyUsage)
+	u.mu.Unlock()
+	err := s.dao.IncAPIKeyUsages(usages)
+	if err != nil {
+		log.Error("FlushAPIKeysUsage: dao.IncAPIKeyUsages: %s", err.Error())
+		u.mu.Lock()
+		defer u.mu.Unlock()
+		for _, usage := range usages {
+			if !usage.Date.Equal(u.date) {
+				u.leftover = append(u.leftover, usage)
+				continue
+			}
+			p := u.pending[usage.KeyID]
+			p.KeyID, p.Date = usage.KeyID, usage.Date
+			p.Requests += usage.Requests
+			p.Cost += usage.Cost
+			u.pending[usage.KeyID] = p
+		}
+		return
+	}
+	stored, err := s.dao.GetAPIKeyUsages(filters.APIKeyUsages{
+		From: dmodels.NewTime(today),
+		To:   dmodels.NewTime(today),
+	})
+	if err != nil {
+		log.Error("FlushAPIKeysUsage: dao.GetAPIKeyUsages: %s", err.Error())
+		return
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if !u.date.Equal(today) {
+		return
+	}
+	for id := range u.stored {
+		u.stored[id] = 0
+	}
+	for _, usage := range stored {
+		u.stored[usage.KeyID] += usage.Cost
+	}
+}
+
+// rotate resets daily counters when the day changes, must be called under lock
+func (u *apiKeysUsage) rotate(today time.Time) {
+	if u.date.Equal(today) {
+		return
+	}
+	for _, usage := range u.pending {
+		u.leftover = append(u.leftover, usage)
+	}
+	u.pending = make(map[uint64]dmodels.APIKeyUsage)
+	u.stored = make(map[uint64]uint64)
+	u.date = today
+}
+
+func hashAPIKey(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(h[:])
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
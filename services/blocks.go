@@ -13,46 +13,22 @@ import (
 const topProposedBlocksValidatorsKey = "topProposedBlocksValidatorsKey"
 const rewardPerBlock = 4.0
 
-func (s *ServiceFacade) GetAggBlocksCount(filter filters.Agg) (items []smodels.AggItem, err error) {
-	items, err = s.dao.GetAggBlocksCount(filter)
-	if err != nil {
-		return nil, fmt.Errorf("dao.GetAggBlocksCount: %s", err.Error())
-	}
-	return items, nil
-}
-
-func (s *ServiceFacade) GetAggBlocksDelay(filter filters.Agg) (items []smodels.AggItem, err error) {
-	items, err = s.dao.GetAggBlocksDelay(filter)
-	if err != nil {
-		return nil, fmt.Errorf("dao.GetAggBlocksDelay: %s", err.Error())
-	}
-	return items, nil
-}
-
-func (s *ServiceFacade) GetAggUniqBlockValidators(filter filters.Agg) (items []smodels.AggItem, err error) {
-	items, err = s.dao.GetAggUniqBlockValidators(filter)
-	if err != nil {
-		return nil, fmt.Errorf("dao.GetAggUniqBlockValidators: %s", err.Error())
-	}
-	return items, nil
-}
-
 func (s *ServiceFacade) GetValidatorBlocksStat(validatorAddress string) (stat smodels.ValidatorBlocksStat, err error) {
 	validator, err := s.GetValidator(validatorAddress)
 	if err != nil {
-		return stat, fmt.Errorf("GetValidator: %s", err.Error())
+		return stat, fmt.Errorf("GetValidator: %w", err)
 	}
 	stat.Proposed, err = s.dao.GetProposedBlocksTotal(filters.BlocksProposed{
 		Proposers: []string{validator.ConsAddress},
 	})
 	if err != nil {
-		return stat, fmt.Errorf("dao.GetProposedBlocksTotal: %s", err.Error())
+		return stat, fmt.Errorf("dao.GetProposedBlocksTotal: %w", err)
 	}
 	stat.MissedValidations, err = s.dao.GetMissedBlocksCount(filters.MissedBlocks{
 		Validators: []string{validator.ConsAddress},
 	})
 	if err != nil {
-		return stat, fmt.Errorf("dao.GetMissedBlocksCount: %s", err.Error())
+		return stat, fmt.Errorf("dao.GetMissedBlocksCount: %w", err)
 	}
 	stat.Revenue = decimal.NewFromFloat(rewardPerBlock).Mul(decimal.NewFromInt(int64(stat.Proposed)))
 	return stat, nil
@@ -61,19 +37,19 @@ func (s *ServiceFacade) GetValidatorBlocksStat(validatorAddress string) (stat sm
 func (s *ServiceFacade) GetBlock(height uint64) (block smodels.Block, err error) {
 	dBlock, err := s.node.GetBlock(height)
 	if err != nil {
-		return block, fmt.Errorf("node.GetBlock: %s", err.Error())
+		return block, fmt.Errorf("node.GetBlock: %w", nodeLookupError(err, "block"))
 	}
 	validators, err := s.getConsensusValidatorMap()
 	if err != nil {
-		return block, fmt.Errorf("s.getConsensusValidatorMap: %s", err.Error())
+		return block, fmt.Errorf("s.getConsensusValidatorMap: %w", err)
 	}
 	proposerKey, err := helpers.B64ToHex(dBlock.Block.Header.ProposerAddress)
 	if err != nil {
-		return block, fmt.Errorf("helpers.B64ToHex: %s", err.Error())
+		return block, fmt.Errorf("helpers.B64ToHex: %w", err)
 	}
 	hashHex, err := helpers.B64ToHex(dBlock.BlockID.Hash)
 	if err != nil {
-		return block, fmt.Errorf("helpers.B64ToHex: %s", err.Error())
+		return block, fmt.Errorf("helpers.B64ToHex: %w", err)
 	}
 	var proposer, proposerAddress string
 	validator, ok := validators[strings.ToUpper(proposerKey)]
@@ -83,7 +59,7 @@ func (s *ServiceFacade) GetBlock(height uint64) (block smodels.Block, err error)
 	}
 	dTxs, err := s.dao.GetTransactions(filters.Transactions{Height: height})
 	if err != nil {
-		return block, fmt.Errorf("dao.GetTransactions: %s", err.Error())
+		return block, fmt.Errorf("dao.GetTransactions: %w", err)
 	}
 	var txs []smodels.TxItem
 	for _, tx := range dTxs {
@@ -111,15 +87,15 @@ func (s *ServiceFacade) GetBlock(height uint64) (block smodels.Block, err error)
 func (s *ServiceFacade) GetBlocks(filter filters.Blocks) (resp smodels.PaginatableResponse, err error) {
 	dBlocks, err := s.dao.GetBlocks(filter)
 	if err != nil {
-		return resp, fmt.Errorf("dao.GetBlocks: %s", err.Error())
+		return resp, fmt.Errorf("dao.GetBlocks: %w", err)
 	}
 	total, err := s.dao.GetBlocksCount(filter)
 	if err != nil {
-		return resp, fmt.Errorf("dao.GetBlocksCount: %s", err.Error())
+		return resp, fmt.Errorf("dao.GetBlocksCount: %w", err)
 	}
 	validators, err := s.getConsensusValidatorMap()
 	if err != nil {
-		return resp, fmt.Errorf("s.getConsensusValidatorMap: %s", err.Error())
+		return resp, fmt.Errorf("s.getConsensusValidatorMap: %w", err)
 	}
 	var blocks []smodels.BlockItem
 	for _, b := range dBlocks {
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"bytes"
+	"encoding/gob"
+	"github.com/everstake/cosmoscan-api/dao/cache"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// gobBasics are registered by gob itself
+var gobBasics = map[string]bool{
+	"bool": true, "string": true, "float64": true, "int64": true, "uint64": true, "int": true, "uint": true,
+}
+
+func TestCachedTypesRoundTrip(t *testing.T) {
+	cache.Register(cachedTypes...)
+	type envelope struct {
+		Value interface{}
+	}
+	for _, v := range cachedTypes {
+		var buf bytes.Buffer
+		err := gob.NewEncoder(&buf).Encode(envelope{Value: v})
+		if err != nil {
+			t.Errorf("%T: gob.Encode: %s", v, err)
+			continue
+		}
+		var e envelope
+		err = gob.NewDecoder(&buf).Decode(&e)
+		if err != nil {
+			t.Errorf("%T: gob.Decode: %s", v, err)
+			continue
+		}
+		if reflect.TypeOf(e.Value) != reflect.TypeOf(v) {
+			t.Errorf("%T: decoded as %T", v, e.Value)
+		}
+	}
+}
+
+// TestCachedTypesRegistered looks for the type assertions of the values returned by CacheLoad and CacheGet,
+// each asserted type has to be in cachedTypes
+func TestCachedTypesRegistered(t *testing.T) {
+	registered := make(map[string]bool)
+	for _, v := range cachedTypes {
+		registered[reflect.TypeOf(v).String()] = true
+	}
+	pkgs, err := parser.ParseDir(token.NewFileSet(), ".", func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var asserted int
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Body == nil {
+					continue
+				}
+				for _, typ := range cachedAssertions(fn.Body) {
+					asserted++
+					if !registered[typ] && !gobBasics[typ] {
+						t.Errorf("%s: cached %s is not in cachedTypes", fn.Name.Name, typ)
+					}
+				}
+			}
+		}
+	}
+	if asserted == 0 {
+		t.Error("no cached values found")
+	}
+}
+
+// cachedAssertions returns the types asserted on the variables assigned from CacheLoad and CacheGet
+func cachedAssertions(body *ast.BlockStmt) (typs []string) {
+	vars := make(map[string]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Rhs) != 1 || len(assign.Lhs) == 0 {
+			return true
+		}
+		call, ok := assign.Rhs[0].(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || (sel.Sel.Name != "CacheLoad" && sel.Sel.Name != "CacheGet") {
+			return true
+		}
+		if ident, ok := assign.Lhs[0].(*ast.Ident); ok {
+			vars[ident.Name] = true
+		}
+		return true
+	})
+	ast.Inspect(body, func(n ast.Node) bool {
+		assertion, ok := n.(*ast.TypeAssertExpr)
+		if !ok || assertion.Type == nil {
+			return true
+		}
+		if ident, ok := assertion.X.(*ast.Ident); ok && vars[ident.Name] {
+			typs = append(typs, types.ExprString(assertion.Type))
+		}
+		return true
+	})
+	return typs
+}
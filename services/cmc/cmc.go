@@ -4,24 +4,55 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/everstake/cosmoscan-api/config"
+	"github.com/everstake/cosmoscan-api/dmodels"
 	"github.com/shopspring/decimal"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 )
 
-const apiURL = "https://pro-api.coinmarketcap.com"
+const (
+	apiURL = "https://pro-api.coinmarketcap.com"
+	source = "cmc"
+	// atomID is the id of atom on coinmarketcap
+	atomID = "3794"
+)
 
 type (
 	CMC struct {
 		cfg    config.Config
 		client *http.Client
 	}
+	Status struct {
+		ErrorCode    int    `json:"error_code"`
+		ErrorMessage string `json:"error_message,omitempty"`
+	}
 	CurrenciesResponse struct {
-		Status struct {
-			ErrorCode    int    `json:"error_code"`
-			ErrorMessage string `json:"error_message,omitempty"`
-		} `json:"status"`
-		Data []Currency `json:"data"`
+		Status Status     `json:"status"`
+		Data   []Currency `json:"data"`
+	}
+	Quote struct {
+		Price       decimal.Decimal `json:"price"`
+		Volume24h   decimal.Decimal `json:"volume_24h"`
+		LastUpdated time.Time       `json:"last_updated"`
+		Timestamp   time.Time       `json:"timestamp"`
+	}
+	QuotesResponse struct {
+		Status Status `json:"status"`
+		Data   map[string]struct {
+			Quote map[string]Quote `json:"quote"`
+		} `json:"data"`
+	}
+	HistoricalQuotesResponse struct {
+		Status Status `json:"status"`
+		Data   map[string]struct {
+			Quotes []struct {
+				Timestamp time.Time        `json:"timestamp"`
+				Quote     map[string]Quote `json:"quote"`
+			} `json:"quotes"`
+		} `json:"data"`
 	}
 	Currency struct {
 		CirculatingSupply decimal.Decimal `json:"circulating_supply"`
@@ -41,7 +72,7 @@ type (
 
 func NewCMC(cfg config.Config) *CMC {
 	return &CMC{
-		client: &http.Client{},
+		client: &http.Client{Timeout: time.Second * 10},
 		cfg:    cfg,
 	}
 }
@@ -50,21 +81,22 @@ func (cmc *CMC) request(endpoint string, data interface{}) error {
 	url := fmt.Sprintf("%s%s", apiURL, endpoint)
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
-		return fmt.Errorf("http.NewRequest: %s", err.Error())
+		return fmt.Errorf("http.NewRequest: %w", err)
 	}
 	req.Header.Set("Accepts", "application/json")
 	req.Header.Set("X-CMC_PRO_API_KEY", cmc.cfg.CMCKey)
 	resp, err := cmc.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("client.Do: %s", err.Error())
+		return fmt.Errorf("client.Do: %w", err)
 	}
+	defer resp.Body.Close()
 	d, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("ioutil.ReadAll: %s", err.Error())
+		return fmt.Errorf("ioutil.ReadAll: %w", err)
 	}
 	err = json.Unmarshal(d, data)
 	if err != nil {
-		return fmt.Errorf("json.Unmarshal: %s", err.Error())
+		return fmt.Errorf("json.Unmarshal: %w", err)
 	}
 	return nil
 }
@@ -77,3 +109,67 @@ func (cmc *CMC) GetCurrencies() (currencies []Currency, err error) {
 	}
 	return currencyResp.Data, err
 }
+
+// GetMarketData returns the current atom price and the 24h trading volume in every currency,
+// one request per currency as the basic plan allows a single conversion per request
+func (cmc *CMC) GetMarketData(currencies []string) (prices []dmodels.Price, err error) {
+	for _, currency := range currencies {
+		convert := strings.ToUpper(currency)
+		params := url.Values{}
+		params.Set("id", atomID)
+		params.Set("convert", convert)
+		var resp QuotesResponse
+		err = cmc.request("/v2/cryptocurrency/quotes/latest?"+params.Encode(), &resp)
+		if err != nil {
+			return nil, fmt.Errorf("request: %w", err)
+		}
+		if resp.Status.ErrorCode != 0 {
+			return nil, fmt.Errorf("error code: %d, msg: %s", resp.Status.ErrorCode, resp.Status.ErrorMessage)
+		}
+		quote, ok := resp.Data[atomID].Quote[convert]
+		if !ok {
+			return nil, fmt.Errorf("not found price in %s", currency)
+		}
+		prices = append(prices, dmodels.Price{
+			Currency: currency,
+			Price:    quote.Price,
+			Volume:   quote.Volume24h,
+			Source:   source,
+			Time:     dmodels.NewTime(quote.LastUpdated),
+		})
+	}
+	return prices, nil
+}
+
+// GetPriceHistory returns the hourly atom prices between from and to
+func (cmc *CMC) GetPriceHistory(currency string, from, to time.Time) (prices []dmodels.Price, err error) {
+	convert := strings.ToUpper(currency)
+	params := url.Values{}
+	params.Set("id", atomID)
+	params.Set("convert", convert)
+	params.Set("time_start", from.UTC().Format(time.RFC3339))
+	params.Set("time_end", to.UTC().Format(time.RFC3339))
+	params.Set("interval", "hourly")
+	var resp HistoricalQuotesResponse
+	err = cmc.request("/v2/cryptocurrency/quotes/historical?"+params.Encode(), &resp)
+	if err != nil {
+		return nil, fmt.Errorf("request: %w", err)
+	}
+	if resp.Status.ErrorCode != 0 {
+		return nil, fmt.Errorf("error code: %d, msg: %s", resp.Status.ErrorCode, resp.Status.ErrorMessage)
+	}
+	for _, item := range resp.Data[atomID].Quotes {
+		quote, ok := item.Quote[convert]
+		if !ok {
+			continue
+		}
+		prices = append(prices, dmodels.Price{
+			Currency: currency,
+			Price:    quote.Price,
+			Volume:   quote.Volume24h,
+			Source:   source,
+			Time:     dmodels.NewTime(item.Timestamp),
+		})
+	}
+	return prices, nil
+}
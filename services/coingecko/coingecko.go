@@ -1,39 +1,106 @@
 package coingecko
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/everstake/cosmoscan-api/dmodels"
 	"github.com/shopspring/decimal"
 	coingecko "github.com/superoo7/go-gecko/v3"
 	"net/http"
+	"net/url"
 	"time"
 )
 
 const (
 	coinID = "cosmos"
+	apiURL = "https://api.coingecko.com/api/v3"
+	source = "coingecko"
 )
 
-type CoinGecko struct {
-	client *coingecko.Client
-}
+type (
+	CoinGecko struct {
+		client     *coingecko.Client
+		httpClient *http.Client
+	}
+	// marketChart items are pairs of the timestamp in milliseconds and the value
+	marketChart struct {
+		Prices       [][2]decimal.Decimal `json:"prices"`
+		TotalVolumes [][2]decimal.Decimal `json:"total_volumes"`
+	}
+)
 
 func NewGecko() *CoinGecko {
 	httpClient := &http.Client{
 		Timeout: time.Second * 10,
 	}
 	return &CoinGecko{
-		client: coingecko.NewClient(httpClient),
+		client:     coingecko.NewClient(httpClient),
+		httpClient: httpClient,
 	}
 }
 
-func (g CoinGecko) GetMarketData() (price, volume24h decimal.Decimal, err error) {
+// GetMarketData returns the current price and the 24h trading volume in every currency
+func (g CoinGecko) GetMarketData(currencies []string) (prices []dmodels.Price, err error) {
 	data, err := g.client.CoinsID(coinID, false, true, true, false, false, false)
 	if err != nil {
-		return price, volume24h, fmt.Errorf("client.CoinsID: %s", err.Error())
+		return nil, fmt.Errorf("client.CoinsID: %w", err)
 	}
 	if data.MarketData.MarketCap == nil {
-		return price, volume24h, errors.New("MarketData.MarketCap is nil")
+		return nil, errors.New("MarketData.MarketCap is nil")
 	}
+	t := time.Now()
+	if updated, err := time.Parse(time.RFC3339, data.MarketData.LastUpdated); err == nil {
+		t = updated
+	}
+	for _, currency := range currencies {
+		price, ok := data.MarketData.CurrentPrice[currency]
+		if !ok {
+			return nil, fmt.Errorf("not found price in %s", currency)
+		}
+		prices = append(prices, dmodels.Price{
+			Currency: currency,
+			Price:    decimal.NewFromFloat(price),
+			Volume:   decimal.NewFromFloat(data.MarketData.TotalVolume[currency]),
+			Source:   source,
+			Time:     dmodels.NewTime(t),
+		})
+	}
+	return prices, nil
+}
 
-	return decimal.NewFromFloat(data.MarketData.CurrentPrice["usd"]), decimal.NewFromFloat(data.MarketData.TotalVolume["usd"]), nil
-}
\ No newline at end of file
+// GetPriceHistory returns the prices between from and to, hourly for ranges up to 90 days and daily for longer ones
+func (g CoinGecko) GetPriceHistory(currency string, from, to time.Time) (prices []dmodels.Price, err error) {
+	params := url.Values{}
+	params.Set("vs_currency", currency)
+	params.Set("from", fmt.Sprintf("%d", from.Unix()))
+	params.Set("to", fmt.Sprintf("%d", to.Unix()))
+	resp, err := g.httpClient.Get(fmt.Sprintf("%s/coins/%s/market_chart/range?%s", apiURL, coinID, params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("httpClient.Get: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bad status code: %d", resp.StatusCode)
+	}
+	var chart marketChart
+	err = json.NewDecoder(resp.Body).Decode(&chart)
+	if err != nil {
+		return nil, fmt.Errorf("json.Decode: %w", err)
+	}
+	volumes := make(map[int64]decimal.Decimal)
+	for _, item := range chart.TotalVolumes {
+		volumes[item[0].IntPart()] = item[1]
+	}
+	for _, item := range chart.Prices {
+		ms := item[0].IntPart()
+		prices = append(prices, dmodels.Price{
+			Currency: currency,
+			Price:    item[1],
+			Volume:   volumes[ms],
+			Source:   source,
+			Time:     dmodels.NewTime(time.Unix(0, ms*int64(time.Millisecond))),
+		})
+	}
+	return prices, nil
+}
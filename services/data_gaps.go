@@ -0,0 +1,239 @@
+package services
+
+import (
+	"fmt"
+	"github.com/everstake/cosmoscan-api/config"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/everstake/cosmoscan-api/log"
+	"github.com/everstake/cosmoscan-api/smodels"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+const (
+	dataGapsDefaultRange = time.Hour * 24 * 30
+	dataGapsMaxRange     = time.Hour * 24 * 366
+	// statsDelay leaves time for MakeStats to save the stats of the day before they are counted as missing
+	statsDelay = time.Hour * 3
+)
+
+// GetDataGaps returns the days without stats and the hours without historical states in the time range,
+// the last 30 days by default
+func (s *ServiceFacade) GetDataGaps(filter filters.TimeRange) (gaps []smodels.DataGap, err error) {
+	return s.dataGaps(filter, false)
+}
+
+// RepairDataGaps recomputes the missing stats and historical states which are derivable from the stored data
+// and returns all the gaps, the ones left are not derivable
+func (s *ServiceFacade) RepairDataGaps(filter filters.TimeRange) (gaps []smodels.DataGap, err error) {
+	return s.dataGaps(filter, true)
+}
+
+// RepairLastDataGaps repairs the gaps of the last 30 days
+func (s *ServiceFacade) RepairLastDataGaps() {
+	gaps, err := s.RepairDataGaps(filters.TimeRange{})
+	if err != nil {
+		log.Error("RepairLastDataGaps: RepairDataGaps: %s", err.Error())
+		return
+	}
+	var repaired int
+	for _, gap := range gaps {
+		if gap.Repaired {
+			repaired++
+		}
+	}
+	if len(gaps) != 0 {
+		log.Warn("RepairLastDataGaps: found %d gaps, repaired %d, not derivable %d", len(gaps), repaired, len(gaps)-repaired)
+	}
+}
+
+func (s *ServiceFacade) dataGaps(filter filters.TimeRange, repair bool) (gaps []smodels.DataGap, err error) {
+	to := time.Now()
+	if !filter.To.IsZero() && filter.To.Before(to) {
+		to = filter.To.Time
+	}
+	from := to.Add(-dataGapsDefaultRange)
+	if !filter.From.IsZero() {
+		from = filter.From.Time
+	}
+	if !from.Before(to) {
+		return nil, derrors.InvalidArgument("from should be before to")
+	}
+	if to.Sub(from) > dataGapsMaxRange {
+		return nil, derrors.InvalidArgument("over max limit range")
+	}
+	gaps, err = s.statsGaps(from, to, repair)
+	if err != nil {
+		return nil, fmt.Errorf("statsGaps: %w", err)
+	}
+	statesGaps, err := s.historicalStatesGaps(from, to, repair)
+	if err != nil {
+		return nil, fmt.Errorf("historicalStatesGaps: %w", err)
+	}
+	return append(gaps, statesGaps...), nil
+}
+
+// statsGaps looks for the stats missing at the ends of the days, which are the times of the stats
+func (s *ServiceFacade) statsGaps(from, to time.Time, repair bool) (gaps []smodels.DataGap, err error) {
+	stats := s.dayStats()
+	titles := make([]string, len(stats))
+	for i, stat := range stats {
+		titles[i] = stat.title
+	}
+	stored, err := s.dao.GetStats(filters.Stats{
+		Titles: titles,
+		From:   dmodels.NewTime(from),
+		To:     dmodels.NewTime(to),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetStats: %w", err)
+	}
+	found := make(map[string]bool)
+	for _, stat := range stored {
+		found[fmt.Sprintf("%s.%d", stat.Title, stat.CreatedAt.Unix())] = true
+	}
+	day := from.UTC().Truncate(time.Hour * 24)
+	if day.Before(from) {
+		day = day.Add(time.Hour * 24)
+	}
+	last := time.Now().Add(-statsDelay)
+	for ; !day.After(to) && day.Before(last); day = day.Add(time.Hour * 24) {
+		var missing []dayStat
+		dayGaps := len(gaps)
+		for _, stat := range stats {
+			if found[fmt.Sprintf("%s.%d", stat.title, day.Unix())] {
+				continue
+			}
+			gaps = append(gaps, smodels.DataGap{
+				Table:     dmodels.StatsTable,
+				Title:     stat.title,
+				Time:      dmodels.NewTime(day),
+				Derivable: stat.derivable,
+			})
+			if stat.derivable {
+				missing = append(missing, stat)
+			}
+		}
+		if !repair || len(missing) == 0 {
+			continue
+		}
+		models := s.makeDayStats(missing, day)
+		err = s.dao.CreateStats(models)
+		if err != nil {
+			return nil, fmt.Errorf("dao.CreateStats: %w", err)
+		}
+		repaired := make(map[string]bool)
+		for _, model := range models {
+			repaired[model.Title] = true
+		}
+		for i := dayGaps; i < len(gaps); i++ {
+			gaps[i].Repaired = repaired[gaps[i].Title]
+		}
+	}
+	return gaps, nil
+}
+
+// historicalStatesGaps looks for the hours without historical states, a state is derivable when the usd price
+// and the supply snapshot of the hour are stored, the inflation rate and the top 20 weight are taken from the previous state
+func (s *ServiceFacade) historicalStatesGaps(from, to time.Time, repair bool) (gaps []smodels.DataGap, err error) {
+	states, err := s.dao.GetHistoricalStates(filters.HistoricalState{
+		From: dmodels.NewTime(from),
+		To:   dmodels.NewTime(to),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetHistoricalStates: %w", err)
+	}
+	previous, err := s.dao.GetHistoricalStates(filters.HistoricalState{
+		To:    dmodels.NewTime(from.Add(-time.Second)),
+		Limit: 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetHistoricalStates: %w", err)
+	}
+	prices, err := s.dao.GetPrices(filters.Prices{
+		Currency: config.QuoteCurrency,
+		From:     dmodels.NewTime(from),
+		To:       dmodels.NewTime(to),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetPrices: %w", err)
+	}
+	supplies, err := s.dao.GetAggSupplySnapshots(filters.Agg{
+		By:   filters.AggByHour,
+		From: dmodels.NewTime(from),
+		To:   dmodels.NewTime(to),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetAggSupplySnapshots: %w", err)
+	}
+	statesMap := make(map[int64]dmodels.HistoricalState)
+	for _, state := range states {
+		statesMap[state.CreatedAt.Truncate(time.Hour).Unix()] = state
+	}
+	pricesMap := make(map[int64]dmodels.Price)
+	for _, price := range prices {
+		pricesMap[price.Time.Unix()] = price
+	}
+	suppliesMap := make(map[int64]dmodels.SupplySnapshot)
+	for _, supply := range supplies {
+		suppliesMap[supply.CreatedAt.Unix()] = supply
+	}
+	var last dmodels.HistoricalState
+	if len(previous) != 0 {
+		last = previous[0]
+	}
+	var models []dmodels.HistoricalState
+	hour := from.UTC().Truncate(time.Hour)
+	if hour.Before(from) {
+		hour = hour.Add(time.Hour)
+	}
+	current := time.Now().Truncate(time.Hour)
+	for ; hour.Before(to) && hour.Before(current); hour = hour.Add(time.Hour) {
+		if state, ok := statesMap[hour.Unix()]; ok {
+			last = state
+			continue
+		}
+		price, okPrice := pricesMap[hour.Unix()]
+		supply, okSupply := suppliesMap[hour.Unix()]
+		gap := smodels.DataGap{
+			Table:     dmodels.HistoricalStates,
+			Time:      dmodels.NewTime(hour),
+			Derivable: okPrice && okSupply,
+		}
+		if repair && gap.Derivable {
+			state := deriveHistoricalState(last, price, supply)
+			models = append(models, state)
+			last = state
+			gap.Repaired = true
+		}
+		gaps = append(gaps, gap)
+	}
+	if len(models) != 0 {
+		err = s.dao.CreateHistoricalStates(models)
+		if err != nil {
+			return nil, fmt.Errorf("dao.CreateHistoricalStates: %w", err)
+		}
+	}
+	return gaps, nil
+}
+
+// deriveHistoricalState makes the state of the hour of the supply snapshot from the price and the snapshot,
+// the values which are not stored anywhere else are taken from the previous state
+func deriveHistoricalState(previous dmodels.HistoricalState, price dmodels.Price, supply dmodels.SupplySnapshot) dmodels.HistoricalState {
+	state := dmodels.HistoricalState{
+		Price:             price.Price,
+		TradingVolume:     price.Volume,
+		CirculatingSupply: supply.Circulating.Truncate(2),
+		CommunityPool:     supply.CommunityPool.Truncate(2),
+		InflationRate:     previous.InflationRate,
+		Top20Weight:       previous.Top20Weight,
+		CreatedAt:         supply.CreatedAt,
+	}
+	if !supply.Total.IsZero() {
+		state.StakedRatio = supply.Bonded.Div(supply.Total).Mul(decimal.New(100, 0)).Truncate(2)
+	}
+	state.MarketCap = state.CirculatingSupply.Mul(state.Price).Truncate(2)
+	return state
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/shopspring/decimal"
+	"testing"
+	"time"
+)
+
+func TestDeriveHistoricalState(t *testing.T) {
+	hour := dmodels.NewTime(time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC))
+	previous := dmodels.HistoricalState{InflationRate: decimal.New(7, 0), Top20Weight: decimal.New(40, 0)}
+	price := dmodels.Price{Price: decimal.New(20, 0), Volume: decimal.New(1000, 0), Time: hour}
+	supply := dmodels.SupplySnapshot{
+		Total:         decimal.New(300, 0),
+		Bonded:        decimal.New(200, 0),
+		CommunityPool: decimal.New(10, 0),
+		Circulating:   decimal.New(250, 0),
+		CreatedAt:     hour,
+	}
+	state := deriveHistoricalState(previous, price, supply)
+	expected := map[string][2]decimal.Decimal{
+		"price":         {state.Price, decimal.New(20, 0)},
+		"volume":        {state.TradingVolume, decimal.New(1000, 0)},
+		"market cap":    {state.MarketCap, decimal.New(5000, 0)},
+		"staked ratio":  {state.StakedRatio, decimal.NewFromFloat(66.66)},
+		"inflation":     {state.InflationRate, decimal.New(7, 0)},
+		"top 20 weight": {state.Top20Weight, decimal.New(40, 0)},
+	}
+	for name, values := range expected {
+		if !values[0].Equal(values[1]) {
+			t.Errorf("%s: expected %s, got %s", name, values[1], values[0])
+		}
+	}
+	if !state.CreatedAt.Equal(hour.Time) {
+		t.Errorf("expected state at %s, got %s", hour, state.CreatedAt)
+	}
+}
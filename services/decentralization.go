@@ -0,0 +1,216 @@
+package services
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/everstake/cosmoscan-api/log"
+	"github.com/everstake/cosmoscan-api/services/node"
+	"github.com/everstake/cosmoscan-api/smodels"
+	"github.com/shopspring/decimal"
+	"sort"
+	"strings"
+	"time"
+)
+
+const decentralizationCacheKey = "decentralization"
+
+var nakamoto66Limit = decimal.NewFromFloat(66.7)
+
+// MakeDecentralizationStats saves the decentralization metrics of the validator set at the start of the day
+func (s *ServiceFacade) MakeDecentralizationStats() {
+	y, m, d := time.Now().UTC().Date()
+	startOfToday := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	metrics, err := s.makeDecentralization(startOfToday)
+	if err != nil {
+		log.Error("MakeDecentralizationStats: makeDecentralization: %s", err.Error())
+		return
+	}
+	var stats []dmodels.Stat
+	for _, entities := range []bool{false, true} {
+		values := metrics.Validators.Values()
+		if entities {
+			values = metrics.Entities.Values()
+		}
+		for _, metric := range dmodels.DecentralizationMetrics {
+			title := dmodels.DecentralizationStatTitle(metric, entities)
+			hash := sha1.Sum([]byte(fmt.Sprintf("%s.%s", title, startOfToday.String())))
+			stats = append(stats, dmodels.Stat{
+				ID:        hex.EncodeToString(hash[:]),
+				Title:     title,
+				Value:     values[metric],
+				CreatedAt: startOfToday,
+			})
+		}
+	}
+	err = s.dao.CreateStats(stats)
+	if err != nil {
+		log.Error("MakeDecentralizationStats: dao.CreateStats: %s", err.Error())
+	}
+}
+
+func (s *ServiceFacade) GetDecentralization() (metrics smodels.Decentralization, err error) {
+	data, err := s.dao.CacheLoad(decentralizationCacheKey, time.Minute*10, func() (interface{}, error) {
+		return s.makeDecentralization(time.Now())
+	})
+	if err != nil {
+		return metrics, fmt.Errorf("makeDecentralization: %w", err)
+	}
+	return data.(smodels.Decentralization), nil
+}
+
+func (s *ServiceFacade) GetAggDecentralization(filter filters.DecentralizationAgg) (items []smodels.AggItem, err error) {
+	found := false
+	for _, metric := range dmodels.DecentralizationMetrics {
+		found = found || metric == filter.Metric
+	}
+	if !found {
+		return nil, derrors.InvalidArgument("unknown metric, use one of: %s", strings.Join(dmodels.DecentralizationMetrics, ", "))
+	}
+	items, err = s.dao.GetAggStats(filters.StatsAgg{
+		Agg:   filter.Agg,
+		Title: dmodels.DecentralizationStatTitle(filter.Metric, filter.Entities),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetAggStats: %w", err)
+	}
+	return items, nil
+}
+
+func (s *ServiceFacade) makeDecentralization(at time.Time) (metrics smodels.Decentralization, err error) {
+	powers, err := s.getValidatorSetPowers(at)
+	if err != nil {
+		return metrics, fmt.Errorf("getValidatorSetPowers: %w", err)
+	}
+	entities, err := s.getValidatorEntities()
+	if err != nil {
+		return metrics, fmt.Errorf("getValidatorEntities: %w", err)
+	}
+	var validatorPowers []decimal.Decimal
+	entityPowers := make(map[string]decimal.Decimal)
+	for validator, power := range powers {
+		validatorPowers = append(validatorPowers, power)
+		entity, ok := entities[validator]
+		if !ok {
+			entity = validator
+		}
+		entityPowers[entity] = entityPowers[entity].Add(power)
+	}
+	var grouped []decimal.Decimal
+	for _, power := range entityPowers {
+		grouped = append(grouped, power)
+	}
+	metrics.Validators = decentralizationMetrics(validatorPowers)
+	metrics.Entities = decentralizationMetrics(grouped)
+	return metrics, nil
+}
+
+// getValidatorSetPowers returns the voting power of the active set (by operator address) at the moment,
+// the bonded validators of the node are used when the set has not been tracked yet
+func (s *ServiceFacade) getValidatorSetPowers(at time.Time) (map[string]decimal.Decimal, error) {
+	validatorsMap, err := s.GetValidatorMap()
+	if err != nil {
+		return nil, fmt.Errorf("GetValidatorMap: %w", err)
+	}
+	powers := make(map[string]decimal.Decimal)
+	set, err := s.dao.GetValidatorsPowers(filters.ValidatorPowers{To: dmodels.NewTime(at)})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetValidatorsPowers: %w", err)
+	}
+	if len(set) == 0 {
+		for _, v := range validatorsMap {
+			if v.Status == node.BondedValidatorStatus {
+				powers[v.OperatorAddress] = v.DelegatorShares.Div(node.PrecisionDiv)
+			}
+		}
+		return powers, nil
+	}
+	consValidators, err := s.getConsensusValidatorMap()
+	if err != nil {
+		return nil, fmt.Errorf("getConsensusValidatorMap: %w", err)
+	}
+	for _, item := range set {
+		address := item.Validator
+		if v, ok := consValidators[address]; ok {
+			address = v.OperatorAddress
+		}
+		powers[address] = powers[address].Add(item.Power)
+	}
+	return powers, nil
+}
+
+// getValidatorEntities maps operator addresses to the entity running the validator:
+// the label of the operator or self-delegation address, otherwise the keybase identity shared by the validators
+func (s *ServiceFacade) getValidatorEntities() (map[string]string, error) {
+	validators, err := s.GetValidators()
+	if err != nil {
+		return nil, fmt.Errorf("GetValidators: %w", err)
+	}
+	validatorsMap, err := s.GetValidatorMap()
+	if err != nil {
+		return nil, fmt.Errorf("GetValidatorMap: %w", err)
+	}
+	labels, err := s.getAddressLabelsMap()
+	if err != nil {
+		return nil, fmt.Errorf("getAddressLabelsMap: %w", err)
+	}
+	entities := make(map[string]string)
+	for _, v := range validators {
+		if label, ok := labels[v.OperatorAddress]; ok {
+			entities[v.OperatorAddress] = "label:" + label.Entity
+			continue
+		}
+		if label, ok := labels[v.AccAddress]; ok {
+			entities[v.OperatorAddress] = "label:" + label.Entity
+			continue
+		}
+		identity := strings.TrimSpace(validatorsMap[v.OperatorAddress].Description.Identity)
+		if identity != "" {
+			entities[v.OperatorAddress] = "identity:" + strings.ToUpper(identity)
+		}
+	}
+	return entities, nil
+}
+
+// decentralizationMetrics computes the concentration of the voting power,
+// HHI is the sum of squared shares in percents (10000 for a single validator)
+func decentralizationMetrics(powers []decimal.Decimal) (metrics smodels.DecentralizationMetrics) {
+	sort.Slice(powers, func(i, j int) bool {
+		return powers[i].GreaterThan(powers[j])
+	})
+	total := decimal.Zero
+	for _, power := range powers {
+		total = total.Add(power)
+	}
+	metrics.Count = uint64(len(powers))
+	if total.IsZero() {
+		return metrics
+	}
+	metrics.Nakamoto33 = nakamotoCoefficient(powers, nakamotoLimit)
+	metrics.Nakamoto66 = nakamotoCoefficient(powers, nakamoto66Limit)
+
+	n := decimal.NewFromInt(int64(len(powers)))
+	weighted := decimal.Zero // sum of powers weighted by the ascending rank
+	hhi := decimal.Zero
+	top10, top20 := decimal.Zero, decimal.Zero
+	for i, power := range powers {
+		share := power.Div(total).Mul(hundred)
+		hhi = hhi.Add(share.Mul(share))
+		if i < 10 {
+			top10 = top10.Add(share)
+		}
+		if i < 20 {
+			top20 = top20.Add(share)
+		}
+		weighted = weighted.Add(power.Mul(decimal.NewFromInt(int64(len(powers) - i))))
+	}
+	gini := decimal.New(2, 0).Mul(weighted).Div(n.Mul(total)).Sub(n.Add(decimal.New(1, 0)).Div(n))
+	metrics.Gini = nonNegative(gini).Truncate(4)
+	metrics.HHI = hhi.Truncate(2)
+	metrics.Top10Share = top10.Truncate(2)
+	metrics.Top20Share = top20.Truncate(2)
+	return metrics
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"github.com/shopspring/decimal"
+	"testing"
+)
+
+func TestDecentralizationMetrics(t *testing.T) {
+	d := decimal.RequireFromString
+	tests := []struct {
+		name       string
+		powers     []decimal.Decimal
+		nakamoto33 uint64
+		nakamoto66 uint64
+		gini       decimal.Decimal
+		hhi        decimal.Decimal
+		top10      decimal.Decimal
+	}{
+		{
+			name:       "equal",
+			powers:     []decimal.Decimal{d("10"), d("10"), d("10"), d("10")},
+			nakamoto33: 2,
+			nakamoto66: 3,
+			gini:       d("0"),
+			hhi:        d("2500"),
+			top10:      d("100"),
+		},
+		{
+			name:       "concentrated",
+			powers:     []decimal.Decimal{d("1"), d("97"), d("1"), d("1")},
+			nakamoto33: 1,
+			nakamoto66: 1,
+			gini:       d("0.72"),
+			hhi:        d("9412"),
+			top10:      d("100"),
+		},
+	}
+	for _, test := range tests {
+		m := decentralizationMetrics(test.powers)
+		if m.Nakamoto33 != test.nakamoto33 || m.Nakamoto66 != test.nakamoto66 {
+			t.Errorf("%s: unexpected nakamoto coefficients %d, %d", test.name, m.Nakamoto33, m.Nakamoto66)
+		}
+		if !m.Gini.Equal(test.gini) || !m.HHI.Equal(test.hhi) || !m.Top10Share.Equal(test.top10) {
+			t.Errorf("%s: unexpected gini %s, hhi %s, top 10 share %s", test.name, m.Gini, m.HHI, m.Top10Share)
+		}
+	}
+	if m := decentralizationMetrics(nil); m.Count != 0 || !m.Gini.IsZero() {
+		t.Errorf("empty set: unexpected metrics %+v", m)
+	}
+}
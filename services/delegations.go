@@ -2,25 +2,21 @@ package services
 
 import (
 	"fmt"
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/everstake/cosmoscan-api/dao/filters"
 	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/everstake/cosmoscan-api/services/node"
 	"github.com/everstake/cosmoscan-api/smodels"
 	"github.com/shopspring/decimal"
+	"sort"
 	"time"
 )
 
 func (s *ServiceFacade) GetAggDelegationsVolume(filter filters.DelegationsAgg) (items []smodels.AggItem, err error) {
 	items, err = s.dao.GetAggDelegationsVolume(filter)
 	if err != nil {
-		return nil, fmt.Errorf("dao.GetAggDelegationsVolume: %s", err.Error())
-	}
-	return items, nil
-}
-
-func (s *ServiceFacade) GetAggUndelegationsVolume(filter filters.Agg) (items []smodels.AggItem, err error) {
-	items, err = s.dao.GetAggUndelegationsVolume(filter)
-	if err != nil {
-		return nil, fmt.Errorf("dao.GetAggUndelegationsVolume: %s", err.Error())
+		return nil, fmt.Errorf("dao.GetAggDelegationsVolume: %w", err)
 	}
 	return items, nil
 }
@@ -28,7 +24,7 @@ func (s *ServiceFacade) GetAggUndelegationsVolume(filter filters.Agg) (items []s
 func (s *ServiceFacade) GetAggUnbondingVolume(filter filters.Agg) (items []smodels.AggItem, err error) {
 	undelegationItems, err := s.dao.GetAggUndelegationsVolume(filter)
 	if err != nil {
-		return nil, fmt.Errorf("dao.GetAggUndelegationsVolume: %s", err.Error())
+		return nil, fmt.Errorf("dao.GetAggUndelegationsVolume: %w", err)
 	}
 	items = make([]smodels.AggItem, len(undelegationItems))
 	for i, item := range undelegationItems {
@@ -37,7 +33,7 @@ func (s *ServiceFacade) GetAggUnbondingVolume(filter filters.Agg) (items []smode
 			To:   item.Time,
 		})
 		if err != nil {
-			return nil, fmt.Errorf("dao.GetUndelegationsVolume: %s", err.Error())
+			return nil, fmt.Errorf("dao.GetUndelegationsVolume: %w", err)
 		}
 		items[i] = smodels.AggItem{
 			Time:  item.Time,
@@ -50,7 +46,7 @@ func (s *ServiceFacade) GetAggUnbondingVolume(filter filters.Agg) (items []smode
 func (s *ServiceFacade) GetValidatorDelegationsAgg(validatorAddress string) (items []smodels.AggItem, err error) {
 	validator, err := s.GetValidator(validatorAddress)
 	if err != nil {
-		return nil, fmt.Errorf("GetValidator: %s", err.Error())
+		return nil, fmt.Errorf("GetValidator: %w", err)
 	}
 	items, err = s.dao.GetAggDelegationsAndUndelegationsVolume(filters.DelegationsAgg{
 		Agg: filters.Agg{
@@ -61,7 +57,7 @@ func (s *ServiceFacade) GetValidatorDelegationsAgg(validatorAddress string) (ite
 		Validators: []string{validatorAddress},
 	})
 	if err != nil {
-		return nil, fmt.Errorf("dao.GetAggDelegationsVolume: %s", err.Error())
+		return nil, fmt.Errorf("dao.GetAggDelegationsVolume: %w", err)
 	}
 	powerValue := validator.Power
 	for i := len(items) - 1; i >= 0; i-- {
@@ -83,7 +79,7 @@ func (s *ServiceFacade) GetValidatorDelegatorsAgg(validatorAddress string) (item
 			Validators: []string{validatorAddress},
 		})
 		if err != nil {
-			return nil, fmt.Errorf("dao.GetDelegatorsTotal: %s", err.Error())
+			return nil, fmt.Errorf("dao.GetDelegatorsTotal: %w", err)
 		}
 		items = append(items, smodels.AggItem{
 			Time:  dmodels.NewTime(date),
@@ -96,14 +92,140 @@ func (s *ServiceFacade) GetValidatorDelegatorsAgg(validatorAddress string) (item
 func (s *ServiceFacade) GetValidatorDelegators(filter filters.ValidatorDelegators) (resp smodels.PaginatableResponse, err error) {
 	items, err := s.dao.GetValidatorDelegators(filter)
 	if err != nil {
-		return resp, fmt.Errorf("dao.GetValidatorDelegators: %s", err.Error())
+		return resp, fmt.Errorf("dao.GetValidatorDelegators: %w", err)
 	}
 	total, err := s.dao.GetValidatorDelegatorsTotal(filter)
 	if err != nil {
-		return resp, fmt.Errorf("dao.GetValidatorDelegatorsTotal: %s", err.Error())
+		return resp, fmt.Errorf("dao.GetValidatorDelegatorsTotal: %w", err)
 	}
 	return smodels.PaginatableResponse{
 		Items: items,
 		Total: total,
 	}, nil
 }
+
+// GetAccountDelegations returns the delegations of the account per validator with the pending and withdrawn rewards,
+// the unbonding entries and the redelegation history
+func (s *ServiceFacade) GetAccountDelegations(address string) (resp smodels.AccountDelegations, err error) {
+	if _, err := types.AccAddressFromBech32(address); err != nil {
+		return resp, derrors.Wrap(derrors.CodeInvalidArgument, err, "invalid address")
+	}
+	delegations, err := s.node.GetDelegations(address)
+	if err != nil {
+		return resp, fmt.Errorf("node.GetDelegations: %w", nodeLookupError(err, "account"))
+	}
+	rewards, err := s.node.GetDelegatorRewards(address)
+	if err != nil {
+		return resp, fmt.Errorf("node.GetDelegatorRewards: %w", nodeLookupError(err, "account"))
+	}
+	unbondings, err := s.node.GetUnbondingDelegations(address)
+	if err != nil {
+		return resp, fmt.Errorf("node.GetUnbondingDelegations: %w", nodeLookupError(err, "account"))
+	}
+	stakes, err := s.dao.GetDelegationStakes(filters.DelegationStakes{Delegators: []string{address}})
+	if err != nil {
+		return resp, fmt.Errorf("dao.GetDelegationStakes: %w", err)
+	}
+	withdrawn, err := s.dao.GetValidatorsDelegatorRewards(filters.ValidatorRewards{Delegators: []string{address}})
+	if err != nil {
+		return resp, fmt.Errorf("dao.GetValidatorsDelegatorRewards: %w", err)
+	}
+	redelegations, err := s.dao.GetRedelegations(filters.Redelegations{Delegator: address})
+	if err != nil {
+		return resp, fmt.Errorf("dao.GetRedelegations: %w", err)
+	}
+	validators, err := s.GetValidatorMap()
+	if err != nil {
+		return resp, fmt.Errorf("GetValidatorMap: %w", err)
+	}
+	uptimes, err := s.GetValidatorsUptime()
+	if err != nil {
+		return resp, fmt.Errorf("GetValidatorsUptime: %w", err)
+	}
+
+	items := make(map[string]*smodels.AccountDelegation)
+	item := func(validator string) *smodels.AccountDelegation {
+		if d, ok := items[validator]; ok {
+			return d
+		}
+		d := &smodels.AccountDelegation{Validator: validator, Unbondings: []smodels.UnbondingEntry{}}
+		if v, ok := validators[validator]; ok {
+			d.Title = v.Description.Moniker
+			d.Commission = v.Commission.CommissionRates.Rate
+			d.Jailed = v.Jailed
+			d.Bonded = v.Status == node.BondedValidatorStatus
+		}
+		items[validator] = d
+		return d
+	}
+	for _, d := range delegations.DelegationResponses {
+		if d.Balance.Denom != node.MainUnit || d.Balance.Amount.IsZero() {
+			continue
+		}
+		item(d.Delegation.ValidatorAddress).Stake = d.Balance.Amount.Div(node.PrecisionDiv)
+	}
+	for _, u := range unbondings.UnbondingResponses {
+		d := item(u.ValidatorAddress)
+		for _, entry := range u.Entries {
+			d.Unbondings = append(d.Unbondings, smodels.UnbondingEntry{
+				CreationHeight: entry.CreationHeight,
+				CompletionTime: dmodels.NewTime(entry.CompletionTime),
+				InitialBalance: entry.InitialBalance.Div(node.PrecisionDiv),
+				Balance:        entry.Balance.Div(node.PrecisionDiv),
+			})
+			resp.Unbonding = resp.Unbonding.Add(entry.Balance.Div(node.PrecisionDiv))
+		}
+	}
+	for _, r := range rewards.Rewards {
+		d, ok := items[r.ValidatorAddress]
+		if !ok {
+			continue
+		}
+		for _, amount := range r.Reward {
+			if amount.Denom == node.MainUnit {
+				d.Rewards = d.Rewards.Add(amount.Amount.Div(node.PrecisionDiv))
+			}
+		}
+	}
+	for _, stake := range stakes {
+		if d, ok := items[stake.Validator]; ok {
+			d.Since = dmodels.NewTime(stake.Since)
+		}
+	}
+	for _, w := range withdrawn {
+		resp.Withdrawn = resp.Withdrawn.Add(w.Amount)
+		if d, ok := items[w.Validator]; ok {
+			d.Withdrawn = w.Amount
+		}
+	}
+	for _, u := range uptimes {
+		if d, ok := items[u.OperatorAddress]; ok {
+			d.Uptime = u.Uptime
+		}
+	}
+
+	resp.Address = address
+	resp.Delegations = make([]smodels.AccountDelegation, 0, len(items))
+	for _, d := range items {
+		resp.Delegated = resp.Delegated.Add(d.Stake)
+		resp.Rewards = resp.Rewards.Add(d.Rewards)
+		resp.Delegations = append(resp.Delegations, *d)
+	}
+	sort.Slice(resp.Delegations, func(i, j int) bool {
+		if !resp.Delegations[i].Stake.Equal(resp.Delegations[j].Stake) {
+			return resp.Delegations[i].Stake.GreaterThan(resp.Delegations[j].Stake)
+		}
+		return resp.Delegations[i].Validator < resp.Delegations[j].Validator
+	})
+	resp.Redelegations = make([]smodels.Redelegation, len(redelegations))
+	for i, r := range redelegations {
+		resp.Redelegations[i] = smodels.Redelegation{
+			TxHash:       r.TxHash,
+			ValidatorSrc: r.ValidatorSrc,
+			ValidatorDst: r.ValidatorDst,
+			Amount:       r.Amount,
+			CreatedAt:    dmodels.NewTime(r.CreatedAt),
+		}
+	}
+	return resp, nil
+}
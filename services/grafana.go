@@ -16,17 +16,17 @@ func (s *ServiceFacade) GetSizeOfNode() (size float64, err error) {
 	url := "http://s175.everstake.one:8060/monitoring"
 	resp, err := http.Get(url)
 	if err != nil {
-		return size, fmt.Errorf("http.Get: %s", err.Error())
+		return size, fmt.Errorf("http.Get: %w", err)
 	}
 	defer resp.Body.Close()
 	data, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return size, fmt.Errorf("ioutil.ReadAll: %s", err.Error())
+		return size, fmt.Errorf("ioutil.ReadAll: %w", err)
 	}
 	var nSize nodeSize
 	err = json.Unmarshal(data, &nSize)
 	if err != nil {
-		return size, fmt.Errorf("json.Unmarshal: %s", err.Error())
+		return size, fmt.Errorf("json.Unmarshal: %w", err)
 	}
 	return nSize.Size, nil
 }
@@ -9,7 +9,7 @@ import (
 func B64ToHex(b64Str string) (hexStr string, err error) {
 	bts, err := base64.StdEncoding.DecodeString(b64Str)
 	if err != nil {
-		return hexStr, fmt.Errorf("base64.StdEncoding.DecodeString: %s", err.Error())
+		return hexStr, fmt.Errorf("base64.StdEncoding.DecodeString: %w", err)
 	}
 	return hex.EncodeToString(bts), nil
 }
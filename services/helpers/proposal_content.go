@@ -0,0 +1,102 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/everstake/cosmoscan-api/config"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/everstake/cosmoscan-api/services/node"
+	"github.com/shopspring/decimal"
+	"strings"
+)
+
+const execLegacyContentType = "/cosmos.gov.v1.MsgExecLegacyContent"
+
+// messageProposalTypes maps the gov v1 messages to the legacy proposal types, so proposals can be filtered by type uniformly
+var messageProposalTypes = map[string]string{
+	"MsgSoftwareUpgrade":    dmodels.ProposalTypeSoftwareUpgrade,
+	"MsgCancelUpgrade":      dmodels.ProposalTypeCancelUpgrade,
+	"MsgCommunityPoolSpend": dmodels.ProposalTypeCommunityPoolSpend,
+}
+
+// rawProposalContent covers both the legacy proposal contents and the gov v1 proposal messages,
+// they use the same field names for the upgrade plan, parameter changes and spends
+type rawProposalContent struct {
+	Type    string `json:"@type"`
+	Changes []struct {
+		Subspace string `json:"subspace"`
+		Key      string `json:"key"`
+		Value    string `json:"value"`
+	} `json:"changes"`
+	Plan *struct {
+		Name   string `json:"name"`
+		Height uint64 `json:"height,string"`
+		Info   string `json:"info"`
+	} `json:"plan"`
+	Recipient string `json:"recipient"`
+	Amount    []struct {
+		Denom  string          `json:"denom"`
+		Amount decimal.Decimal `json:"amount"`
+	} `json:"amount"`
+	Content json.RawMessage `json:"content"`
+}
+
+// DecodeProposalContent decodes the content of a legacy proposal or a message of a gov v1 proposal,
+// atom amounts are converted from the base denomination
+func DecodeProposalContent(data []byte) (content dmodels.ProposalContent, err error) {
+	var raw rawProposalContent
+	err = json.Unmarshal(data, &raw)
+	if err != nil {
+		return content, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	if raw.Type == execLegacyContentType && len(raw.Content) != 0 {
+		return DecodeProposalContent(raw.Content)
+	}
+	content.Type = raw.Type
+	if parts := strings.Split(raw.Type, "."); len(parts) > 0 {
+		content.Type = parts[len(parts)-1]
+	}
+	if t, ok := messageProposalTypes[content.Type]; ok {
+		content.Type = t
+	}
+	for _, change := range raw.Changes {
+		content.Changes = append(content.Changes, dmodels.ParamChange{
+			Subspace: change.Subspace,
+			Key:      change.Key,
+			Value:    change.Value,
+		})
+	}
+	if raw.Plan != nil {
+		content.Plan = &dmodels.UpgradePlan{
+			Name:   raw.Plan.Name,
+			Height: raw.Plan.Height,
+			Info:   raw.Plan.Info,
+		}
+	}
+	content.Recipient = raw.Recipient
+	for _, amount := range raw.Amount {
+		coin := dmodels.Coin{Denom: amount.Denom, Amount: amount.Amount}
+		if amount.Denom == node.MainUnit {
+			coin = dmodels.Coin{Denom: config.Currency, Amount: node.Precision(amount.Amount)}
+		}
+		content.Amount = append(content.Amount, coin)
+	}
+	return content, nil
+}
+
+// DecodeProposalContents decodes the messages of a gov v1 proposal
+func DecodeProposalContents(messages []byte) (contents dmodels.ProposalContents, err error) {
+	var items []json.RawMessage
+	err = json.Unmarshal(messages, &items)
+	if err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	for _, item := range items {
+		content, err := DecodeProposalContent(item)
+		if err != nil {
+			return nil, fmt.Errorf("DecodeProposalContent: %w", err)
+		}
+		contents = append(contents, content)
+	}
+	return contents, nil
+}
@@ -11,7 +11,7 @@ import (
 func GetHexAddressFromBase64PK(key string) (address string, err error) {
 	decodedKey, err := base64.StdEncoding.DecodeString(key)
 	if err != nil {
-		return address, fmt.Errorf("base64.DecodeString: %s", err.Error())
+		return address, fmt.Errorf("base64.DecodeString: %w", err)
 	}
 	if len(decodedKey) != 32 {
 		return address, fmt.Errorf("wrong key format")
@@ -23,7 +23,7 @@ func GetHexAddressFromBase64PK(key string) (address string, err error) {
 func GetBech32FromBase64PK(pkB64 string, pkType string) (address string, err error) {
 	decodedKey, err := base64.StdEncoding.DecodeString(pkB64)
 	if err != nil {
-		return address, fmt.Errorf("base64.DecodeString: %s", err.Error())
+		return address, fmt.Errorf("base64.DecodeString: %w", err)
 	}
 	var hexAddress string
 	switch pkType {
@@ -35,7 +35,7 @@ func GetBech32FromBase64PK(pkB64 string, pkType string) (address string, err err
 	}
 	addr, err := types.AccAddressFromHex(hexAddress)
 	if err != nil {
-		return address, fmt.Errorf("types.AccAddressFromHex: %s", err.Error())
+		return address, fmt.Errorf("types.AccAddressFromHex: %w", err)
 	}
 	return addr.String(), nil
 }
@@ -2,6 +2,7 @@ package services
 
 import (
 	"fmt"
+	"github.com/everstake/cosmoscan-api/config"
 	"github.com/everstake/cosmoscan-api/dao/filters"
 	"github.com/everstake/cosmoscan-api/dmodels"
 	"github.com/everstake/cosmoscan-api/log"
@@ -53,28 +54,28 @@ func (s ServiceFacade) Test() (state dmodels.HistoricalState, err error) {
 func (s ServiceFacade) makeState() (state dmodels.HistoricalState, err error) {
 	state.InflationRate, err = s.node.GetInflation()
 	if err != nil {
-		return state, fmt.Errorf("node.GetInflation: %s", err.Error())
+		return state, fmt.Errorf("node.GetInflation: %w", err)
 	}
 	state.InflationRate = state.InflationRate.Truncate(2)
 	state.CommunityPool, err = s.node.GetCommunityPoolAmount()
 	if err != nil {
-		return state, fmt.Errorf("node.GetCommunityPoolAmount: %s", err.Error())
+		return state, fmt.Errorf("node.GetCommunityPoolAmount: %w", err)
 	}
 	state.CommunityPool = state.CommunityPool.Truncate(2)
 	totalSupply, err := s.node.GetTotalSupply()
 	if err != nil {
-		return state, fmt.Errorf("node.GetTotalSupply: %s", err.Error())
+		return state, fmt.Errorf("node.GetTotalSupply: %w", err)
 	}
 	stakingPool, err := s.node.GetStakingPool()
 	if err != nil {
-		return state, fmt.Errorf("node.GetStakingPool: %s", err.Error())
+		return state, fmt.Errorf("node.GetStakingPool: %w", err)
 	}
 	if !totalSupply.IsZero() {
 		state.StakedRatio = stakingPool.Pool.BondedTokens.Div(totalSupply).Mul(decimal.New(100, 0)).Truncate(2)
 	}
 	validators, err := s.node.GetValidators()
 	if err != nil {
-		return state, fmt.Errorf("node.GetValidators: %s", err.Error())
+		return state, fmt.Errorf("node.GetValidators: %w", err)
 	}
 	sort.Slice(validators, func(i, j int) bool {
 		return validators[i].DelegatorShares.GreaterThan(validators[j].DelegatorShares)
@@ -90,12 +91,17 @@ func (s ServiceFacade) makeState() (state dmodels.HistoricalState, err error) {
 		}
 	}
 
-	state.CirculatingSupply = totalSupply.Truncate(2)
+	supply, err := s.makeSupply()
+	if err != nil {
+		return state, fmt.Errorf("makeSupply: %w", err)
+	}
+	state.CirculatingSupply = supply.Circulating.Truncate(2)
 
-	state.Price, state.TradingVolume, err = s.cm.GetMarketData()
+	prices, err := s.cm.GetMarketData([]string{config.QuoteCurrency})
 	if err != nil {
-		return state, fmt.Errorf("cm.GetMarketData: %s", err.Error())
+		return state, fmt.Errorf("cm.GetMarketData: %w", err)
 	}
+	state.Price, state.TradingVolume = prices[0].Price, prices[0].Volume
 	state.MarketCap = state.CirculatingSupply.Mul(state.Price).Truncate(2)
 
 	if state.Price.IsZero() {
@@ -105,35 +111,68 @@ func (s ServiceFacade) makeState() (state dmodels.HistoricalState, err error) {
 	return state, nil
 }
 
-func (s *ServiceFacade) GetHistoricalState() (state smodels.HistoricalState, err error) {
+// GetHistoricalState returns the latest state along with the price and the market cap of the last day in the currency
+func (s *ServiceFacade) GetHistoricalState(currency string) (state smodels.HistoricalState, err error) {
+	currency, err = s.quoteCurrency(currency)
+	if err != nil {
+		return state, err
+	}
 	models, err := s.dao.GetHistoricalStates(filters.HistoricalState{Limit: 1})
 	if err != nil {
-		return state, fmt.Errorf("dao.GetHistoricalStates: %s", err.Error())
+		return state, fmt.Errorf("dao.GetHistoricalStates: %w", err)
 	}
 	if len(models) == 0 {
 		return state, fmt.Errorf("not found any states")
 	}
 	state.Current = models[0]
-	state.PriceAgg, err = s.dao.GetAggHistoricalStatesByField(filters.Agg{
+	lastDay := filters.Agg{
 		By:   filters.AggByHour,
 		From: dmodels.NewTime(time.Now().Add(-time.Hour * 24)),
-	}, "his_price")
-	if err != nil {
-		return state, fmt.Errorf("dao.GetAggHistoricalStatesByField: %s", err.Error())
 	}
-	state.MarketCapAgg, err = s.dao.GetAggHistoricalStatesByField(filters.Agg{
-		By:   filters.AggByHour,
-		From: dmodels.NewTime(time.Now().Add(-time.Hour * 24)),
-	}, "his_market_cap")
-	if err != nil {
-		return state, fmt.Errorf("dao.GetAggHistoricalStatesByField: %s", err.Error())
+	if currency == config.QuoteCurrency {
+		state.PriceAgg, err = s.dao.GetAggHistoricalStatesByField(lastDay, "his_price")
+		if err != nil {
+			return state, fmt.Errorf("dao.GetAggHistoricalStatesByField: %w", err)
+		}
+		state.MarketCapAgg, err = s.dao.GetAggHistoricalStatesByField(lastDay, "his_market_cap")
+		if err != nil {
+			return state, fmt.Errorf("dao.GetAggHistoricalStatesByField: %w", err)
+		}
+	} else {
+		price, err := s.getLastPrice(currency)
+		if err != nil {
+			return state, fmt.Errorf("getLastPrice: %w", err)
+		}
+		state.Current.Price = price.Price
+		state.Current.TradingVolume = price.Volume
+		state.Current.MarketCap = state.Current.CirculatingSupply.Mul(price.Price).Truncate(2)
+		state.PriceAgg, err = s.dao.GetAggPrices(filters.PricesAgg{Agg: lastDay, Currency: currency})
+		if err != nil {
+			return state, fmt.Errorf("dao.GetAggPrices: %w", err)
+		}
+		supply, err := s.dao.GetAggHistoricalStatesByField(lastDay, "his_circulating_supply")
+		if err != nil {
+			return state, fmt.Errorf("dao.GetAggHistoricalStatesByField: %w", err)
+		}
+		prices := make(map[int64]decimal.Decimal)
+		for _, item := range state.PriceAgg {
+			prices[item.Time.Unix()] = item.Value
+		}
+		for _, item := range supply {
+			if price, ok := prices[item.Time.Unix()]; ok {
+				state.MarketCapAgg = append(state.MarketCapAgg, smodels.AggItem{
+					Time:  item.Time,
+					Value: item.Value.Mul(price).Truncate(2),
+				})
+			}
+		}
 	}
 	state.StakedRatioAgg, err = s.dao.GetAggHistoricalStatesByField(filters.Agg{
 		By:   filters.AggByDay,
 		From: dmodels.NewTime(time.Now().Add(-time.Hour * 24 * 30)),
 	}, "his_staked_ratio")
 	if err != nil {
-		return state, fmt.Errorf("dao.GetAggHistoricalStatesByField: %s", err.Error())
+		return state, fmt.Errorf("dao.GetAggHistoricalStatesByField: %w", err)
 	}
 	return state, nil
 }
@@ -0,0 +1,74 @@
+package limiter
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// idleTTL is how long an untouched bucket is kept before it is dropped; by then it is full anyway
+const idleTTL = time.Minute * 10
+
+type (
+	// Limiter keeps a token bucket per key (api key, client ip, etc.)
+	Limiter struct {
+		mu          *sync.Mutex
+		buckets     map[string]*bucket
+		lastCleanup time.Time
+		now         func() time.Time
+	}
+	bucket struct {
+		tokens float64
+		last   time.Time
+	}
+)
+
+func New() *Limiter {
+	return &Limiter{
+		mu:          &sync.Mutex{},
+		buckets:     make(map[string]*bucket),
+		lastCleanup: time.Now(),
+		now:         time.Now,
+	}
+}
+
+// Take withdraws cost tokens from the bucket of the key, the bucket is refilled by rate tokens per second
+// up to burst tokens. When there are not enough tokens it returns false and the time left until the request
+// can be satisfied.
+func (l *Limiter) Take(key string, rate float64, burst uint64, cost uint64) (ok bool, retryAfter time.Duration) {
+	if rate <= 0 || burst == 0 {
+		return true, 0
+	}
+	if cost > burst {
+		cost = burst
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.cleanup(now)
+	b, found := l.buckets[key]
+	if !found {
+		b = &bucket{tokens: float64(burst), last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(burst), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+	if b.tokens >= float64(cost) {
+		b.tokens -= float64(cost)
+		return true, 0
+	}
+	missing := float64(cost) - b.tokens
+	return false, time.Duration(missing / rate * float64(time.Second))
+}
+
+func (l *Limiter) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < idleTTL {
+		return
+	}
+	for key, b := range l.buckets {
+		if now.Sub(b.last) > idleTTL {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastCleanup = now
+}
@@ -0,0 +1,40 @@
+package limiter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiterTake(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := New()
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		if ok, _ := l.Take("ip:1", 1, 5, 1); !ok {
+			t.Fatalf("request %d: expected to pass within burst", i)
+		}
+	}
+	ok, retryAfter := l.Take("ip:1", 1, 5, 1)
+	if ok {
+		t.Fatal("expected to be limited after burst")
+	}
+	if retryAfter != time.Second {
+		t.Errorf("retryAfter: expected %s, got %s", time.Second, retryAfter)
+	}
+	if ok, _ := l.Take("ip:2", 1, 5, 1); !ok {
+		t.Error("buckets of different keys must be independent")
+	}
+
+	now = now.Add(time.Second * 2)
+	if ok, _ := l.Take("ip:1", 1, 5, 2); !ok {
+		t.Error("expected bucket to be refilled")
+	}
+	ok, retryAfter = l.Take("ip:1", 1, 5, 3)
+	if ok {
+		t.Fatal("expected to be limited by cost")
+	}
+	if retryAfter != time.Second*3 {
+		t.Errorf("retryAfter: expected %s, got %s", time.Second*3, retryAfter)
+	}
+}
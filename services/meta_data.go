@@ -2,6 +2,7 @@ package services
 
 import (
 	"fmt"
+	"github.com/everstake/cosmoscan-api/config"
 	"github.com/everstake/cosmoscan-api/dao/filters"
 	"github.com/everstake/cosmoscan-api/services/helpers"
 	"github.com/everstake/cosmoscan-api/services/node"
@@ -9,18 +10,31 @@ import (
 	"github.com/shopspring/decimal"
 )
 
-func (s *ServiceFacade) GetMetaData() (meta smodels.MetaData, err error) {
-	states, err := s.dao.GetHistoricalStates(filters.HistoricalState{Limit: 1})
+func (s *ServiceFacade) GetMetaData(currency string) (meta smodels.MetaData, err error) {
+	currency, err = s.quoteCurrency(currency)
 	if err != nil {
-		return meta, fmt.Errorf("dao.GetHistoricalStates: %s", err.Error())
+		return meta, err
 	}
-	if len(states) != 0 {
-		state := states[0]
-		meta.CurrentPrice = state.Price
+	if currency == config.QuoteCurrency {
+		states, err := s.dao.GetHistoricalStates(filters.HistoricalState{Limit: 1})
+		if err != nil {
+			return meta, fmt.Errorf("dao.GetHistoricalStates: %w", err)
+		}
+		if len(states) != 0 {
+			meta.CurrentPrice = states[0].Price
+		}
+	} else {
+		prices, err := s.dao.GetPrices(filters.Prices{Currency: currency, Limit: 1})
+		if err != nil {
+			return meta, fmt.Errorf("dao.GetPrices: %w", err)
+		}
+		if len(prices) != 0 {
+			meta.CurrentPrice = prices[0].Price
+		}
 	}
 	blocks, err := s.dao.GetBlocks(filters.Blocks{Limit: 2})
 	if err != nil {
-		return meta, fmt.Errorf("dao.GetBlocks: %s", err.Error())
+		return meta, fmt.Errorf("dao.GetBlocks: %w", err)
 	}
 	if len(blocks) == 2 {
 		meta.BlockTime = blocks[0].CreatedAt.Sub(blocks[1].CreatedAt).Seconds()
@@ -44,7 +58,7 @@ func (s *ServiceFacade) GetMetaData() (meta smodels.MetaData, err error) {
 		for _, validator := range validators {
 			consAddress, err := helpers.GetHexAddressFromBase64PK(validator.ConsensusPubkey.Key)
 			if err != nil {
-				return meta, fmt.Errorf("helpers.GetHexAddressFromBase64PK(%s): %s", validator.ConsensusPubkey.Key, err.Error())
+				return meta, fmt.Errorf("helpers.GetHexAddressFromBase64PK(%s): %w", validator.ConsensusPubkey.Key, err)
 			}
 			if consAddress == proposer {
 				meta.LatestValidator = validator.Description.Moniker
@@ -54,7 +68,7 @@ func (s *ServiceFacade) GetMetaData() (meta smodels.MetaData, err error) {
 	}
 	proposals, err := s.dao.GetProposals(filters.Proposals{Limit: 1})
 	if err != nil {
-		return meta, fmt.Errorf("dao.GetProposals: %s", err.Error())
+		return meta, fmt.Errorf("dao.GetProposals: %w", err)
 	}
 	if len(proposals) != 0 {
 		meta.LatestProposal = smodels.MetaDataProposal{
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"fmt"
+	"github.com/everstake/cosmoscan-api/config"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/everstake/cosmoscan-api/smodels"
+	"github.com/shopspring/decimal"
+	"sort"
+)
+
+// GetMetrics returns the metrics of the registry sorted by name
+func (s *ServiceFacade) GetMetrics() (metrics []smodels.MetricInfo) {
+	for name, metric := range dmodels.Metrics {
+		metrics = append(metrics, smodels.MetricInfo{
+			Name: name,
+			Aggs: metric.Aggs,
+			Unit: metricUnit(metric),
+		})
+	}
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].Name < metrics[j].Name
+	})
+	return metrics
+}
+
+// GetMetric returns the time series of the metric, the default aggregation is the first one of the metric,
+// with Compare the series of the previous period of the same length is returned too
+func (s *ServiceFacade) GetMetric(filter filters.Metric) (series smodels.MetricSeries, err error) {
+	metric, ok := dmodels.Metrics[filter.Name]
+	if !ok {
+		return series, derrors.NotFound("metric %s not found", filter.Name)
+	}
+	if filter.Agg == "" {
+		filter.Agg = metric.Aggs[0]
+	}
+	if !metricHasAgg(metric, filter.Agg) {
+		return series, derrors.InvalidArgument("unsupported agg %s of metric %s", filter.Agg, filter.Name)
+	}
+	series = smodels.MetricSeries{
+		Metric: filter.Name,
+		Agg:    filter.Agg,
+		Bucket: filter.Bucket,
+		TZ:     filter.TZ,
+		Unit:   metricUnit(metric),
+		From:   filter.From,
+		To:     filter.To,
+	}
+	series.Items, series.Total, err = s.getMetricPeriod(metric, filter)
+	if err != nil {
+		return series, err
+	}
+	if !filter.Compare {
+		return series, nil
+	}
+	previous := filter
+	previous.From = dmodels.NewTime(filter.From.Add(-filter.To.Sub(filter.From.Time)))
+	previous.To = filter.From
+	comparison := smodels.MetricComparison{
+		From: previous.From,
+		To:   previous.To,
+	}
+	comparison.Items, comparison.Total, err = s.getMetricPeriod(metric, previous)
+	if err != nil {
+		return series, err
+	}
+	if !comparison.Total.IsZero() {
+		comparison.Change = series.Total.Sub(comparison.Total).Div(comparison.Total).Mul(decimal.New(100, 0)).Round(2)
+	}
+	series.Previous = &comparison
+	return series, nil
+}
+
+func (s *ServiceFacade) getMetricPeriod(metric dmodels.Metric, filter filters.Metric) (items []smodels.AggItem, total decimal.Decimal, err error) {
+	items, err = s.dao.GetMetric(metric, filter)
+	if err != nil {
+		return nil, total, fmt.Errorf("dao.GetMetric: %w", err)
+	}
+	// the legacy agg routes serve the items only
+	if filter.Legacy {
+		return items, total, nil
+	}
+	total, err = s.dao.GetMetricTotal(metric, filter)
+	if err != nil {
+		return nil, total, fmt.Errorf("dao.GetMetricTotal: %w", err)
+	}
+	return items, total, nil
+}
+
+func metricHasAgg(metric dmodels.Metric, agg string) bool {
+	for _, a := range metric.Aggs {
+		if a == agg {
+			return true
+		}
+	}
+	return false
+}
+
+func metricUnit(metric dmodels.Metric) string {
+	if metric.Atom {
+		return config.Currency
+	}
+	return ""
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"github.com/everstake/cosmoscan-api/dao/derrors"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"testing"
+)
+
+func TestGetMetricErrors(t *testing.T) {
+	s := &ServiceFacade{}
+	tests := []struct {
+		filter filters.Metric
+		code   string
+	}{
+		{filter: filters.Metric{Name: "unknown"}, code: derrors.CodeNotFound},
+		{filter: filters.Metric{Name: dmodels.MetricBlocksCount, Agg: dmodels.MetricAggP99}, code: derrors.CodeInvalidArgument},
+		{filter: filters.Metric{Name: dmodels.MetricBlocksDelay, Agg: "median"}, code: derrors.CodeInvalidArgument},
+	}
+	for _, test := range tests {
+		_, err := s.GetMetric(test.filter)
+		if derrors.Code(err) != test.code {
+			t.Errorf("%s %s: expected %s, got %v", test.filter.Name, test.filter.Agg, test.code, err)
+		}
+	}
+}
+
+func TestGetMetrics(t *testing.T) {
+	s := &ServiceFacade{}
+	metrics := s.GetMetrics()
+	if len(metrics) != len(dmodels.Metrics) {
+		t.Fatalf("expected %d metrics, got %d", len(dmodels.Metrics), len(metrics))
+	}
+	for i, metric := range metrics {
+		if len(metric.Aggs) == 0 {
+			t.Errorf("%s: expected aggs", metric.Name)
+		}
+		if i > 0 && metrics[i-1].Name >= metric.Name {
+			t.Errorf("expected metrics sorted by name")
+		}
+	}
+}
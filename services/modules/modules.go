@@ -17,7 +17,8 @@ type Module interface {
 }
 
 type Group struct {
-	modules []Module
+	modules     []Module
+	stopTimeout time.Duration
 }
 
 type errResp struct {
@@ -27,10 +28,17 @@ type errResp struct {
 
 func NewGroup(module ...Module) *Group {
 	return &Group{
-		modules: module,
+		modules:     module,
+		stopTimeout: gracefulTimeout,
 	}
 }
 
+// WithStopTimeout sets how long each module is given to stop
+func (g *Group) WithStopTimeout(timeout time.Duration) *Group {
+	g.stopTimeout = timeout
+	return g
+}
+
 func (g *Group) Run() {
 	errors := make(chan errResp, len(g.modules))
 	for _, m := range g.modules {
@@ -62,7 +70,7 @@ func (g *Group) Stop() {
 	wg.Add(len(g.modules))
 	for _, m := range g.modules {
 		go func(m Module) {
-			err := stopModule(m)
+			err := stopModule(m, g.stopTimeout)
 			if err != nil {
 				log.Error("Module [%s] stopped with error: %s", m.Title(), err.Error())
 			}
@@ -73,18 +81,18 @@ func (g *Group) Stop() {
 	log.Info("All modules was stopped")
 }
 
-func stopModule(m Module) error {
+func stopModule(m Module, timeout time.Duration) error {
 	if m == nil {
 		return nil
 	}
-	result := make(chan error)
+	result := make(chan error, 1)
 	go func() {
 		result <- m.Stop()
 	}()
 	select {
 	case err := <-result:
 		return err
-	case <-time.After(gracefulTimeout):
+	case <-time.After(timeout):
 		return fmt.Errorf("stoped by timeout")
 	}
 }
@@ -2,11 +2,14 @@ package node
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/everstake/cosmoscan-api/config"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/shopspring/decimal"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -19,12 +22,25 @@ const (
 	RejectedProposalStatus      = "PROPOSAL_STATUS_REJECTED"
 	FailedProposalStatus        = "PROPOSAL_STATUS_FAILED"
 
+	execLegacyContentMsgType = "/cosmos.gov.v1.MsgExecLegacyContent"
+
+	BondedValidatorStatus = "BOND_STATUS_BONDED"
+
 	MainUnit = "uatom"
+
+	requestTimeout = time.Minute
+
+	grpcCodeInvalidArgument = 3
+	grpcCodeNotFound        = 5
 )
 
 var PrecisionDiv = decimal.New(1, precision)
 
 type (
+	grpcError struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
 	API struct {
 		cfg    config.Config
 		client *http.Client
@@ -44,6 +60,8 @@ type (
 			Type string `json:"@type"`
 			Key  string `json:"key"`
 		} `json:"consensus_pubkey"`
+		Jailed          bool            `json:"jailed"`
+		Status          string          `json:"status"`
 		Tokens          uint64          `json:"tokens,string"`
 		DelegatorShares decimal.Decimal `json:"delegator_shares"`
 		Description     struct {
@@ -98,6 +116,7 @@ type (
 				ValidatorAddress string          `json:"validator_address"`
 				Shares           decimal.Decimal `json:"shares"`
 			} `json:"delegation"`
+			Balance Amount `json:"balance"`
 		} `json:"delegation_responses"`
 	}
 	UnbondingResult struct {
@@ -105,33 +124,52 @@ type (
 			DelegatorAddress string `json:"delegator_address"`
 			ValidatorAddress string `json:"validator_address"`
 			Entries          []struct {
-				Balance decimal.Decimal `json:"balance"`
+				CreationHeight uint64          `json:"creation_height,string"`
+				CompletionTime time.Time       `json:"completion_time"`
+				InitialBalance decimal.Decimal `json:"initial_balance"`
+				Balance        decimal.Decimal `json:"balance"`
 			} `json:"entries"`
 		} `json:"unbonding_responses"`
 	}
 	ProposalsResult struct {
-		Proposals []struct {
-			Content struct {
-				Type        string `json:"@type"`
-				Title       string `json:"title"`
-				Description string `json:"description"`
-			} `json:"content"`
-			ProposalID       uint64 `json:"proposal_id,string"`
-			Status           string `json:"status"`
-			FinalTallyResult struct {
-				Yes        int64 `json:"yes,string"`
-				Abstain    int64 `json:"abstain,string"`
-				No         int64 `json:"no,string"`
-				NoWithVeto int64 `json:"no_with_veto,string"`
-			} `json:"final_tally_result"`
-			SubmitTime     time.Time `json:"submit_time"`
-			DepositEndTime time.Time `json:"deposit_end_time"`
-			TotalDeposit   []struct {
-				Amount decimal.Decimal `json:"amount"`
-			} `json:"total_deposit"`
-			VotingStartTime time.Time `json:"voting_start_time"`
-			VotingEndTime   time.Time `json:"voting_end_time"`
-		} `json:"proposals"`
+		Proposals []Proposal `json:"proposals"`
+	}
+	// Proposal is a gov v1 proposal, Content is taken from its legacy content message if there is one
+	Proposal struct {
+		ProposalID       uint64            `json:"id,string"`
+		Messages         []json.RawMessage `json:"messages"`
+		Content          ProposalContent   `json:"-"`
+		Status           string            `json:"status"`
+		FinalTallyResult TallyResult       `json:"final_tally_result"`
+		SubmitTime       time.Time         `json:"submit_time"`
+		DepositEndTime   time.Time         `json:"deposit_end_time"`
+		TotalDeposit     []struct {
+			Amount decimal.Decimal `json:"amount"`
+		} `json:"total_deposit"`
+		VotingStartTime time.Time `json:"voting_start_time"`
+		VotingEndTime   time.Time `json:"voting_end_time"`
+		Title           string    `json:"title"`
+		Summary         string    `json:"summary"`
+	}
+	TallyResult struct {
+		Yes        int64 `json:"yes_count,string"`
+		Abstain    int64 `json:"abstain_count,string"`
+		No         int64 `json:"no_count,string"`
+		NoWithVeto int64 `json:"no_with_veto_count,string"`
+	}
+	ProposalContent struct {
+		Type        string `json:"@type"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		// Raw keeps the whole content for decoding of the type specific fields
+		Raw json.RawMessage `json:"-"`
+	}
+	ParamResult struct {
+		Param struct {
+			Subspace string `json:"subspace"`
+			Key      string `json:"key"`
+			Value    string `json:"value"`
+		} `json:"param"`
 	}
 	ProposalProposer struct {
 		Proposal struct {
@@ -160,12 +198,32 @@ type (
 		} `json:"result"`
 	}
 	ProposalTallyResult struct {
-		Tally struct {
-			Yes        int64 `json:"yes,string"`
-			Abstain    int64 `json:"abstain,string"`
-			No         int64 `json:"no,string"`
-			NoWithVeto int64 `json:"no_with_veto,string"`
-		} `json:"tally"`
+		Tally TallyResult `json:"tally"`
+	}
+	TallyParamsResult struct {
+		TallyParams TallyParams `json:"tally_params"`
+	}
+	TallyParams struct {
+		Quorum        decimal.Decimal `json:"quorum"`
+		Threshold     decimal.Decimal `json:"threshold"`
+		VetoThreshold decimal.Decimal `json:"veto_threshold"`
+	}
+	DistributionParamsResult struct {
+		Params DistributionParams `json:"params"`
+	}
+	DistributionParams struct {
+		CommunityTax        decimal.Decimal `json:"community_tax"`
+		WithdrawAddrEnabled bool            `json:"withdraw_addr_enabled"`
+	}
+	SlashingParamsResult struct {
+		Params SlashingParams `json:"params"`
+	}
+	SlashingParams struct {
+		SignedBlocksWindow      uint64          `json:"signed_blocks_window,string"`
+		MinSignedPerWindow      decimal.Decimal `json:"min_signed_per_window"`
+		DowntimeJailDuration    string          `json:"downtime_jail_duration"`
+		SlashFractionDoubleSign decimal.Decimal `json:"slash_fraction_double_sign"`
+		SlashFractionDowntime   decimal.Decimal `json:"slash_fraction_downtime"`
 	}
 	Block struct {
 		BlockID struct {
@@ -291,7 +349,7 @@ type (
 func NewAPI(cfg config.Config) *API {
 	return &API{
 		cfg:    cfg,
-		client: &http.Client{},
+		client: &http.Client{Timeout: requestTimeout},
 	}
 }
 
@@ -299,28 +357,72 @@ func (api API) request(endpoint string, data interface{}) error {
 	url := fmt.Sprintf("%s/%s", api.cfg.Parser.Node, endpoint)
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
-		return fmt.Errorf("http.NewRequest: %s", err.Error())
+		return fmt.Errorf("http.NewRequest: %w", err)
 	}
 	resp, err := api.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("client.Do: %s", err.Error())
+		err = derrors.FromConnection(err)
+		if derrors.Code(err) == "" {
+			err = derrors.Wrap(derrors.CodeUpstreamUnavailable, err, "")
+		}
+		return fmt.Errorf("client.Do: %w", err)
 	}
+	defer resp.Body.Close()
 	d, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("ioutil.ReadAll: %s", err.Error())
+		return fmt.Errorf("ioutil.ReadAll: %w", derrors.FromConnection(err))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return statusError(resp.StatusCode, d)
 	}
 	err = json.Unmarshal(d, data)
 	if err != nil {
-		return fmt.Errorf("json.Unmarshal: %s", err.Error())
+		return fmt.Errorf("json.Unmarshal: %w", err)
 	}
 	return nil
 }
 
+// StatusError is a failed response of the node, its message is logged but never shown to the clients
+type StatusError struct {
+	Status  int
+	Code    int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("node responded with status %d: %s", e.Status, e.Message)
+}
+
+// statusError turns a failed response of the node into an upstream error, whether the requested object
+// is missing is up to the caller (see IsNotFound) as the same response means a failure for other calls
+func statusError(status int, body []byte) error {
+	var e grpcError
+	_ = json.Unmarshal(body, &e)
+	err := &StatusError{Status: status, Code: e.Code, Message: e.Message}
+	if status == http.StatusGatewayTimeout {
+		return derrors.Wrap(derrors.CodeTimeout, err, "node request timed out")
+	}
+	return derrors.Wrap(derrors.CodeUpstreamUnavailable, err, "node request failed")
+}
+
+// IsNotFound reports whether the node responded that the requested object does not exist,
+// the gRPC code of the gateway response is more precise than the http status
+func IsNotFound(err error) bool {
+	var e *StatusError
+	return errors.As(err, &e) && (e.Code == grpcCodeNotFound || e.Status == http.StatusNotFound)
+}
+
+// IsInvalidArgument reports whether the node rejected the arguments of the request
+func IsInvalidArgument(err error) bool {
+	var e *StatusError
+	return errors.As(err, &e) && (e.Code == grpcCodeInvalidArgument || e.Status == http.StatusBadRequest)
+}
+
 func (api API) GetCommunityPoolAmount() (amount decimal.Decimal, err error) {
 	var cp CommunityPool
 	err = api.request("cosmos/distribution/v1beta1/community_pool", &cp)
 	if err != nil {
-		return amount, fmt.Errorf("request: %s", err.Error())
+		return amount, fmt.Errorf("request: %w", err)
 	}
 	for _, p := range cp.Pool {
 		if p.Denom == MainUnit {
@@ -334,7 +436,7 @@ func (api API) GetValidators() (items []Validator, err error) {
 	var validators Validators
 	err = api.request("cosmos/staking/v1beta1/validators?pagination.limit=10000", &validators)
 	if err != nil {
-		return nil, fmt.Errorf("request: %s", err.Error())
+		return nil, fmt.Errorf("request: %w", err)
 	}
 	return validators.Validators, nil
 }
@@ -343,7 +445,7 @@ func (api API) GetInflation() (amount decimal.Decimal, err error) {
 	var inflation Inflation
 	err = api.request("cosmos/mint/v1beta1/inflation", &inflation)
 	if err != nil {
-		return amount, fmt.Errorf("request: %s", err.Error())
+		return amount, fmt.Errorf("request: %w", err)
 	}
 	return inflation.Inflation.Mul(decimal.New(100, 0)), nil
 }
@@ -352,7 +454,7 @@ func (api API) GetTotalSupply() (amount decimal.Decimal, err error) {
 	var s Supply
 	err = api.request(fmt.Sprintf("cosmos/bank/v1beta1/supply/%s", MainUnit), &s)
 	if err != nil {
-		return amount, fmt.Errorf("request: %s", err.Error())
+		return amount, fmt.Errorf("request: %w", err)
 	}
 	return s.Amount.Amount.Div(PrecisionDiv), nil
 }
@@ -360,7 +462,7 @@ func (api API) GetTotalSupply() (amount decimal.Decimal, err error) {
 func (api API) GetStakingPool() (sp StakingPool, err error) {
 	err = api.request("cosmos/staking/v1beta1/pool", &sp)
 	if err != nil {
-		return sp, fmt.Errorf("request: %s", err.Error())
+		return sp, fmt.Errorf("request: %w", err)
 	}
 	sp.Pool.BondedTokens = sp.Pool.BondedTokens.Div(PrecisionDiv)
 	sp.Pool.NotBondedTokens = sp.Pool.NotBondedTokens.Div(PrecisionDiv)
@@ -371,7 +473,7 @@ func (api API) GetBalance(address string) (amount decimal.Decimal, err error) {
 	var result AmountResult
 	err = api.request(fmt.Sprintf("cosmos/bank/v1beta1/balances/%s", address), &result)
 	if err != nil {
-		return amount, fmt.Errorf("request: %s", err.Error())
+		return amount, fmt.Errorf("request: %w", err)
 	}
 	for _, b := range result.Balances {
 		if b.Denom == MainUnit {
@@ -384,7 +486,7 @@ func (api API) GetBalance(address string) (amount decimal.Decimal, err error) {
 func (api API) GetBalances(address string) (result AmountResult, err error) {
 	err = api.request(fmt.Sprintf("cosmos/bank/v1beta1/balances/%s", address), &result)
 	if err != nil {
-		return result, fmt.Errorf("request: %s", err.Error())
+		return result, fmt.Errorf("request: %w", err)
 	}
 	return result, nil
 }
@@ -393,7 +495,7 @@ func (api API) GetStakeRewards(address string) (amount decimal.Decimal, err erro
 	var result DelegatorRewards
 	err = api.request(fmt.Sprintf("cosmos/distribution/v1beta1/delegators/%s/rewards", address), &result)
 	if err != nil {
-		return amount, fmt.Errorf("request: %s", err.Error())
+		return amount, fmt.Errorf("request: %w", err)
 	}
 	for _, b := range result.Total {
 		if b.Denom == MainUnit {
@@ -407,7 +509,7 @@ func (api API) GetStake(address string) (amount decimal.Decimal, err error) {
 	var result StakeResult
 	err = api.request(fmt.Sprintf("cosmos/staking/v1beta1/delegations/%s?pagination.limit=10000", address), &result)
 	if err != nil {
-		return amount, fmt.Errorf("request: %s", err.Error())
+		return amount, fmt.Errorf("request: %w", err)
 	}
 	shares := decimal.Zero
 	for _, r := range result.DelegationResponses {
@@ -416,11 +518,35 @@ func (api API) GetStake(address string) (amount decimal.Decimal, err error) {
 	return shares.Div(PrecisionDiv), nil
 }
 
+func (api API) GetDelegations(address string) (result StakeResult, err error) {
+	err = api.request(fmt.Sprintf("cosmos/staking/v1beta1/delegations/%s?pagination.limit=10000", address), &result)
+	if err != nil {
+		return result, fmt.Errorf("request: %w", err)
+	}
+	return result, nil
+}
+
+func (api API) GetDelegatorRewards(address string) (result DelegatorRewards, err error) {
+	err = api.request(fmt.Sprintf("cosmos/distribution/v1beta1/delegators/%s/rewards", address), &result)
+	if err != nil {
+		return result, fmt.Errorf("request: %w", err)
+	}
+	return result, nil
+}
+
+func (api API) GetUnbondingDelegations(address string) (result UnbondingResult, err error) {
+	err = api.request(fmt.Sprintf("cosmos/staking/v1beta1/delegators/%s/unbonding_delegations?pagination.limit=10000", address), &result)
+	if err != nil {
+		return result, fmt.Errorf("request: %w", err)
+	}
+	return result, nil
+}
+
 func (api API) GetUnbonding(address string) (amount decimal.Decimal, err error) {
 	var result UnbondingResult
 	err = api.request(fmt.Sprintf("cosmos/staking/v1beta1/delegators/%s/unbonding_delegations?pagination.limit=10000", address), &result)
 	if err != nil {
-		return amount, fmt.Errorf("request: %s", err.Error())
+		return amount, fmt.Errorf("request: %w", err)
 	}
 	for _, r := range result.UnbondingResponses {
 		for _, entry := range r.Entries {
@@ -432,34 +558,117 @@ func (api API) GetUnbonding(address string) (amount decimal.Decimal, err error)
 }
 
 func (api API) GetProposals() (proposals ProposalsResult, err error) {
-	err = api.request("cosmos/gov/v1beta1/proposals?pagination.limit=10000", &proposals)
+	err = api.request("cosmos/gov/v1/proposals?pagination.limit=10000", &proposals)
 	if err != nil {
-		return proposals, fmt.Errorf("request: %s", err.Error())
+		return proposals, fmt.Errorf("request: %w", err)
+	}
+	for i := range proposals.Proposals {
+		proposals.Proposals[i].Content, err = v1ProposalContent(proposals.Proposals[i])
+		if err != nil {
+			return proposals, fmt.Errorf("v1ProposalContent: %w", err)
+		}
 	}
 	return proposals, nil
 }
 
+// v1ProposalContent unwraps the content of a MsgExecLegacyContent message,
+// proposals with other messages have no content besides the title and summary
+func v1ProposalContent(p Proposal) (content ProposalContent, err error) {
+	for _, data := range p.Messages {
+		var msg struct {
+			Type    string          `json:"@type"`
+			Content json.RawMessage `json:"content"`
+		}
+		err = json.Unmarshal(data, &msg)
+		if err != nil {
+			return content, fmt.Errorf("json.Unmarshal: %w", err)
+		}
+		if msg.Type != execLegacyContentMsgType || len(msg.Content) == 0 {
+			continue
+		}
+		err = json.Unmarshal(msg.Content, &content)
+		if err != nil {
+			return content, fmt.Errorf("json.Unmarshal: %w", err)
+		}
+		return content, nil
+	}
+	return ProposalContent{Title: p.Title, Description: p.Summary}, nil
+}
+
+func (c *ProposalContent) UnmarshalJSON(data []byte) error {
+	type content ProposalContent
+	var v content
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return err
+	}
+	*c = ProposalContent(v)
+	c.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// GetParam returns the current value of the parameter of the x/params subspace
+func (api API) GetParam(subspace string, key string) (value string, err error) {
+	var result ParamResult
+	err = api.request(fmt.Sprintf("cosmos/params/v1beta1/params?subspace=%s&key=%s", url.QueryEscape(subspace), url.QueryEscape(key)), &result)
+	if err != nil {
+		return value, fmt.Errorf("request: %w", err)
+	}
+	return result.Param.Value, nil
+}
+
 func (api API) GetDelegatorValidatorStake(delegator string, validator string) (amount decimal.Decimal, err error) {
 	var result DelegatorValidatorStakeResult
 	err = api.request(fmt.Sprintf("cosmos/staking/v1beta1/validators/%s/delegations/%s", validator, delegator), &result)
+	if IsNotFound(err) {
+		return decimal.Zero, nil
+	}
 	if err != nil {
-		return amount, fmt.Errorf("request: %s", err.Error())
+		return amount, fmt.Errorf("request: %w", err)
 	}
 	return result.DelegationResponse.Delegation.Shares.Div(PrecisionDiv), nil
 }
 
 func (api API) ProposalTallyResult(id uint64) (result ProposalTallyResult, err error) {
-	err = api.request(fmt.Sprintf("/cosmos/gov/v1beta1/proposals/%d/tally", id), &result)
+	err = api.request(fmt.Sprintf("cosmos/gov/v1/proposals/%d/tally", id), &result)
 	if err != nil {
-		return result, fmt.Errorf("request: %s", err.Error())
+		return result, fmt.Errorf("request: %w", err)
 	}
 	return result, nil
 }
 
+// GetTallyParams uses gov v1, v1beta1 returns the params as base64 encoded bytes
+func (api API) GetTallyParams() (params TallyParams, err error) {
+	var result TallyParamsResult
+	err = api.request("cosmos/gov/v1/params/tallying", &result)
+	if err != nil {
+		return params, fmt.Errorf("request: %w", err)
+	}
+	return result.TallyParams, nil
+}
+
+func (api API) GetDistributionParams() (params DistributionParams, err error) {
+	var result DistributionParamsResult
+	err = api.request("cosmos/distribution/v1beta1/params", &result)
+	if err != nil {
+		return params, fmt.Errorf("request: %w", err)
+	}
+	return result.Params, nil
+}
+
+func (api API) GetSlashingParams() (params SlashingParams, err error) {
+	var result SlashingParamsResult
+	err = api.request("cosmos/slashing/v1beta1/params", &result)
+	if err != nil {
+		return params, fmt.Errorf("request: %w", err)
+	}
+	return result.Params, nil
+}
+
 func (api API) GetBlock(id uint64) (result Block, err error) {
 	err = api.request(fmt.Sprintf("/cosmos/base/tendermint/v1beta1/blocks/%d", id), &result)
 	if err != nil {
-		return result, fmt.Errorf("request: %s", err.Error())
+		return result, fmt.Errorf("request: %w", err)
 	}
 	return result, nil
 }
@@ -467,7 +676,7 @@ func (api API) GetBlock(id uint64) (result Block, err error) {
 func (api API) GetTransaction(hash string) (result TxResult, err error) {
 	err = api.request(fmt.Sprintf("/cosmos/tx/v1beta1/txs/%s", hash), &result)
 	if err != nil {
-		return result, fmt.Errorf("request: %s", err.Error())
+		return result, fmt.Errorf("request: %w", err)
 	}
 	return result, nil
 }
@@ -21,7 +21,15 @@ const (
 	SubmitProposalMsg              = "/cosmos.gov.v1beta1.MsgSubmitProposal"
 	DepositMsg                     = "/cosmos.gov.v1beta1.MsgDeposit"
 	VoteMsg                        = "/cosmos.gov.v1beta1.MsgVote"
+	VoteWeightedMsg                = "/cosmos.gov.v1beta1.MsgVoteWeighted"
+	SubmitProposalV1Msg            = "/cosmos.gov.v1.MsgSubmitProposal"
+	DepositV1Msg                   = "/cosmos.gov.v1.MsgDeposit"
+	VoteV1Msg                      = "/cosmos.gov.v1.MsgVote"
+	VoteWeightedV1Msg              = "/cosmos.gov.v1.MsgVoteWeighted"
+	ExecLegacyContentMsg           = "/cosmos.gov.v1.MsgExecLegacyContent"
+	CommunityPoolSpendMsg          = "/cosmos.distribution.v1beta1.MsgCommunityPoolSpend"
 	UnJailMsg                      = "/cosmos.slashing.v1beta1.MsgUnjail"
+	CreateVestingAccountMsg        = "/cosmos.vesting.v1beta1.MsgCreateVestingAccount"
 )
 
 type (
@@ -141,6 +149,13 @@ type (
 		ToAddress   string   `json:"to_address,omitempty"`
 		Amount      []Amount `json:"amount"`
 	}
+	MsgCreateVestingAccount struct {
+		FromAddress string   `json:"from_address"`
+		ToAddress   string   `json:"to_address"`
+		Amount      []Amount `json:"amount"`
+		EndTime     int64    `json:"end_time,string"`
+		Delayed     bool     `json:"delayed"`
+	}
 	MsgMultiSendValue struct {
 		Inputs []struct {
 			Address string   `json:"address"`
@@ -178,27 +193,55 @@ type (
 		ValidatorAddress string `json:"validator_address"`
 	}
 	MsgSubmitProposal struct {
-		Content struct {
-			Type  string `json:"type"`
-			Value struct {
-				Title       string   `json:"title"`
-				Description string   `json:"description"`
-				Recipient   string   `json:"recipient"`
-				Amount      []Amount `json:"amount"`
-			} `json:"value"`
-		} `json:"content"`
-		InitialDeposit []Amount `json:"initial_deposit"`
-		Proposer       string   `json:"proposer"`
+		Content        json.RawMessage `json:"content"`
+		InitialDeposit []Amount        `json:"initial_deposit"`
+		Proposer       string          `json:"proposer"`
+	}
+	LegacyProposalContent struct {
+		Type        string   `json:"@type"`
+		Title       string   `json:"title"`
+		Description string   `json:"description"`
+		Recipient   string   `json:"recipient"`
+		Amount      []Amount `json:"amount"`
 	}
 	MsgDeposit struct {
 		ProposalID uint64   `json:"proposal_id,string"`
 		Depositor  string   `json:"depositor" `
 		Amount     []Amount `json:"amount" `
 	}
+	MsgSubmitProposalV1 struct {
+		Messages       []json.RawMessage `json:"messages"`
+		InitialDeposit []Amount          `json:"initial_deposit"`
+		Proposer       string            `json:"proposer"`
+		Metadata       string            `json:"metadata"`
+		Title          string            `json:"title"`
+		Summary        string            `json:"summary"`
+	}
+	// ProposalMessage holds the fields of the gov v1 proposal messages we are interested in
+	ProposalMessage struct {
+		Type    string `json:"@type"`
+		Content struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+		} `json:"content"`
+		Recipient string   `json:"recipient"`
+		Amount    []Amount `json:"amount"`
+	}
 	MsgVote struct {
 		ProposalID uint64 `json:"proposal_id,string"`
 		Voter      string `json:"voter"`
 		Option     string `json:"option"`
+		Metadata   string `json:"metadata"`
+	}
+	MsgVoteWeighted struct {
+		ProposalID uint64               `json:"proposal_id,string"`
+		Voter      string               `json:"voter"`
+		Options    []WeightedVoteOption `json:"options"`
+		Metadata   string               `json:"metadata"`
+	}
+	WeightedVoteOption struct {
+		Option string          `json:"option"`
+		Weight decimal.Decimal `json:"weight"`
 	}
 	MsgUnjail struct {
 		ValidatorAddr string `json:"validator_addr"`
@@ -219,7 +262,8 @@ type (
 				Type string `json:"@type"`
 				Key  string `json:"key"`
 			} `json:"pub_key"`
-			VotingPower decimal.Decimal `json:"voting_power"`
+			VotingPower      decimal.Decimal `json:"voting_power"`
+			ProposerPriority int64           `json:"proposer_priority,string"`
 		} `json:"validators"`
 	}
 )
@@ -261,7 +305,7 @@ func (api *API) get(endpoint string, params map[string]string, result interface{
 	}
 	resp, err := api.client.Get(fullURL)
 	if err != nil {
-		return fmt.Errorf("client.Get: %s", err.Error())
+		return fmt.Errorf("client.Get: %w", err)
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
@@ -274,16 +318,17 @@ func (api *API) get(endpoint string, params map[string]string, result interface{
 	}
 	data, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("ioutil.ReadAll: %s", err.Error())
+		return fmt.Errorf("ioutil.ReadAll: %w", err)
 	}
 	err = json.Unmarshal(data, result)
 	if err != nil {
-		return fmt.Errorf("json.Unmarshal: %s", err.Error())
+		return fmt.Errorf("json.Unmarshal: %w", err)
 	}
 	return nil
 }
 
 func (api *API) GetValidatorset(height uint64) (set Validatorsets, err error) {
-	err = api.get(fmt.Sprintf("cosmos/base/tendermint/v1beta1/validatorsets/%d", height), nil, &set)
+	params := map[string]string{"pagination.limit": "1000"} // the default page does not cover the whole active set
+	err = api.get(fmt.Sprintf("cosmos/base/tendermint/v1beta1/validatorsets/%d", height), params, &set)
 	return set, err
 }
@@ -16,8 +16,12 @@ const saveGenesisBatch = 100
 type Genesis struct {
 	AppState struct {
 		Accounts []struct {
-			Address string   `json:"address"`
-			Coins   []Amount `json:"coins"`
+			Address         string   `json:"address"`
+			Coins           []Amount `json:"coins"`
+			OriginalVesting []Amount `json:"original_vesting"`
+			// StartTime and EndTime are unix times of the vesting schedule, zero start time means delayed vesting
+			StartTime int64 `json:"start_time,string"`
+			EndTime   int64 `json:"end_time,string"`
 		} `json:"accounts"`
 		Distribution struct {
 			DelegatorStartingInfos []struct {
@@ -57,15 +61,15 @@ type Genesis struct {
 func GetGenesisState() (state Genesis, err error) {
 	resp, err := http.Get(genesisJson)
 	if err != nil {
-		return state, fmt.Errorf("http.Get: %s", err.Error())
+		return state, fmt.Errorf("http.Get: %w", err)
 	}
 	data, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return state, fmt.Errorf("ioutil.ReadAll: %s", err.Error())
+		return state, fmt.Errorf("ioutil.ReadAll: %w", err)
 	}
 	err = json.Unmarshal(data, &state)
 	if err != nil {
-		return state, fmt.Errorf("json.Unmarshal: %s", err.Error())
+		return state, fmt.Errorf("json.Unmarshal: %w", err)
 	}
 
 	return state, nil
@@ -99,15 +103,16 @@ func printStruct(field interface{}, i int) {
 func (p *Parser) parseGenesisState() error {
 	state, err := GetGenesisState()
 	if err != nil {
-		return fmt.Errorf("getGenesisState: %s", err.Error())
+		return fmt.Errorf("getGenesisState: %w", err)
 	}
 	t, err := time.Parse("2006-01-02", "2019-12-11")
 	if err != nil {
-		return fmt.Errorf("time.Parse: %s", err.Error())
+		return fmt.Errorf("time.Parse: %w", err)
 	}
 	var (
-		delegations []dmodels.Delegation
-		accounts    []dmodels.Account
+		delegations     []dmodels.Delegation
+		accounts        []dmodels.Account
+		vestingAccounts []dmodels.VestingAccount
 	)
 	for i, delegation := range state.AppState.Staking.Delegations {
 		delegations = append(delegations, dmodels.Delegation{
@@ -146,6 +151,27 @@ func (p *Parser) parseGenesisState() error {
 			Stake:     accountDelegation[account.Address],
 			CreatedAt: t,
 		})
+		if account.EndTime == 0 {
+			continue
+		}
+		vesting, err := calculateAtomAmount(account.OriginalVesting)
+		if err != nil || vesting.IsZero() {
+			continue
+		}
+		vestingAccount := dmodels.VestingAccount{
+			Address:         account.Address,
+			Type:            dmodels.VestingTypeContinuous,
+			OriginalVesting: vesting,
+			StartTime:       time.Unix(account.StartTime, 0),
+			EndTime:         time.Unix(account.EndTime, 0),
+			TxHash:          "genesis",
+			CreatedAt:       t,
+		}
+		if account.StartTime == 0 {
+			vestingAccount.Type = dmodels.VestingTypeDelayed
+			vestingAccount.StartTime = t
+		}
+		vestingAccounts = append(vestingAccounts, vestingAccount)
 	}
 
 	for i := 0; i < len(accounts); i += saveGenesisBatch {
@@ -155,7 +181,18 @@ func (p *Parser) parseGenesisState() error {
 		}
 		err := p.dao.CreateAccounts(accounts[i:endOfPart])
 		if err != nil {
-			return fmt.Errorf("dao.CreateAccounts: %s", err.Error())
+			return fmt.Errorf("dao.CreateAccounts: %w", err)
+		}
+	}
+
+	for i := 0; i < len(vestingAccounts); i += saveGenesisBatch {
+		endOfPart := i + saveGenesisBatch
+		if i+saveGenesisBatch > len(vestingAccounts) {
+			endOfPart = len(vestingAccounts)
+		}
+		err := p.dao.CreateVestingAccounts(vestingAccounts[i:endOfPart])
+		if err != nil {
+			return fmt.Errorf("dao.CreateVestingAccounts: %w", err)
 		}
 	}
 
@@ -166,7 +203,7 @@ func (p *Parser) parseGenesisState() error {
 		}
 		err := p.dao.CreateDelegations(delegations[i:endOfPart])
 		if err != nil {
-			return fmt.Errorf("dao.CreateDelegations: %s", err.Error())
+			return fmt.Errorf("dao.CreateDelegations: %w", err)
 		}
 	}
 
@@ -16,6 +16,7 @@ import (
 	"github.com/everstake/cosmoscan-api/log"
 	"github.com/everstake/cosmoscan-api/services/helpers"
 	"github.com/everstake/cosmoscan-api/services/node"
+	"github.com/everstake/cosmoscan-api/smodels"
 	"github.com/shopspring/decimal"
 	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/libs/bytes"
@@ -31,6 +32,9 @@ const ParserTitle = "hub3"
 const AddressLength = 45
 
 const batchTxs = 50
+
+// webhookEventsMaxAge is the max age of the parsed blocks which events are sent to the webhooks
+const webhookEventsMaxAge = time.Hour
 const precision = 6
 
 var precisionDiv = decimal.New(1, precision)
@@ -67,6 +71,9 @@ type (
 		jailers          []dmodels.Jailer
 		missedBlocks     []dmodels.MissedBlock
 		accountTxs       []dmodels.AccountTx
+		validatorSet     []dmodels.ValidatorPower
+		validatorPowers  []dmodels.ValidatorPower
+		vestingAccounts  []dmodels.VestingAccount
 	}
 )
 
@@ -96,7 +103,7 @@ func (p *Parser) Run() error {
 	if model.Height == 0 {
 		err = p.parseGenesisState()
 		if err != nil {
-			return fmt.Errorf("parseGenesisState: %s", err.Error())
+			return fmt.Errorf("parseGenesisState: %w", err)
 		}
 		p.setAccounts()
 	}
@@ -172,6 +179,14 @@ func (p *Parser) runFetcher() {
 					continue
 				}
 				set[address] = struct{}{}
+				d.validatorSet = append(d.validatorSet, dmodels.ValidatorPower{
+					ID:               makeHash(fmt.Sprintf("%d.%s", block.Block.Header.Height, address)),
+					Height:           block.Block.Header.Height,
+					Validator:        address,
+					Power:            s.VotingPower,
+					ProposerPriority: s.ProposerPriority,
+					CreatedAt:        block.Block.Header.Time,
+				})
 			}
 
 			precommits := make(map[string]struct{})
@@ -226,6 +241,7 @@ func (p *Parser) runFetcher() {
 					Height:    tx.TxResponse.Height,
 					Messages:  uint64(len(tx.TxResponse.Tx.Body.Messages)),
 					Fee:       fee,
+					FeePayer:  feePayer(tx),
 					GasUsed:   tx.TxResponse.GasUsed,
 					GasWanted: tx.TxResponse.GasWanted,
 					CreatedAt: tx.TxResponse.Timestamp,
@@ -272,12 +288,18 @@ func (p *Parser) runFetcher() {
 							err = d.parseWithdrawValidatorCommissionMsg(i, tx, msg)
 						case SubmitProposalMsg:
 							err = d.parseSubmitProposalMsg(i, tx, msg)
-						case DepositMsg:
+						case SubmitProposalV1Msg:
+							err = d.parseSubmitProposalV1Msg(i, tx, msg)
+						case DepositMsg, DepositV1Msg:
 							err = d.parseDepositMsg(i, tx, msg)
-						case VoteMsg:
+						case VoteMsg, VoteV1Msg:
 							err = d.parseVoteMsg(i, tx, msg)
+						case VoteWeightedMsg, VoteWeightedV1Msg:
+							err = d.parseVoteWeightedMsg(i, tx, msg)
 						case UnJailMsg:
 							err = d.parseUnjailMsg(i, tx, msg)
+						case CreateVestingAccountMsg:
+							err = d.parseCreateVestingAccountMsg(i, tx, msg)
 						}
 						if err != nil {
 							log.Error("Parser: (height: %d): %s", tx.TxResponse.Height, err.Error())
@@ -313,6 +335,22 @@ func (p *Parser) saving() {
 	}
 	p.setAccounts()
 
+	// voting power of the last saved validator set, only changes of the set are saved, the set is loaded
+	// from the database so the validators which have left it while the parser was stopped get the zero power
+	powers := make(map[string]decimal.Decimal)
+	for {
+		saved, err := p.dao.GetValidatorsPowers(filters.ValidatorPowers{})
+		if err != nil {
+			log.Error("Parser: saving: dao.GetValidatorsPowers: %s", err.Error())
+			<-time.After(time.Second * 5)
+			continue
+		}
+		for _, power := range saved {
+			powers[power.Validator] = power.Power
+		}
+		break
+	}
+
 	ticker := time.After(time.Second)
 
 	var dataset []data
@@ -362,6 +400,8 @@ func (p *Parser) saving() {
 			singleData.proposalDeposits = append(singleData.proposalDeposits, item.proposalDeposits...)
 			singleData.missedBlocks = append(singleData.missedBlocks, item.missedBlocks...)
 			singleData.accountTxs = append(singleData.accountTxs, item.accountTxs...)
+			singleData.vestingAccounts = append(singleData.vestingAccounts, item.vestingAccounts...)
+			singleData.validatorPowers = append(singleData.validatorPowers, validatorSetChanges(powers, item)...)
 		}
 		p.wg.Add(1)
 		var err error
@@ -461,7 +501,24 @@ func (p *Parser) saving() {
 			log.Error("Parser: dao.CreateAccountTxs: %s", err.Error())
 			<-time.After(repeatDelay)
 		}
+		for {
+			err = p.dao.CreateValidatorPowers(singleData.validatorPowers)
+			if err == nil {
+				break
+			}
+			log.Error("Parser: dao.CreateValidatorPowers: %s", err.Error())
+			<-time.After(repeatDelay)
+		}
+		for {
+			err = p.dao.CreateVestingAccounts(singleData.vestingAccounts)
+			if err == nil {
+				break
+			}
+			log.Error("Parser: dao.CreateVestingAccounts: %s", err.Error())
+			<-time.After(repeatDelay)
+		}
 		p.saveNewAccounts(singleData)
+		p.emitWebhookEvents(singleData)
 		for {
 			model.Height += uint64(count)
 			err = p.dao.UpdateParser(model)
@@ -471,11 +528,43 @@ func (p *Parser) saving() {
 			log.Error("Parser: dao.UpdateParser: %s", err.Error())
 			<-time.After(repeatDelay)
 		}
-		dataset = append(dataset[count:])
+		p.dao.CacheSet(dao.ParserHeightCacheKey, model.Height, time.Hour)
+		dataset = dataset[count:]
 		p.wg.Done()
 	}
 }
 
+// validatorSetChanges compares the validator set of the block with the previous one and updates powers,
+// validators that have left the set get a record with zero power
+func validatorSetChanges(powers map[string]decimal.Decimal, d data) (changes []dmodels.ValidatorPower) {
+	if len(d.validatorSet) == 0 {
+		return nil
+	}
+	set := make(map[string]struct{})
+	for _, v := range d.validatorSet {
+		set[v.Validator] = struct{}{}
+		power, ok := powers[v.Validator]
+		if !ok || !power.Equal(v.Power) {
+			changes = append(changes, v)
+			powers[v.Validator] = v.Power
+		}
+	}
+	for address := range powers {
+		if _, ok := set[address]; ok {
+			continue
+		}
+		changes = append(changes, dmodels.ValidatorPower{
+			ID:        makeHash(fmt.Sprintf("%d.%s", d.height, address)),
+			Height:    d.height,
+			Validator: address,
+			Power:     decimal.Zero,
+			CreatedAt: d.validatorSet[0].CreatedAt,
+		})
+		delete(powers, address)
+	}
+	return changes
+}
+
 func (p *Parser) setAccounts() {
 	var accounts []dmodels.Account
 	var err error
@@ -529,15 +618,102 @@ func (p *Parser) saveNewAccounts(data data) {
 	}
 }
 
+// emitWebhookEvents saves the deliveries of the transfers and the missed blocks to the webhooks they match,
+// the saving is repeated like the other data of the block, so no events are lost on failures
+func (p *Parser) emitWebhookEvents(data data) {
+	events := webhookEvents(data, time.Now().Add(-webhookEventsMaxAge))
+	if len(events) == 0 {
+		return
+	}
+	var webhooks []dmodels.Webhook
+	var err error
+	for {
+		webhooks, err = p.dao.GetWebhooks(filters.Webhooks{Active: true})
+		if err == nil {
+			break
+		}
+		log.Error("Parser: dao.GetWebhooks: %s", err.Error())
+		<-time.After(repeatDelay)
+	}
+	deliveries, err := dmodels.NewWebhookDeliveries(webhooks, events)
+	if err != nil {
+		log.Error("Parser: dmodels.NewWebhookDeliveries: %s", err.Error())
+		return
+	}
+	for {
+		err = p.dao.CreateWebhookDeliveries(deliveries)
+		if err == nil {
+			break
+		}
+		log.Error("Parser: dao.CreateWebhookDeliveries: %s", err.Error())
+		<-time.After(repeatDelay)
+	}
+}
+
+// webhookEvents makes the events of the blocks created after the given time only,
+// so the webhooks are not flooded with the history while the parser catches up
+func webhookEvents(data data, after time.Time) (events []dmodels.WebhookEvent) {
+	for _, transfer := range data.transfers {
+		if transfer.CreatedAt.Before(after) {
+			continue
+		}
+		event := dmodels.NewWebhookEvent(dmodels.WebhookEventTransfer, transfer.ID, smodels.WebhookTransfer{
+			TxHash:   transfer.TxHash,
+			From:     transfer.From,
+			To:       transfer.To,
+			Amount:   transfer.Amount,
+			Currency: transfer.Currency,
+		}, transfer.CreatedAt)
+		for _, address := range []string{transfer.From, transfer.To} {
+			if strings.TrimSpace(address) != "" {
+				event.Addresses = append(event.Addresses, address)
+			}
+		}
+		event.Amount = transfer.Amount
+		event.Currency = transfer.Currency
+		events = append(events, event)
+	}
+	missed := make(map[string]smodels.WebhookMissedBlocks)
+	var validators []string
+	var createdAt time.Time
+	for _, block := range data.missedBlocks {
+		if block.CreatedAt.Before(after) {
+			continue
+		}
+		item, ok := missed[block.Validator]
+		if !ok {
+			validators = append(validators, block.Validator)
+			item.ConsAddress = block.Validator
+		}
+		item.Heights = append(item.Heights, block.Height)
+		item.Count++
+		missed[block.Validator] = item
+		if block.CreatedAt.After(createdAt) {
+			createdAt = block.CreatedAt
+		}
+	}
+	for _, validator := range validators {
+		item := missed[validator]
+		sort.Slice(item.Heights, func(i, j int) bool {
+			return item.Heights[i] < item.Heights[j]
+		})
+		key := fmt.Sprintf("%s.%d", validator, item.Heights[0])
+		event := dmodels.NewWebhookEvent(dmodels.WebhookEventValidatorMissedBlocks, key, item, createdAt)
+		event.Validators = []string{validator}
+		events = append(events, event)
+	}
+	return events
+}
+
 func (d *data) parseMsgSend(index int, tx Tx, data []byte) (err error) {
 	var m MsgSend
 	err = json.Unmarshal(data, &m)
 	if err != nil {
-		return fmt.Errorf("json.Unmarshal: %s", err.Error())
+		return fmt.Errorf("json.Unmarshal: %w", err)
 	}
 	currency, amount, err := calculateAmount(m.Amount)
 	if err != nil {
-		return fmt.Errorf("calculateAtomAmount: %s", err.Error())
+		return fmt.Errorf("calculateAtomAmount: %w", err)
 	}
 	id := makeHash(fmt.Sprintf("%s.%d", tx.TxResponse.Hash, index))
 	d.transfers = append(d.transfers, dmodels.Transfer{
@@ -552,17 +728,56 @@ func (d *data) parseMsgSend(index int, tx Tx, data []byte) (err error) {
 	return nil
 }
 
+// parseCreateVestingAccountMsg saves the transfer to the new account along with its vesting schedule
+func (d *data) parseCreateVestingAccountMsg(index int, tx Tx, data []byte) (err error) {
+	var m MsgCreateVestingAccount
+	err = json.Unmarshal(data, &m)
+	if err != nil {
+		return fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	currency, amount, err := calculateAmount(m.Amount)
+	if err != nil {
+		return fmt.Errorf("calculateAmount: %w", err)
+	}
+	d.transfers = append(d.transfers, dmodels.Transfer{
+		ID:        makeHash(fmt.Sprintf("%s.%d", tx.TxResponse.Hash, index)),
+		TxHash:    tx.TxResponse.Hash,
+		From:      m.FromAddress,
+		To:        m.ToAddress,
+		Amount:    amount,
+		Currency:  currency,
+		CreatedAt: tx.TxResponse.Timestamp,
+	})
+	if currency != config.Currency {
+		return nil
+	}
+	vestingType := dmodels.VestingTypeContinuous
+	if m.Delayed {
+		vestingType = dmodels.VestingTypeDelayed
+	}
+	d.vestingAccounts = append(d.vestingAccounts, dmodels.VestingAccount{
+		Address:         m.ToAddress,
+		Type:            vestingType,
+		OriginalVesting: amount,
+		StartTime:       tx.TxResponse.Timestamp,
+		EndTime:         time.Unix(m.EndTime, 0),
+		TxHash:          tx.TxResponse.Hash,
+		CreatedAt:       tx.TxResponse.Timestamp,
+	})
+	return nil
+}
+
 func (d *data) parseMultiSendMsg(index int, tx Tx, data []byte) (err error) {
 	var m MsgMultiSendValue
 	err = json.Unmarshal(data, &m)
 	if err != nil {
-		return fmt.Errorf("json.Unmarshal: %s", err.Error())
+		return fmt.Errorf("json.Unmarshal: %w", err)
 	}
 	for i, input := range m.Inputs {
 		id := makeHash(fmt.Sprintf("%s.%d.i.%d", tx.TxResponse.Hash, index, i))
 		currency, amount, err := calculateAmount(input.Coins)
 		if err != nil {
-			return fmt.Errorf("calculateAtomAmount: %s", err.Error())
+			return fmt.Errorf("calculateAtomAmount: %w", err)
 		}
 		d.transfers = append(d.transfers, dmodels.Transfer{
 			ID:        id,
@@ -578,7 +793,7 @@ func (d *data) parseMultiSendMsg(index int, tx Tx, data []byte) (err error) {
 		id := makeHash(fmt.Sprintf("%s.%d.o.%d", tx.TxResponse.Hash, index, i))
 		currency, amount, err := calculateAmount(output.Coins)
 		if err != nil {
-			return fmt.Errorf("calculateAtomAmount: %s", err.Error())
+			return fmt.Errorf("calculateAtomAmount: %w", err)
 		}
 		d.transfers = append(d.transfers, dmodels.Transfer{
 			ID:        id,
@@ -597,11 +812,11 @@ func (d *data) parseDelegateMsg(index int, tx Tx, data []byte) (err error) {
 	var m MsgDelegate
 	err = json.Unmarshal(data, &m)
 	if err != nil {
-		return fmt.Errorf("json.Unmarshal: %s", err.Error())
+		return fmt.Errorf("json.Unmarshal: %w", err)
 	}
 	amount, err := m.Amount.getAmount()
 	if err != nil {
-		return fmt.Errorf("getAmount: %s", err.Error())
+		return fmt.Errorf("getAmount: %w", err)
 	}
 	id := makeHash(fmt.Sprintf("%s.%d", tx.TxResponse.Hash, index))
 	d.delegations = append(d.delegations, dmodels.Delegation{
@@ -619,11 +834,11 @@ func (d *data) parseUndelegateMsg(index int, tx Tx, data []byte) (err error) {
 	var m MsgUndelegate
 	err = json.Unmarshal(data, &m)
 	if err != nil {
-		return fmt.Errorf("json.Unmarshal: %s", err.Error())
+		return fmt.Errorf("json.Unmarshal: %w", err)
 	}
 	amount, err := m.Amount.getAmount()
 	if err != nil {
-		return fmt.Errorf("getAmount: %s", err.Error())
+		return fmt.Errorf("getAmount: %w", err)
 	}
 	id := makeHash(fmt.Sprintf("%s.%d", tx.TxResponse.Hash, index))
 	d.delegations = append(d.delegations, dmodels.Delegation{
@@ -641,29 +856,31 @@ func (d *data) parseBeginRedelegateMsg(index int, tx Tx, data []byte) (err error
 	var m MsgBeginRedelegate
 	err = json.Unmarshal(data, &m)
 	if err != nil {
-		return fmt.Errorf("json.Unmarshal: %s", err.Error())
+		return fmt.Errorf("json.Unmarshal: %w", err)
 	}
 	amount, err := m.Amount.getAmount()
 	if err != nil {
-		return fmt.Errorf("getAmount: %s", err.Error())
+		return fmt.Errorf("getAmount: %w", err)
 	}
 	id := makeHash(fmt.Sprintf("%s.%d.s", tx.TxResponse.Hash, index))
 	d.delegations = append(d.delegations, dmodels.Delegation{
-		ID:        id,
-		TxHash:    tx.TxResponse.Hash,
-		Delegator: m.DelegatorAddress,
-		Validator: m.ValidatorSrcAddress,
-		Amount:    amount.Mul(decimal.NewFromFloat(-1)),
-		CreatedAt: tx.TxResponse.Timestamp,
+		ID:           id,
+		TxHash:       tx.TxResponse.Hash,
+		Delegator:    m.DelegatorAddress,
+		Validator:    m.ValidatorSrcAddress,
+		Amount:       amount.Mul(decimal.NewFromFloat(-1)),
+		Counterparty: m.ValidatorDstAddress,
+		CreatedAt:    tx.TxResponse.Timestamp,
 	})
 	id = makeHash(fmt.Sprintf("%s.%d.d", tx.TxResponse.Hash, index))
 	d.delegations = append(d.delegations, dmodels.Delegation{
-		ID:        id,
-		TxHash:    tx.TxResponse.Hash,
-		Delegator: m.DelegatorAddress,
-		Validator: m.ValidatorDstAddress,
-		Amount:    amount,
-		CreatedAt: tx.TxResponse.Timestamp,
+		ID:           id,
+		TxHash:       tx.TxResponse.Hash,
+		Delegator:    m.DelegatorAddress,
+		Validator:    m.ValidatorDstAddress,
+		Amount:       amount,
+		Counterparty: m.ValidatorSrcAddress,
+		CreatedAt:    tx.TxResponse.Timestamp,
 	})
 	return nil
 }
@@ -672,7 +889,7 @@ func (d *data) parseWithdrawDelegationRewardMsg(index int, tx Tx, data []byte) (
 	var m MsgWithdrawDelegationReward
 	err = json.Unmarshal(data, &m)
 	if err != nil {
-		return fmt.Errorf("json.Unmarshal: %s", err.Error())
+		return fmt.Errorf("json.Unmarshal: %w", err)
 	}
 
 	mp := make(map[string]decimal.Decimal)
@@ -688,12 +905,12 @@ func (d *data) parseWithdrawDelegationRewardMsg(index int, tx Tx, data []byte) (
 					if len(parts) > 1 {
 						amount, err = strToAmount(parts[len(parts)-1])
 						if err != nil {
-							return fmt.Errorf("strToAmount: %s", err.Error())
+							return fmt.Errorf("strToAmount: %w", err)
 						}
 					} else if len(parts) == 1 {
 						amount, err = strToAmount(parts[0])
 						if err != nil {
-							return fmt.Errorf("strToAmount: %s", err.Error())
+							return fmt.Errorf("strToAmount: %w", err)
 						}
 					} else {
 						return fmt.Errorf("parts: small length")
@@ -729,76 +946,162 @@ func (d *data) parseSubmitProposalMsg(index int, tx Tx, data []byte) (err error)
 	var m MsgSubmitProposal
 	err = json.Unmarshal(data, &m)
 	if err != nil {
-		return fmt.Errorf("json.Unmarshal: %s", err.Error())
+		return fmt.Errorf("json.Unmarshal: %w", err)
 	}
-	var id uint64
-	for _, log := range tx.TxResponse.Logs {
-		for _, event := range log.Events {
-			if event.Type == "submit_proposal" {
-				for _, att := range event.Attributes {
-					if att.Key == "proposal_id" {
-						id, err = strconv.ParseUint(att.Value, 10, 64)
-						if err != nil {
-							return fmt.Errorf("strconv.ParseUint: %s", err.Error())
-						}
-					}
-				}
-			}
-		}
+	id, err := findSubmittedProposalID(tx)
+	if err != nil {
+		return fmt.Errorf("findSubmittedProposalID: %w", err)
 	}
-	if id == 0 {
-		return fmt.Errorf("not found proposal_id")
+	var content LegacyProposalContent
+	err = json.Unmarshal(m.Content, &content)
+	if err != nil {
+		return fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	typedContent, err := helpers.DecodeProposalContent(m.Content)
+	if err != nil {
+		return fmt.Errorf("helpers.DecodeProposalContent: %w", err)
 	}
-	amount, err := calculateAtomAmount(m.Content.Value.Amount)
+	amount, err := calculateAtomAmount(atomAmounts(content.Amount))
 	if err != nil {
-		return fmt.Errorf("calculateAtomAmount: %s", err.Error())
+		return fmt.Errorf("calculateAtomAmount: %w", err)
 	}
 	initDeposit, err := calculateAtomAmount(m.InitialDeposit)
 	if err != nil {
-		return fmt.Errorf("calculateAtomAmount: %s", err.Error())
+		return fmt.Errorf("calculateAtomAmount: %w", err)
 	}
 	d.proposals = append(d.proposals, dmodels.HistoryProposal{
 		ID:          id,
 		TxHash:      tx.TxResponse.Hash,
-		Title:       m.Content.Value.Title,
-		Description: m.Content.Value.Description,
-		Recipient:   m.Content.Value.Recipient,
+		Title:       content.Title,
+		Description: content.Description,
+		Recipient:   content.Recipient,
 		Amount:      amount,
 		InitDeposit: initDeposit,
 		Proposer:    m.Proposer,
+		Contents:    dmodels.ProposalContents{typedContent},
 		CreatedAt:   tx.TxResponse.Timestamp,
 	})
 	return nil
 }
 
+func (d *data) parseSubmitProposalV1Msg(index int, tx Tx, data []byte) (err error) {
+	var m MsgSubmitProposalV1
+	err = json.Unmarshal(data, &m)
+	if err != nil {
+		return fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	id, err := findSubmittedProposalID(tx)
+	if err != nil {
+		return fmt.Errorf("findSubmittedProposalID: %w", err)
+	}
+	initDeposit, err := calculateAtomAmount(m.InitialDeposit)
+	if err != nil {
+		return fmt.Errorf("calculateAtomAmount: %w", err)
+	}
+	messages, err := json.Marshal(m.Messages)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+	contents, err := helpers.DecodeProposalContents(messages)
+	if err != nil {
+		return fmt.Errorf("helpers.DecodeProposalContents: %w", err)
+	}
+	proposal := dmodels.HistoryProposal{
+		ID:          id,
+		TxHash:      tx.TxResponse.Hash,
+		Title:       m.Title,
+		Description: m.Summary,
+		Amount:      decimal.Zero,
+		InitDeposit: initDeposit,
+		Proposer:    m.Proposer,
+		Metadata:    m.Metadata,
+		Messages:    string(messages),
+		Contents:    contents,
+		CreatedAt:   tx.TxResponse.Timestamp,
+	}
+	// legacy proposals and community pool spends keep their details inside the messages
+	for _, msg := range m.Messages {
+		var pm ProposalMessage
+		err = json.Unmarshal(msg, &pm)
+		if err != nil {
+			return fmt.Errorf("json.Unmarshal: %w", err)
+		}
+		switch pm.Type {
+		case ExecLegacyContentMsg:
+			if proposal.Title == "" {
+				proposal.Title = pm.Content.Title
+			}
+			if proposal.Description == "" {
+				proposal.Description = pm.Content.Description
+			}
+		case CommunityPoolSpendMsg:
+			amount, err := calculateAtomAmount(atomAmounts(pm.Amount))
+			if err != nil {
+				return fmt.Errorf("calculateAtomAmount: %w", err)
+			}
+			proposal.Recipient = pm.Recipient
+			proposal.Amount = proposal.Amount.Add(amount)
+		}
+	}
+	d.proposals = append(d.proposals, proposal)
+	return nil
+}
+
 func (d *data) parseVoteMsg(index int, tx Tx, data []byte) (err error) {
 	var m MsgVote
 	err = json.Unmarshal(data, &m)
 	if err != nil {
-		return fmt.Errorf("json.Unmarshal: %s", err.Error())
-	}
-	var option string
-	switch m.Option {
-	case "VOTE_OPTION_YES":
-		option = "Yes"
-	case "VOTE_OPTION_ABSTAIN":
-		option = "Abstain"
-	case "VOTE_OPTION_NO":
-		option = "No"
-	case "VOTE_OPTION_NO_WITH_VETO":
-		option = "NoWithVeto"
-	default:
-		return fmt.Errorf("unknown type of option: %d", m.Option)
+		return fmt.Errorf("json.Unmarshal: %w", err)
 	}
-	id := makeHash(fmt.Sprintf("%s.%d.s", tx.TxResponse.Hash, index))
-	d.proposalVotes = append(d.proposalVotes, dmodels.ProposalVote{
-		ID:         id,
-		ProposalID: m.ProposalID,
-		Voter:      m.Voter,
+	return d.addProposalVote(index, tx, m.ProposalID, m.Voter, []WeightedVoteOption{
+		{Option: m.Option, Weight: decimal.New(1, 0)},
+	})
+}
+
+func (d *data) parseVoteWeightedMsg(index int, tx Tx, data []byte) (err error) {
+	var m MsgVoteWeighted
+	err = json.Unmarshal(data, &m)
+	if err != nil {
+		return fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	return d.addProposalVote(index, tx, m.ProposalID, m.Voter, m.Options)
+}
+
+func (d *data) addProposalVote(index int, tx Tx, proposalID uint64, voter string, options []WeightedVoteOption) error {
+	if len(options) == 0 {
+		return fmt.Errorf("empty vote options")
+	}
+	vote := dmodels.ProposalVote{
+		ID:         makeHash(fmt.Sprintf("%s.%d.s", tx.TxResponse.Hash, index)),
+		ProposalID: proposalID,
+		Voter:      voter,
 		TxHash:     tx.TxResponse.Hash,
-		Option:     option,
 		CreatedAt:  dmodels.NewTime(tx.TxResponse.Timestamp),
-	})
+	}
+	for _, o := range options {
+		var option string
+		switch o.Option {
+		case "VOTE_OPTION_YES":
+			option = dmodels.VoteOptionYes
+			vote.WeightYes = vote.WeightYes.Add(o.Weight)
+		case "VOTE_OPTION_ABSTAIN":
+			option = dmodels.VoteOptionAbstain
+			vote.WeightAbstain = vote.WeightAbstain.Add(o.Weight)
+		case "VOTE_OPTION_NO":
+			option = dmodels.VoteOptionNo
+			vote.WeightNo = vote.WeightNo.Add(o.Weight)
+		case "VOTE_OPTION_NO_WITH_VETO":
+			option = dmodels.VoteOptionNoWithVeto
+			vote.WeightNoWithVeto = vote.WeightNoWithVeto.Add(o.Weight)
+		default:
+			return fmt.Errorf("unknown type of option: %s", o.Option)
+		}
+		if vote.Option != "" && vote.Option != option {
+			option = dmodels.VoteOptionWeighted
+		}
+		vote.Option = option
+	}
+	d.proposalVotes = append(d.proposalVotes, vote)
 	return nil
 }
 
@@ -806,13 +1109,13 @@ func (d *data) parseDepositMsg(index int, tx Tx, data []byte) (err error) {
 	var m MsgDeposit
 	err = json.Unmarshal(data, &m)
 	if err != nil {
-		return fmt.Errorf("json.Unmarshal: %s", err.Error())
+		return fmt.Errorf("json.Unmarshal: %w", err)
 	}
 	amount := decimal.Zero
 	for _, a := range m.Amount {
 		amt, err := a.getAmount()
 		if err != nil {
-			return fmt.Errorf("getAmount: %s", err.Error())
+			return fmt.Errorf("getAmount: %w", err)
 		}
 		amount = amount.Add(amt)
 	}
@@ -832,7 +1135,7 @@ func (d *data) parseWithdrawValidatorCommissionMsg(index int, tx Tx, data []byte
 	var m MsgWithdrawValidatorCommission
 	err = json.Unmarshal(data, &m)
 	if err != nil {
-		return fmt.Errorf("json.Unmarshal: %s", err.Error())
+		return fmt.Errorf("json.Unmarshal: %w", err)
 	}
 	var amount decimal.Decimal
 	found := false
@@ -845,12 +1148,12 @@ func (d *data) parseWithdrawValidatorCommissionMsg(index int, tx Tx, data []byte
 						if len(parts) > 1 {
 							amount, err = strToAmount(parts[len(parts)-1])
 							if err != nil {
-								return fmt.Errorf("strToAmount: %s", err.Error())
+								return fmt.Errorf("strToAmount: %w", err)
 							}
 						} else if len(parts) == 1 {
 							amount, err = strToAmount(parts[0])
 							if err != nil {
-								return fmt.Errorf("strToAmount: %s", err.Error())
+								return fmt.Errorf("strToAmount: %w", err)
 							}
 						} else {
 							return fmt.Errorf("parts: small length")
@@ -880,7 +1183,7 @@ func (d *data) parseUnjailMsg(index int, tx Tx, data []byte) (err error) {
 	var m MsgUnjail
 	err = json.Unmarshal(data, &m)
 	if err != nil {
-		return fmt.Errorf("json.Unmarshal: %s", err.Error())
+		return fmt.Errorf("json.Unmarshal: %w", err)
 	}
 	id := makeHash(fmt.Sprintf("%s.%d", tx.TxResponse.Hash, index))
 	d.jailers = append(d.jailers, dmodels.Jailer{
@@ -891,6 +1194,56 @@ func (d *data) parseUnjailMsg(index int, tx Tx, data []byte) (err error) {
 	return nil
 }
 
+// findSubmittedProposalID returns the id of the proposal created by the transaction
+func findSubmittedProposalID(tx Tx) (id uint64, err error) {
+	for _, log := range tx.TxResponse.Logs {
+		for _, event := range log.Events {
+			if event.Type == "submit_proposal" {
+				for _, att := range event.Attributes {
+					if att.Key == "proposal_id" {
+						id, err = strconv.ParseUint(att.Value, 10, 64)
+						if err != nil {
+							return 0, fmt.Errorf("strconv.ParseUint: %w", err)
+						}
+					}
+				}
+			}
+		}
+	}
+	if id == 0 {
+		return 0, fmt.Errorf("not found proposal_id")
+	}
+	return id, nil
+}
+
+// atomAmounts skips the coins of other denominations, spends may transfer several coins at once
+func atomAmounts(amountItems []Amount) (items []Amount) {
+	for _, item := range amountItems {
+		if item.Denom == node.MainUnit {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// feePayer returns the explicit payer of the fee or the first signer, the one charged by default,
+// an empty string if the key of the signer is not a single secp256k1 key
+func feePayer(tx Tx) string {
+	if tx.Tx.AuthInfo.Fee.Payer != "" {
+		return tx.Tx.AuthInfo.Fee.Payer
+	}
+	if len(tx.Tx.AuthInfo.SignerInfos) == 0 {
+		return ""
+	}
+	key := tx.Tx.AuthInfo.SignerInfos[0].PublicKey
+	address, err := helpers.GetBech32FromBase64PK(key.Key, key.Type)
+	if err != nil {
+		log.Warn("Parser: tx %s: helpers.GetBech32FromBase64PK: %s", tx.TxResponse.Hash, err.Error())
+		return ""
+	}
+	return address
+}
+
 func calculateAtomAmount(amountItems []Amount) (decimal.Decimal, error) {
 	volume := decimal.Zero
 	for _, item := range amountItems {
@@ -950,7 +1303,7 @@ func strToAmount(str string) (decimal.Decimal, error) {
 	val := strings.TrimSuffix(str, node.MainUnit)
 	amount, err := decimal.NewFromString(val)
 	if err != nil {
-		return amount, fmt.Errorf("decimal.NewFromString: %s", err.Error())
+		return amount, fmt.Errorf("decimal.NewFromString: %w", err)
 	}
 	amount = amount.Div(precisionDiv)
 	return amount, nil
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/everstake/cosmoscan-api/config"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/everstake/cosmoscan-api/log"
+	"github.com/everstake/cosmoscan-api/smodels"
+	"strings"
+	"time"
+)
+
+const (
+	// priceHistoryChunk keeps the history requests within the range the markets return hourly
+	priceHistoryChunk = time.Hour * 24 * 90
+	priceHistoryPause = time.Second * 3
+)
+
+// priceHistoryStart is the listing of atom on the markets
+var priceHistoryStart = time.Date(2019, 3, 14, 0, 0, 0, 0, time.UTC)
+
+// cryptoMarkets asks the markets in turn until one of them answers
+type cryptoMarkets []CryptoMarket
+
+func (markets cryptoMarkets) GetMarketData(currencies []string) (prices []dmodels.Price, err error) {
+	for _, market := range markets {
+		prices, err = market.GetMarketData(currencies)
+		if err == nil {
+			return prices, nil
+		}
+		log.Warn("cryptoMarkets: %T.GetMarketData: %s", market, err.Error())
+	}
+	return nil, err
+}
+
+func (markets cryptoMarkets) GetPriceHistory(currency string, from, to time.Time) (prices []dmodels.Price, err error) {
+	for _, market := range markets {
+		prices, err = market.GetPriceHistory(currency, from, to)
+		if err == nil {
+			return prices, nil
+		}
+		log.Warn("cryptoMarkets: %T.GetPriceHistory: %s", market, err.Error())
+	}
+	return nil, err
+}
+
+// UpdatePrices saves the current prices and backfills the hourly price history of every quote currency
+func (s *ServiceFacade) UpdatePrices() {
+	hour := time.Now().UTC().Truncate(time.Hour)
+	prices, err := s.cm.GetMarketData(s.quoteCurrencies())
+	if err != nil {
+		log.Error("UpdatePrices: cm.GetMarketData: %s", err.Error())
+	} else {
+		for i := range prices {
+			prices[i].Time = dmodels.NewTime(hour)
+		}
+		err = s.dao.CreatePrices(hourlyPrices(prices))
+		if err != nil {
+			log.Error("UpdatePrices: dao.CreatePrices: %s", err.Error())
+		}
+	}
+	for _, currency := range s.quoteCurrencies() {
+		err = s.backfillPrices(currency, hour)
+		if err != nil {
+			log.Error("UpdatePrices: backfillPrices(%s): %s", currency, err.Error())
+		}
+	}
+}
+
+// backfillPrices saves the price history of the currency up to the hour by chunks,
+// the backfill cursor is kept apart from the saved prices, so a failed chunk is retried on the next run
+// while the current prices are still saved
+func (s *ServiceFacade) backfillPrices(currency string, hour time.Time) error {
+	backfill, err := s.dao.GetPriceBackfill(currency)
+	if err != nil && !errors.Is(err, derrors.ErrNotFound) {
+		return fmt.Errorf("dao.GetPriceBackfill: %w", err)
+	}
+	if err != nil {
+		// the history saved before the cursor existed has no gaps, it ends at the latest price
+		backfill = dmodels.PriceBackfill{Currency: currency, Time: priceHistoryStart}
+		last, err := s.dao.GetPrices(filters.Prices{Currency: currency, Limit: 1})
+		if err != nil {
+			return fmt.Errorf("dao.GetPrices: %w", err)
+		}
+		if len(last) != 0 {
+			backfill.Time = last[0].Time.Add(time.Hour)
+		}
+	}
+	for backfill.Time.Before(hour) {
+		to := backfill.Time.Add(priceHistoryChunk)
+		if to.After(hour) {
+			to = hour
+		}
+		prices, err := s.cm.GetPriceHistory(currency, backfill.Time, to)
+		if err != nil {
+			return fmt.Errorf("cm.GetPriceHistory(%s): %w", backfill.Time.String(), err)
+		}
+		err = s.dao.CreatePrices(hourlyPrices(prices))
+		if err != nil {
+			return fmt.Errorf("dao.CreatePrices: %w", err)
+		}
+		backfill.Time = to
+		err = s.dao.SavePriceBackfill(backfill)
+		if err != nil {
+			return fmt.Errorf("dao.SavePriceBackfill: %w", err)
+		}
+		<-time.After(priceHistoryPause)
+	}
+	return nil
+}
+
+func (s *ServiceFacade) GetQuoteCurrencies() []string {
+	return s.quoteCurrencies()
+}
+
+func (s *ServiceFacade) GetAggPrices(filter filters.PricesAgg) (items []smodels.AggItem, err error) {
+	filter.Currency, err = s.quoteCurrency(filter.Currency)
+	if err != nil {
+		return nil, err
+	}
+	items, err = s.dao.GetAggPrices(filter)
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetAggPrices: %w", err)
+	}
+	return items, nil
+}
+
+// getLastPrice returns the latest saved price in the currency
+func (s *ServiceFacade) getLastPrice(currency string) (price dmodels.Price, err error) {
+	prices, err := s.dao.GetPrices(filters.Prices{Currency: currency, Limit: 1})
+	if err != nil {
+		return price, fmt.Errorf("dao.GetPrices: %w", err)
+	}
+	if len(prices) == 0 {
+		return price, fmt.Errorf("not found any prices in %s", currency)
+	}
+	return prices[0], nil
+}
+
+// quoteCurrencies returns usd followed by the configured currencies
+func (s *ServiceFacade) quoteCurrencies() []string {
+	currencies := []string{config.QuoteCurrency}
+	for _, currency := range s.cfg.Currencies {
+		currency = strings.ToLower(currency)
+		if currency != config.QuoteCurrency {
+			currencies = append(currencies, currency)
+		}
+	}
+	return currencies
+}
+
+// quoteCurrency validates the currency requested for the fiat values, usd by default
+func (s *ServiceFacade) quoteCurrency(currency string) (string, error) {
+	if currency == "" {
+		return config.QuoteCurrency, nil
+	}
+	currency = strings.ToLower(currency)
+	for _, c := range s.quoteCurrencies() {
+		if c == currency {
+			return currency, nil
+		}
+	}
+	return "", derrors.InvalidArgument("unsupported currency: %s", currency)
+}
+
+// hourlyPrices keeps the last price of every hour with the time truncated to the hour
+func hourlyPrices(prices []dmodels.Price) []dmodels.Price {
+	var result []dmodels.Price
+	index := make(map[string]int)
+	for _, price := range prices {
+		hour := price.Time.UTC().Truncate(time.Hour)
+		hash := sha1.Sum([]byte(fmt.Sprintf("price.%s.%s", price.Currency, hour.String())))
+		price.ID = hex.EncodeToString(hash[:])
+		price.Time = dmodels.NewTime(hour)
+		if i, ok := index[price.ID]; ok {
+			result[i] = price
+			continue
+		}
+		index[price.ID] = len(result)
+		result = append(result, price)
+	}
+	return result
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"github.com/everstake/cosmoscan-api/config"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/shopspring/decimal"
+	"testing"
+	"time"
+)
+
+func TestHourlyPrices(t *testing.T) {
+	hour := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+	prices := hourlyPrices([]dmodels.Price{
+		{Currency: "usd", Price: decimal.New(10, 0), Time: dmodels.NewTime(hour.Add(time.Minute * 5))},
+		{Currency: "usd", Price: decimal.New(11, 0), Time: dmodels.NewTime(hour.Add(time.Minute * 55))},
+		{Currency: "eur", Price: decimal.New(9, 0), Time: dmodels.NewTime(hour.Add(time.Minute * 5))},
+		{Currency: "usd", Price: decimal.New(12, 0), Time: dmodels.NewTime(hour.Add(time.Hour))},
+	})
+	if len(prices) != 3 {
+		t.Fatalf("expected 3 prices, got %d", len(prices))
+	}
+	if !prices[0].Price.Equal(decimal.New(11, 0)) || !prices[0].Time.Equal(hour) {
+		t.Errorf("expected the last price of the hour, got %s at %s", prices[0].Price, prices[0].Time)
+	}
+	if prices[0].ID == prices[1].ID {
+		t.Errorf("expected different ids for different currencies")
+	}
+}
+
+func TestQuoteCurrency(t *testing.T) {
+	s := &ServiceFacade{cfg: config.Config{Currencies: []string{"EUR", "btc", "usd"}}}
+	tests := map[string]string{
+		"":    "usd",
+		"USD": "usd",
+		"eur": "eur",
+		"BTC": "btc",
+	}
+	for currency, expected := range tests {
+		c, err := s.quoteCurrency(currency)
+		if err != nil || c != expected {
+			t.Errorf("%s: expected %s, got %s (%v)", currency, expected, c, err)
+		}
+	}
+	if _, err := s.quoteCurrency("jpy"); err == nil {
+		t.Errorf("expected error for unsupported currency")
+	}
+	if currencies := s.quoteCurrencies(); len(currencies) != 3 {
+		t.Errorf("expected 3 currencies, got %v", currencies)
+	}
+}
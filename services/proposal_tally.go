@@ -0,0 +1,149 @@
+package services
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/everstake/cosmoscan-api/log"
+	"github.com/everstake/cosmoscan-api/services/node"
+	"github.com/everstake/cosmoscan-api/smodels"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// MakeProposalTallySnapshots saves the live tally of the proposals in voting period, one snapshot per hour
+func (s *ServiceFacade) MakeProposalTallySnapshots() {
+	proposals, err := s.node.GetProposals()
+	if err != nil {
+		log.Error("MakeProposalTallySnapshots: node.GetProposals: %s", err.Error())
+		return
+	}
+	params, err := s.node.GetTallyParams()
+	if err != nil {
+		log.Error("MakeProposalTallySnapshots: node.GetTallyParams: %s", err.Error())
+		return
+	}
+	stakingPool, err := s.node.GetStakingPool()
+	if err != nil {
+		log.Error("MakeProposalTallySnapshots: node.GetStakingPool: %s", err.Error())
+		return
+	}
+	bonded := stakingPool.Pool.BondedTokens
+	hour := time.Now().UTC().Truncate(time.Hour)
+	var snapshots []dmodels.ProposalTallySnapshot
+	for _, p := range proposals.Proposals {
+		if p.Status != node.VotingPeriodProposalStatus {
+			continue
+		}
+		result, err := s.node.ProposalTallyResult(p.ProposalID)
+		if err != nil {
+			log.Error("MakeProposalTallySnapshots: node.ProposalTallyResult(%d): %s", p.ProposalID, err.Error())
+			continue
+		}
+		tally := nodeTally(result)
+		outcome := proposalOutcome(tally, bonded, params)
+		hash := sha1.Sum([]byte(fmt.Sprintf("%d.%s", p.ProposalID, hour.String())))
+		snapshots = append(snapshots, dmodels.ProposalTallySnapshot{
+			ID:               hex.EncodeToString(hash[:]),
+			ProposalID:       p.ProposalID,
+			Yes:              tally.Yes,
+			Abstain:          tally.Abstain,
+			No:               tally.No,
+			NoWithVeto:       tally.NoWithVeto,
+			Bonded:           bonded,
+			Turnout:          turnout(tally, bonded),
+			QuorumReached:    outcome.QuorumReached,
+			ThresholdReached: outcome.ThresholdReached,
+			Vetoed:           outcome.Vetoed,
+			ProjectedOutcome: outcome.ProjectedOutcome,
+			QuorumNeeded:     outcome.QuorumNeeded,
+			YesNeeded:        outcome.YesNeeded,
+			VetoNeeded:       outcome.VetoNeeded,
+			CreatedAt:        dmodels.NewTime(hour),
+		})
+	}
+	err = s.dao.CreateProposalTallySnapshots(snapshots)
+	if err != nil {
+		log.Error("MakeProposalTallySnapshots: dao.CreateProposalTallySnapshots: %s", err.Error())
+	}
+}
+
+func (s *ServiceFacade) GetProposalTallySnapshots(filter filters.ProposalTallySnapshots) (snapshots []dmodels.ProposalTallySnapshot, err error) {
+	snapshots, err = s.dao.GetProposalTallySnapshots(filter)
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetProposalTallySnapshots: %w", err)
+	}
+	return snapshots, nil
+}
+
+func nodeTally(result node.ProposalTallyResult) smodels.ProposalTally {
+	return smodels.ProposalTally{
+		Yes:        decimal.NewFromInt(result.Tally.Yes).Div(node.PrecisionDiv),
+		Abstain:    decimal.NewFromInt(result.Tally.Abstain).Div(node.PrecisionDiv),
+		No:         decimal.NewFromInt(result.Tally.No).Div(node.PrecisionDiv),
+		NoWithVeto: decimal.NewFromInt(result.Tally.NoWithVeto).Div(node.PrecisionDiv),
+	}
+}
+
+// turnout returns the percent of the bonded stake that has voted
+func turnout(tally smodels.ProposalTally, bonded decimal.Decimal) decimal.Decimal {
+	if bonded.IsZero() {
+		return decimal.Zero
+	}
+	return tally.Total().Div(bonded).Mul(decimal.New(100, 0)).Truncate(2)
+}
+
+// proposalOutcome applies the rules of the gov module tally to the current votes:
+// quorum of the bonded stake must vote, NoWithVeto must not exceed the veto threshold of all votes
+// and Yes must exceed the threshold of the votes without Abstain
+func proposalOutcome(tally smodels.ProposalTally, bonded decimal.Decimal, params node.TallyParams) (outcome dmodels.ProposalOutcome) {
+	total := tally.Total()
+	nonAbstain := total.Sub(tally.Abstain)
+
+	outcome.QuorumNeeded = params.Quorum.Mul(bonded).Sub(total)
+	outcome.QuorumReached = !outcome.QuorumNeeded.IsPositive()
+	if !total.IsZero() {
+		outcome.Vetoed = tally.NoWithVeto.Div(total).GreaterThan(params.VetoThreshold)
+	}
+	if !nonAbstain.IsZero() {
+		outcome.ThresholdReached = tally.Yes.Div(nonAbstain).GreaterThan(params.Threshold)
+	}
+
+	// x more votes of the option are needed to reach the share t: (votes + x) / (base + x) = t
+	needed := func(votes decimal.Decimal, base decimal.Decimal, t decimal.Decimal) decimal.Decimal {
+		if t.GreaterThanOrEqual(decimal.New(1, 0)) {
+			return decimal.Zero
+		}
+		return t.Mul(base).Sub(votes).Div(decimal.New(1, 0).Sub(t))
+	}
+	if !outcome.ThresholdReached {
+		outcome.YesNeeded = needed(tally.Yes, nonAbstain, params.Threshold)
+	}
+	if !outcome.Vetoed {
+		outcome.VetoNeeded = needed(tally.NoWithVeto, total, params.VetoThreshold)
+	}
+	outcome.QuorumNeeded = nonNegative(outcome.QuorumNeeded).Truncate(6)
+	outcome.YesNeeded = nonNegative(outcome.YesNeeded).Truncate(6)
+	outcome.VetoNeeded = nonNegative(outcome.VetoNeeded).Truncate(6)
+
+	switch {
+	case !outcome.QuorumReached:
+		outcome.ProjectedOutcome = dmodels.ProposalOutcomeNoQuorum
+	case outcome.Vetoed:
+		outcome.ProjectedOutcome = dmodels.ProposalOutcomeVetoed
+	case outcome.ThresholdReached:
+		outcome.ProjectedOutcome = dmodels.ProposalOutcomePassed
+	default:
+		outcome.ProjectedOutcome = dmodels.ProposalOutcomeRejected
+	}
+	return outcome
+}
+
+func nonNegative(d decimal.Decimal) decimal.Decimal {
+	if d.IsNegative() {
+		return decimal.Zero
+	}
+	return d
+}
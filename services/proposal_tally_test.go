@@ -0,0 +1,77 @@
+package services
+
+import (
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/everstake/cosmoscan-api/services/node"
+	"github.com/everstake/cosmoscan-api/smodels"
+	"github.com/shopspring/decimal"
+	"testing"
+)
+
+func TestProposalOutcome(t *testing.T) {
+	params := node.TallyParams{
+		Quorum:        decimal.RequireFromString("0.4"),
+		Threshold:     decimal.RequireFromString("0.5"),
+		VetoThreshold: decimal.RequireFromString("0.334"),
+	}
+	d := decimal.RequireFromString
+	tests := []struct {
+		name     string
+		tally    smodels.ProposalTally
+		expected dmodels.ProposalOutcome
+	}{
+		{
+			name:  "no quorum",
+			tally: smodels.ProposalTally{Yes: d("100"), No: d("50"), Abstain: d("50"), NoWithVeto: d("0")},
+			expected: dmodels.ProposalOutcome{
+				ThresholdReached: true,
+				ProjectedOutcome: dmodels.ProposalOutcomeNoQuorum,
+				QuorumNeeded:     d("200"),
+				VetoNeeded:       d("100.300300"),
+			},
+		},
+		{
+			name:  "passed",
+			tally: smodels.ProposalTally{Yes: d("300"), No: d("100"), Abstain: d("100"), NoWithVeto: d("0")},
+			expected: dmodels.ProposalOutcome{
+				QuorumReached:    true,
+				ThresholdReached: true,
+				ProjectedOutcome: dmodels.ProposalOutcomePassed,
+				VetoNeeded:       d("250.750750"),
+			},
+		},
+		{
+			name:  "rejected",
+			tally: smodels.ProposalTally{Yes: d("100"), No: d("300"), Abstain: d("100"), NoWithVeto: d("0")},
+			expected: dmodels.ProposalOutcome{
+				QuorumReached:    true,
+				ProjectedOutcome: dmodels.ProposalOutcomeRejected,
+				YesNeeded:        d("200"),
+				VetoNeeded:       d("250.750750"),
+			},
+		},
+		{
+			name:  "vetoed",
+			tally: smodels.ProposalTally{Yes: d("300"), No: d("0"), Abstain: d("0"), NoWithVeto: d("200")},
+			expected: dmodels.ProposalOutcome{
+				QuorumReached:    true,
+				ThresholdReached: true,
+				Vetoed:           true,
+				ProjectedOutcome: dmodels.ProposalOutcomeVetoed,
+			},
+		},
+	}
+	for _, test := range tests {
+		outcome := proposalOutcome(test.tally, d("1000"), params)
+		e := test.expected
+		if outcome.QuorumReached != e.QuorumReached || outcome.ThresholdReached != e.ThresholdReached ||
+			outcome.Vetoed != e.Vetoed || outcome.ProjectedOutcome != e.ProjectedOutcome {
+			t.Errorf("%s: unexpected outcome %+v", test.name, outcome)
+		}
+		if !outcome.QuorumNeeded.Equal(e.QuorumNeeded) || !outcome.YesNeeded.Equal(e.YesNeeded) ||
+			!outcome.VetoNeeded.Equal(e.VetoNeeded) {
+			t.Errorf("%s: unexpected needed votes: quorum %s, yes %s, veto %s",
+				test.name, outcome.QuorumNeeded, outcome.YesNeeded, outcome.VetoNeeded)
+		}
+	}
+}
@@ -4,24 +4,113 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/everstake/cosmoscan-api/dao/filters"
 	"github.com/everstake/cosmoscan-api/dmodels"
 	"github.com/everstake/cosmoscan-api/log"
+	"github.com/everstake/cosmoscan-api/services/helpers"
 	"github.com/everstake/cosmoscan-api/services/node"
 	"github.com/everstake/cosmoscan-api/smodels"
 	"github.com/shopspring/decimal"
+	"sort"
 	"strings"
 	"time"
 )
 
+const avgBlocksDelayCacheKey = "avg_blocks_delay"
+
 func (s *ServiceFacade) GetProposals(filter filters.Proposals) (proposals []dmodels.Proposal, err error) {
 	proposals, err = s.dao.GetProposals(filter)
 	if err != nil {
-		return nil, fmt.Errorf("dao.GetProposals: %s", err.Error())
+		return nil, fmt.Errorf("dao.GetProposals: %w", err)
+	}
+	err = s.setUpgradeCountdown(proposals)
+	if err != nil {
+		return nil, fmt.Errorf("setUpgradeCountdown: %w", err)
 	}
 	return proposals, nil
 }
 
+// proposalContents decodes the typed content of the proposal. The current values of the changed params
+// are remembered while the proposal is not executed, so the diff is kept after the execution.
+func (s *ServiceFacade) proposalContents(content node.ProposalContent, hps []dmodels.HistoryProposal, previous dmodels.ProposalContents, status string) (contents dmodels.ProposalContents, err error) {
+	switch {
+	case content.Type != "":
+		c, err := helpers.DecodeProposalContent(content.Raw)
+		if err != nil {
+			return nil, fmt.Errorf("helpers.DecodeProposalContent: %w", err)
+		}
+		contents = dmodels.ProposalContents{c}
+	case len(hps) > 0:
+		contents = hps[0].Contents
+	}
+	oldValues := make(map[string]string)
+	for _, c := range previous {
+		for _, change := range c.Changes {
+			if change.OldValue != "" {
+				oldValues[change.Subspace+"/"+change.Key] = change.OldValue
+			}
+		}
+	}
+	pending := status == node.DepositPeriodProposalStatus || status == node.VotingPeriodProposalStatus
+	for i := range contents {
+		for j := range contents[i].Changes {
+			change := &contents[i].Changes[j]
+			value, ok := oldValues[change.Subspace+"/"+change.Key]
+			if ok {
+				change.OldValue = value
+				continue
+			}
+			if !pending {
+				continue
+			}
+			change.OldValue, err = s.node.GetParam(change.Subspace, change.Key)
+			if err != nil {
+				return nil, fmt.Errorf("node.GetParam: %w", err)
+			}
+		}
+	}
+	return contents, nil
+}
+
+// setUpgradeCountdown estimates the time of the upcoming software upgrades by the average block time of the last day
+func (s *ServiceFacade) setUpgradeCountdown(proposals []dmodels.Proposal) error {
+	var plans []*dmodels.UpgradePlan
+	for _, p := range proposals {
+		plan := p.Contents.Plan()
+		if plan != nil && p.Status != "Rejected" && p.Status != "Failed" {
+			plans = append(plans, plan)
+		}
+	}
+	if len(plans) == 0 {
+		return nil
+	}
+	blocks, err := s.dao.GetBlocks(filters.Blocks{Limit: 1})
+	if err != nil {
+		return fmt.Errorf("dao.GetBlocks: %w", err)
+	}
+	if len(blocks) == 0 {
+		return nil
+	}
+	data, err := s.dao.CacheLoad(avgBlocksDelayCacheKey, time.Minute*10, func() (interface{}, error) {
+		return s.dao.GetAvgBlocksDelay(filters.TimeRange{From: dmodels.NewTime(time.Now().Add(-time.Hour * 24))})
+	})
+	if err != nil {
+		return fmt.Errorf("dao.GetAvgBlocksDelay: %w", err)
+	}
+	delay := data.(float64)
+	latest := blocks[0]
+	for _, plan := range plans {
+		if plan.Height <= latest.ID {
+			continue
+		}
+		plan.BlocksLeft = plan.Height - latest.ID
+		estimated := dmodels.NewTime(latest.CreatedAt.Add(time.Duration(float64(plan.BlocksLeft) * delay * float64(time.Second))))
+		plan.EstimatedTime = &estimated
+	}
+	return nil
+}
+
 func (s *ServiceFacade) UpdateProposals() {
 	nodeProposals, err := s.node.GetProposals()
 	if err != nil {
@@ -53,11 +142,17 @@ func (s *ServiceFacade) UpdateProposals() {
 		return
 	}
 
+	tallyParams, err := s.node.GetTallyParams()
+	if err != nil {
+		log.Error("UpdateProposals: node.GetTallyParams: %s", err.Error())
+		return
+	}
+
 	for _, p := range nodeProposals.Proposals {
 		votes, err := s.GetProposalVotes(filters.ProposalVotes{ProposalID: p.ProposalID})
 		if err != nil {
-			log.Error("UpdateProposals: GetProposalVotes: %s", err.Error())
-			return
+			log.Error("UpdateProposals: GetProposalVotes(%d): %s", p.ProposalID, err.Error())
+			continue
 		}
 		votersTotal := len(votes)
 		participationRate := decimal.Zero
@@ -70,8 +165,8 @@ func (s *ServiceFacade) UpdateProposals() {
 			Limit: 1,
 		})
 		if err != nil {
-			log.Error("UpdateProposals: dao.GetProposals: %s", err.Error())
-			return
+			log.Error("UpdateProposals: dao.GetProposals(%d): %s", p.ProposalID, err.Error())
+			continue
 		}
 		var proposerAddress string
 		if len(proposals) > 0 {
@@ -83,43 +178,42 @@ func (s *ServiceFacade) UpdateProposals() {
 			totalDeposit = totalDeposit.Add(value.Amount)
 		}
 
-		activityItems, err := s.dao.GetAggProposalVotes(filters.Agg{
-			By: filters.AggByDay,
-		}, []uint64{p.ProposalID})
+		activityItems, err := s.dao.GetAggProposalVotes(filters.ProposalVotesAgg{
+			Agg:        filters.Agg{By: filters.AggByDay},
+			ProposalID: []uint64{p.ProposalID},
+		})
 		activityJson, _ := json.Marshal(activityItems)
 
 		hps, err := s.dao.GetHistoryProposals(filters.HistoryProposals{ID: []uint64{p.ProposalID}})
 		if err != nil {
-			log.Error("UpdateProposals: dao.GetHistoryProposals: %s", err.Error())
-			return
+			log.Error("UpdateProposals: dao.GetHistoryProposals(%d): %s", p.ProposalID, err.Error())
+			continue
 		}
-		var txHash string
+		var txHash, metadata string
+		messages := json.RawMessage("[]")
 		if len(hps) > 0 {
 			txHash = hps[0].TxHash
+			metadata = hps[0].Metadata
+			if hps[0].Messages != "" {
+				messages = json.RawMessage(hps[0].Messages)
+			}
 		}
 
-		var yes, abstain, no, noWithVeto decimal.Decimal
+		var tally smodels.ProposalTally
 		if p.Status == node.VotingPeriodProposalStatus {
-			tally, err := s.node.ProposalTallyResult(p.ProposalID)
+			result, err := s.node.ProposalTallyResult(p.ProposalID)
 			if err != nil {
-				log.Error("UpdateProposals: node.ProposalTallyResult: %s", err.Error())
-				return
+				log.Error("UpdateProposals: node.ProposalTallyResult(%d): %s", p.ProposalID, err.Error())
+				continue
 			}
-			yes = decimal.NewFromInt(tally.Tally.Yes).Div(node.PrecisionDiv)
-			abstain = decimal.NewFromInt(tally.Tally.Abstain).Div(node.PrecisionDiv)
-			no = decimal.NewFromInt(tally.Tally.No).Div(node.PrecisionDiv)
-			noWithVeto = decimal.NewFromInt(tally.Tally.NoWithVeto).Div(node.PrecisionDiv)
+			tally = nodeTally(result)
 		} else {
-			yes = decimal.NewFromInt(p.FinalTallyResult.Yes).Div(node.PrecisionDiv)
-			abstain = decimal.NewFromInt(p.FinalTallyResult.Abstain).Div(node.PrecisionDiv)
-			no = decimal.NewFromInt(p.FinalTallyResult.No).Div(node.PrecisionDiv)
-			noWithVeto = decimal.NewFromInt(p.FinalTallyResult.NoWithVeto).Div(node.PrecisionDiv)
-		}
-
-		turnout := decimal.Zero
-		if !totalStake.Pool.BondedTokens.IsZero() {
-			turnout = yes.Add(abstain).Add(no).Add(noWithVeto).Div(totalStake.Pool.BondedTokens)
-			turnout = turnout.Mul(decimal.NewFromFloat(100)).Truncate(2)
+			tally = smodels.ProposalTally{
+				Yes:        decimal.NewFromInt(p.FinalTallyResult.Yes).Div(node.PrecisionDiv),
+				Abstain:    decimal.NewFromInt(p.FinalTallyResult.Abstain).Div(node.PrecisionDiv),
+				No:         decimal.NewFromInt(p.FinalTallyResult.No).Div(node.PrecisionDiv),
+				NoWithVeto: decimal.NewFromInt(p.FinalTallyResult.NoWithVeto).Div(node.PrecisionDiv),
+			}
 		}
 
 		proposer := proposerAddress
@@ -149,19 +243,40 @@ func (s *ServiceFacade) UpdateProposals() {
 			proposalType = proposalTypeParts[len(proposalTypeParts)-1]
 		}
 
+		var previousContents dmodels.ProposalContents
+		if len(proposals) > 0 {
+			previousContents = proposals[0].Contents
+		}
+		contents, err := s.proposalContents(p.Content, hps, previousContents, p.Status)
+		if err != nil {
+			log.Error("UpdateProposals: proposalContents(%d): %s", p.ProposalID, err.Error())
+			continue
+		}
+		// gov v1 proposals have no legacy content, their details come from the submitted messages
+		title, description := p.Content.Title, p.Content.Description
+		if title == "" && len(hps) > 0 {
+			title, description = hps[0].Title, hps[0].Description
+		}
+		if proposalType == "" && len(contents) > 0 {
+			proposalType = contents[0].Type
+		}
+
 		proposal := dmodels.Proposal{
 			ID:                p.ProposalID,
 			TxHash:            txHash,
 			Type:              proposalType,
 			Proposer:          proposer,
 			ProposerAddress:   proposerAddress,
-			Title:             p.Content.Title,
-			Description:       p.Content.Description,
+			Title:             title,
+			Description:       description,
+			Metadata:          metadata,
+			Messages:          messages,
+			Contents:          contents,
 			Status:            status,
-			VotesYes:          yes,
-			VotesAbstain:      abstain,
-			VotesNo:           no,
-			VotesNoWithVeto:   noWithVeto,
+			VotesYes:          tally.Yes,
+			VotesAbstain:      tally.Abstain,
+			VotesNo:           tally.No,
+			VotesNoWithVeto:   tally.NoWithVeto,
 			SubmitTime:        dmodels.NewTime(p.SubmitTime),
 			DepositEndTime:    dmodels.NewTime(p.DepositEndTime),
 			TotalDeposits:     totalDeposit.Div(node.PrecisionDiv),
@@ -169,10 +284,18 @@ func (s *ServiceFacade) UpdateProposals() {
 			VotingEndTime:     dmodels.NewTime(p.VotingEndTime),
 			Voters:            uint64(votersTotal),
 			ParticipationRate: participationRate,
-			Turnout:           turnout,
+			Turnout:           turnout(tally, totalStake.Pool.BondedTokens),
 			Activity:          activityJson,
 		}
 
+		// the outcome is projected only while voting, afterwards the status is final
+		switch p.Status {
+		case node.VotingPeriodProposalStatus:
+			proposal.ProposalOutcome = proposalOutcome(tally, totalStake.Pool.BondedTokens, tallyParams)
+		case node.PassedProposalStatus, node.RejectedProposalStatus:
+			proposal.ProjectedOutcome = status
+		}
+
 		if proposal.VotingStartTime.Unix() < 0 {
 			proposal.VotingStartTime = dmodels.Time{Time: time.Unix(0, 0)}
 		}
@@ -186,8 +309,28 @@ func (s *ServiceFacade) UpdateProposals() {
 			err = s.dao.UpdateProposal(proposal)
 		}
 		if err != nil {
-			log.Error("UpdateProposals: save/update proposal: %s", err.Error())
-			return
+			log.Error("UpdateProposals: save/update proposal(%d): %s", p.ProposalID, err.Error())
+			continue
+		}
+
+		var previousStatus string
+		if len(proposals) > 0 {
+			previousStatus = proposals[0].Status
+		}
+		// new proposals are announced while they are open only, so that the first run doesn't announce the history
+		active := status == "DepositPeriod" || status == "VotingPeriod"
+		if previousStatus != status && (previousStatus != "" || active) {
+			event := dmodels.NewWebhookEvent(dmodels.WebhookEventProposalStatus, fmt.Sprintf("%d.%s", p.ProposalID, status), smodels.WebhookProposalStatus{
+				ProposalID:     p.ProposalID,
+				Title:          title,
+				PreviousStatus: previousStatus,
+				Status:         status,
+				VotingEndTime:  proposal.VotingEndTime,
+			}, time.Now())
+			err = s.emitWebhookEvents([]dmodels.WebhookEvent{event})
+			if err != nil {
+				log.Error("UpdateProposals: emitWebhookEvents: %s", err.Error())
+			}
 		}
 	}
 }
@@ -198,11 +341,11 @@ func (s *ServiceFacade) GetProposalVotes(filter filters.ProposalVotes) (items []
 	filter.Limit = 0
 	votes, err := s.dao.GetProposalVotes(filter)
 	if err != nil {
-		return nil, fmt.Errorf("dao.GetProposalVotes: %s", err.Error())
+		return nil, fmt.Errorf("dao.GetProposalVotes: %w", err)
 	}
 	vm, err := s.GetValidatorMap()
 	if err != nil {
-		return nil, fmt.Errorf("GetValidatorMap: %s", err.Error())
+		return nil, fmt.Errorf("GetValidatorMap: %w", err)
 	}
 	validatorsMap := make(map[string]node.Validator)
 	for _, validator := range vm {
@@ -236,10 +379,18 @@ func (s *ServiceFacade) GetProposalVotes(filter filters.ProposalVotes) (items []
 	return items, nil
 }
 
+func (s *ServiceFacade) GetAggProposalVotes(filter filters.ProposalVotesAgg) (items []smodels.AggItem, err error) {
+	items, err = s.dao.GetAggProposalVotes(filter)
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetAggProposalVotes: %w", err)
+	}
+	return items, nil
+}
+
 func (s *ServiceFacade) GetProposalDeposits(filter filters.ProposalDeposits) (deposits []dmodels.ProposalDeposit, err error) {
 	deposits, err = s.dao.GetProposalDeposits(filter)
 	if err != nil {
-		return nil, fmt.Errorf("dao.GetProposalDeposits: %s", err.Error())
+		return nil, fmt.Errorf("dao.GetProposalDeposits: %w", err)
 	}
 	return deposits, nil
 }
@@ -247,11 +398,11 @@ func (s *ServiceFacade) GetProposalDeposits(filter filters.ProposalDeposits) (de
 func (s *ServiceFacade) GetProposalsChartData() (items []smodels.ProposalChartData, err error) {
 	proposals, err := s.dao.GetProposals(filters.Proposals{})
 	if err != nil {
-		return nil, fmt.Errorf("dao.GetProposals: %s", err.Error())
+		return nil, fmt.Errorf("dao.GetProposals: %w", err)
 	}
 	validators, err := s.GetValidatorMap()
 	if err != nil {
-		return nil, fmt.Errorf("GetValidatorMap: %s", err.Error())
+		return nil, fmt.Errorf("GetValidatorMap: %w", err)
 	}
 	validatorsMap := make(map[string]node.Validator)
 	for _, validator := range validators {
@@ -263,7 +414,7 @@ func (s *ServiceFacade) GetProposalsChartData() (items []smodels.ProposalChartDa
 	for _, p := range proposals {
 		votes, err := s.dao.GetProposalVotes(filters.ProposalVotes{ProposalID: p.ID})
 		if err != nil {
-			return nil, fmt.Errorf("dao.GetProposalVotes: %s", err.Error())
+			return nil, fmt.Errorf("dao.GetProposalVotes: %w", err)
 		}
 		var validatorsTotal uint64
 		for _, vote := range votes {
@@ -295,3 +446,186 @@ func (s *ServiceFacade) GetProposalsChartData() (items []smodels.ProposalChartDa
 
 	return items, nil
 }
+
+func (s *ServiceFacade) GetProposal(id uint64) (detail smodels.ProposalDetail, err error) {
+	proposals, err := s.dao.GetProposals(filters.Proposals{ID: []uint64{id}, Limit: 1})
+	if err != nil {
+		return detail, fmt.Errorf("dao.GetProposals: %w", err)
+	}
+	if len(proposals) == 0 {
+		return detail, derrors.NotFound("proposal not found")
+	}
+	err = s.setUpgradeCountdown(proposals)
+	if err != nil {
+		return detail, fmt.Errorf("setUpgradeCountdown: %w", err)
+	}
+	p := proposals[0]
+	detail.Proposal = p
+	detail.Timeline = proposalTimeline(p)
+
+	detail.Deposits, err = s.dao.GetProposalDeposits(filters.ProposalDeposits{ProposalID: []uint64{id}})
+	if err != nil {
+		return detail, fmt.Errorf("dao.GetProposalDeposits: %w", err)
+	}
+
+	// stakes are taken at the end of voting, the same moment the chain makes the final tally
+	stakesAt := time.Now()
+	if p.Status != "DepositPeriod" && p.Status != "VotingPeriod" && p.VotingEndTime.Unix() > 0 {
+		stakesAt = p.VotingEndTime.Time
+	}
+
+	votes, err := s.dao.GetProposalVotes(filters.ProposalVotes{ProposalID: id})
+	if err != nil {
+		return detail, fmt.Errorf("dao.GetProposalVotes: %w", err)
+	}
+	// votes are sorted by time, so the latest vote of the voter wins
+	votesMap := make(map[string]dmodels.ProposalVote)
+	for _, vote := range votes {
+		if vote.CreatedAt.After(stakesAt) {
+			continue
+		}
+		votesMap[vote.Voter] = vote
+	}
+
+	vm, err := s.GetValidatorMap()
+	if err != nil {
+		return detail, fmt.Errorf("GetValidatorMap: %w", err)
+	}
+	// only bonded validators take part in the tally
+	validators := make(map[string]node.Validator)
+	accValidators := make(map[string]string)
+	var operators []string
+	for _, validator := range vm {
+		bench, _ := types.ValAddressFromBech32(validator.OperatorAddress)
+		accAddress := types.AccAddress(bench.Bytes()).String()
+		_, voted := votesMap[accAddress]
+		if validator.Status != node.BondedValidatorStatus && !voted {
+			continue
+		}
+		validators[validator.OperatorAddress] = validator
+		accValidators[accAddress] = validator.OperatorAddress
+		operators = append(operators, validator.OperatorAddress)
+	}
+
+	var delegators []string
+	for voter := range votesMap {
+		if _, ok := accValidators[voter]; !ok {
+			delegators = append(delegators, voter)
+		}
+	}
+
+	power := make(map[string]decimal.Decimal)
+	if len(operators) != 0 {
+		stakes, err := s.dao.GetValidatorsStake(filters.DelegationStakes{
+			Validators: operators,
+			To:         dmodels.NewTime(stakesAt),
+		})
+		if err != nil {
+			return detail, fmt.Errorf("dao.GetValidatorsStake: %w", err)
+		}
+		for _, stake := range stakes {
+			power[stake.Validator] = stake.Amount
+		}
+	}
+
+	var delegatorStakes []dmodels.DelegationStake
+	if len(delegators) != 0 && len(operators) != 0 {
+		delegatorStakes, err = s.dao.GetDelegationStakes(filters.DelegationStakes{
+			Delegators: delegators,
+			Validators: operators,
+			To:         dmodels.NewTime(stakesAt),
+		})
+		if err != nil {
+			return detail, fmt.Errorf("dao.GetDelegationStakes: %w", err)
+		}
+	}
+
+	validatorVote := func(operator string) (vote dmodels.ProposalVote, ok bool) {
+		bench, _ := types.ValAddressFromBech32(operator)
+		vote, ok = votesMap[types.AccAddress(bench.Bytes()).String()]
+		return vote, ok
+	}
+
+	// a delegator's own vote overrides the vote inherited from the validator for the delegated stake
+	deducted := make(map[string]decimal.Decimal)
+	for _, stake := range delegatorStakes {
+		vote := votesMap[stake.Delegator]
+		detail.Tally.Add(vote, stake.Amount)
+		deducted[stake.Validator] = deducted[stake.Validator].Add(stake.Amount)
+		vVote, ok := validatorVote(stake.Validator)
+		if !ok || vVote.SameChoice(vote) {
+			continue
+		}
+		detail.Overrides = append(detail.Overrides, smodels.ProposalVoteOverride{
+			Delegator:       stake.Delegator,
+			Validator:       stake.Validator,
+			ValidatorTitle:  validators[stake.Validator].Description.Moniker,
+			Option:          vote.Option,
+			ValidatorOption: vVote.Option,
+			Amount:          stake.Amount,
+		})
+	}
+	sort.Slice(detail.Overrides, func(i, j int) bool {
+		return detail.Overrides[i].Amount.GreaterThan(detail.Overrides[j].Amount)
+	})
+
+	for operator, validator := range validators {
+		effectivePower := power[operator].Sub(deducted[operator])
+		if effectivePower.IsNegative() {
+			effectivePower = decimal.Zero
+		}
+		bench, _ := types.ValAddressFromBech32(operator)
+		item := smodels.ProposalValidatorVote{
+			Title:           validator.Description.Moniker,
+			OperatorAddress: operator,
+			Voter:           types.AccAddress(bench.Bytes()).String(),
+			Power:           power[operator],
+			EffectivePower:  effectivePower,
+		}
+		if vote, ok := validatorVote(operator); ok {
+			item.Voted = true
+			item.Option = vote.Option
+			item.TxHash = vote.TxHash
+			item.VotedAt = vote.CreatedAt
+			detail.Tally.Add(vote, effectivePower)
+		}
+		detail.ValidatorVotes = append(detail.ValidatorVotes, item)
+	}
+	sort.Slice(detail.ValidatorVotes, func(i, j int) bool {
+		return detail.ValidatorVotes[i].Power.GreaterThan(detail.ValidatorVotes[j].Power)
+	})
+
+	detail.NodeTally = smodels.ProposalTally{
+		Yes:        p.VotesYes,
+		Abstain:    p.VotesAbstain,
+		No:         p.VotesNo,
+		NoWithVeto: p.VotesNoWithVeto,
+	}
+	if p.Status == "VotingPeriod" {
+		tally, err := s.node.ProposalTallyResult(id)
+		if err != nil {
+			// the stored tally is refreshed by UpdateProposals, so it is a good enough fallback
+			log.Warn("GetProposal: node.ProposalTallyResult: %s", err.Error())
+		} else {
+			detail.NodeTally = nodeTally(tally)
+		}
+	}
+	return detail, nil
+}
+
+func proposalTimeline(p dmodels.Proposal) (items []smodels.ProposalTimelineItem) {
+	events := []smodels.ProposalTimelineItem{
+		{Event: "submit", Time: p.SubmitTime},
+		{Event: "deposit_end", Time: p.DepositEndTime},
+		{Event: "voting_start", Time: p.VotingStartTime},
+		{Event: "voting_end", Time: p.VotingEndTime},
+	}
+	for _, event := range events {
+		// voting dates are stored as unix zero until the proposal enters the voting period
+		if event.Time.Unix() <= 0 {
+			continue
+		}
+		items = append(items, event)
+	}
+	return items
+}
@@ -45,7 +45,7 @@ package services
 //				for i, r := range ranges {
 //					total, err := s.dao.GetDelegatorsTotal(filters.TimeRange{From: dmodels.NewTime(t.Add(-r))})
 //					if err != nil {
-//						return value, fmt.Errorf("dao.GetDelegatorsTotal: %s", err.Error())
+//						return value, fmt.Errorf("dao.GetDelegatorsTotal: %w", err)
 //					}
 //					value.setValue(i, fmt.Sprintf("%d", total))
 //				}
@@ -60,7 +60,7 @@ package services
 //				for i, r := range ranges {
 //					total, err := s.dao.GetMultiDelegatorsTotal(filters.TimeRange{From: dmodels.NewTime(t.Add(-r))})
 //					if err != nil {
-//						return value, fmt.Errorf("dao.GetMultiDelegatorsTotal: %s", err.Error())
+//						return value, fmt.Errorf("dao.GetMultiDelegatorsTotal: %w", err)
 //					}
 //					value.setValue(i, fmt.Sprintf("%d", total))
 //				}
@@ -75,7 +75,7 @@ package services
 //				for i, r := range ranges {
 //					total, err := s.dao.GetTransferVolume(filters.TimeRange{From: dmodels.NewTime(t.Add(-r))})
 //					if err != nil {
-//						return value, fmt.Errorf("dao.GetTransferVolume: %s", err.Error())
+//						return value, fmt.Errorf("dao.GetTransferVolume: %w", err)
 //					}
 //					value.setValue(i, total.String())
 //				}
@@ -90,7 +90,7 @@ package services
 //				for i, r := range ranges {
 //					total, err := s.dao.GetTransactionsFeeVolume(filters.TimeRange{From: dmodels.NewTime(t.Add(-r))})
 //					if err != nil {
-//						return value, fmt.Errorf("dao.GetTransactionsFeeVolume: %s", err.Error())
+//						return value, fmt.Errorf("dao.GetTransactionsFeeVolume: %w", err)
 //					}
 //					value.setValue(i, total.String())
 //				}
@@ -105,7 +105,7 @@ package services
 //				for i, r := range ranges {
 //					total, err := s.dao.GetTransactionsHighestFee(filters.TimeRange{From: dmodels.NewTime(t.Add(-r))})
 //					if err != nil {
-//						return value, fmt.Errorf("dao.GetTransactionsHighestFee: %s", err.Error())
+//						return value, fmt.Errorf("dao.GetTransactionsHighestFee: %w", err)
 //					}
 //					value.setValue(i, total.String())
 //				}
@@ -120,7 +120,7 @@ package services
 //				for i, r := range ranges {
 //					total, err := s.dao.GetUndelegationsVolume(filters.TimeRange{From: dmodels.NewTime(t.Add(-r))})
 //					if err != nil {
-//						return value, fmt.Errorf("dao.GetUndelegationsVolume: %s", err.Error())
+//						return value, fmt.Errorf("dao.GetUndelegationsVolume: %w", err)
 //					}
 //					value.setValue(i, total.String())
 //				}
@@ -135,7 +135,7 @@ package services
 //				for i, r := range ranges {
 //					total, err := s.dao.GetAvgBlocksDelay(filters.TimeRange{From: dmodels.NewTime(t.Add(-r))})
 //					if err != nil {
-//						return value, fmt.Errorf("dao.GetAvgBlocksDelay: %s", err.Error())
+//						return value, fmt.Errorf("dao.GetAvgBlocksDelay: %w", err)
 //					}
 //					value.setValue(i, fmt.Sprintf("%f", total))
 //				}
@@ -206,7 +206,7 @@ package services
 //		dmodels.RangeStateBlockDelay,
 //	})
 //	if err != nil {
-//		return nil, fmt.Errorf("dao.GetRangeStates: %s", err.Error())
+//		return nil, fmt.Errorf("dao.GetRangeStates: %w", err)
 //	}
 //	states = make(map[string]smodels.RangeState)
 //	for _, model := range models {
@@ -3,44 +3,59 @@ package services
 import (
 	"github.com/everstake/cosmoscan-api/config"
 	"github.com/everstake/cosmoscan-api/dao"
+	"github.com/everstake/cosmoscan-api/dao/cache"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/everstake/cosmoscan-api/dao/filters"
 	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/everstake/cosmoscan-api/services/cmc"
 	"github.com/everstake/cosmoscan-api/services/coingecko"
 	"github.com/everstake/cosmoscan-api/services/node"
 	"github.com/everstake/cosmoscan-api/smodels"
 	"github.com/shopspring/decimal"
+	"io"
+	"net/http"
+	"time"
 )
 
 type (
 	Services interface {
 		KeepHistoricalState()
 		UpdateValidatorsMap()
+		WarmUpCache()
 		GetValidatorMap() (map[string]node.Validator, error)
-		GetMetaData() (meta smodels.MetaData, err error)
-		GetAggTransactionsFee(filter filters.Agg) (items []smodels.AggItem, err error)
-		GetAggOperationsCount(filter filters.Agg) (items []smodels.AggItem, err error)
-		GetAggTransfersVolume(filter filters.Agg) (items []smodels.AggItem, err error)
-		GetHistoricalState() (state smodels.HistoricalState, err error)
-		GetAggBlocksCount(filter filters.Agg) (items []smodels.AggItem, err error)
-		GetAggBlocksDelay(filter filters.Agg) (items []smodels.AggItem, err error)
-		GetAggUniqBlockValidators(filter filters.Agg) (items []smodels.AggItem, err error)
+		GetMetaData(currency string) (meta smodels.MetaData, err error)
+		GetAggTransfersVolume(filter filters.TransfersAgg) (items []smodels.AggItem, err error)
+		GetTransfers(filter filters.Transfers) (resp smodels.PaginatableResponse, err error)
+		GetCounterparties(filter filters.Counterparties) (items []smodels.Counterparty, err error)
+		GetTransfersTrace(filter filters.Trace) (graph smodels.TraceGraph, err error)
+		GetHistoricalState(currency string) (state smodels.HistoricalState, err error)
 		GetAggDelegationsVolume(filter filters.DelegationsAgg) (items []smodels.AggItem, err error)
-		GetAggUndelegationsVolume(filter filters.Agg) (items []smodels.AggItem, err error)
 		GetNetworkStates(filter filters.Stats) (map[string][]decimal.Decimal, error)
 		GetStakingPie() (pie smodels.Pie, err error)
 		MakeUpdateBalances()
 		GetSizeOfNode() (size float64, err error)
 		MakeStats()
 		UpdateProposals()
+		MakeProposalTallySnapshots()
+		MakeSupplySnapshot()
+		UpdatePrices()
+		RepairLastDataGaps()
+		GetDataGaps(filter filters.TimeRange) (gaps []smodels.DataGap, err error)
+		RepairDataGaps(filter filters.TimeRange) (gaps []smodels.DataGap, err error)
+		GetSupply() (supply dmodels.SupplySnapshot, err error)
+		GetCirculatingSupply() (amount decimal.Decimal, err error)
+		GetAggSupply(filter filters.Agg) (snapshots []dmodels.SupplySnapshot, err error)
 		GetProposals(filter filters.Proposals) (proposals []dmodels.Proposal, err error)
+		GetProposal(id uint64) (detail smodels.ProposalDetail, err error)
 		GetProposalVotes(filter filters.ProposalVotes) (items []smodels.ProposalVote, err error)
+		GetAggProposalVotes(filter filters.ProposalVotesAgg) (items []smodels.AggItem, err error)
+		GetProposalTallySnapshots(filter filters.ProposalTallySnapshots) (snapshots []dmodels.ProposalTallySnapshot, err error)
 		GetProposalDeposits(filter filters.ProposalDeposits) (deposits []dmodels.ProposalDeposit, err error)
 		GetProposalsChartData() (items []smodels.ProposalChartData, err error)
 		GetAggValidators33Power(filter filters.Agg) (items []smodels.AggItem, err error)
 		GetValidators() (validators []smodels.Validator, err error)
 		UpdateValidators()
 		GetAvgOperationsPerBlock(filter filters.Agg) (items []smodels.AggItem, err error)
-		GetAggWhaleAccounts(filter filters.Agg) (items []smodels.AggItem, err error)
 		GetTopProposedBlocksValidators() (items []dmodels.ValidatorValue, err error)
 		GetMostJailedValidators() (items []dmodels.ValidatorValue, err error)
 		GetFeeRanges() (items []smodels.FeeRange, err error)
@@ -50,8 +65,24 @@ type (
 		GetValidatorDelegationsAgg(validatorAddress string) (items []smodels.AggItem, err error)
 		GetValidatorDelegatorsAgg(validatorAddress string) (items []smodels.AggItem, err error)
 		GetValidatorBlocksStat(validatorAddress string) (stat smodels.ValidatorBlocksStat, err error)
+		GetValidatorUptime(address string) (uptime smodels.ValidatorUptime, err error)
+		GetValidatorSigningMap(address string, blocks uint64) (items []smodels.SigningMapItem, err error)
+		GetValidatorsUptime() (items []smodels.ValidatorUptimeItem, err error)
+		GetAggValidatorPower(address string, filter filters.Agg) (items []smodels.AggItem, err error)
+		GetStakingParams() (params smodels.StakingParams, err error)
+		GetValidatorRewardsEstimate(address string, amount decimal.Decimal) (estimate smodels.RewardsEstimate, err error)
+		GetValidatorsAPR() (items []smodels.ValidatorAPR, err error)
+		MakeDecentralizationStats()
+		MakeAccountsDistributionStats()
+		GetDecentralization() (metrics smodels.Decentralization, err error)
+		GetAggDecentralization(filter filters.DecentralizationAgg) (items []smodels.AggItem, err error)
+		GetAddressLabels(filter filters.AddressLabels) (resp smodels.PaginatableResponse, err error)
+		GetEntities() (entities []smodels.Entity, err error)
+		SaveAddressLabel(params smodels.AddressLabelParams) (label dmodels.AddressLabel, err error)
+		DeleteAddressLabel(address string) error
+		ImportAddressLabels(format string, r io.Reader) (count uint64, err error)
+		ImportAddressLabelsFile(path string)
 		GetValidatorDelegators(filter filters.ValidatorDelegators) (resp smodels.PaginatableResponse, err error)
-		GetAggBondedRatio(filter filters.Agg) (items []smodels.AggItem, err error)
 		GetAggUnbondingVolume(filter filters.Agg) (items []smodels.AggItem, err error)
 		Test() (state dmodels.HistoricalState, err error)
 		GetBlock(height uint64) (block smodels.Block, err error)
@@ -59,9 +90,37 @@ type (
 		GetTransaction(hash string) (tx smodels.Tx, err error)
 		GetTransactions(filter filters.Transactions) (resp smodels.PaginatableResponse, err error)
 		GetAccount(address string) (account smodels.Account, err error)
+		GetAccountBalanceHistory(address string, currency string, filter filters.Agg) (items []smodels.BalanceHistoryItem, err error)
+		GetAccountDelegations(address string) (resp smodels.AccountDelegations, err error)
+		GetAccountVesting(address string, at time.Time) (vesting smodels.AccountVesting, err error)
+		GetVestingSchedule(filter filters.Agg) (items []smodels.AggItem, err error)
+		GetQuoteCurrencies() []string
+		GetAggPrices(filter filters.PricesAgg) (items []smodels.AggItem, err error)
+		GetMetrics() (metrics []smodels.MetricInfo)
+		GetMetric(filter filters.Metric) (series smodels.MetricSeries, err error)
+		GetRichList(filter filters.Accounts) (resp smodels.PaginatableResponse, err error)
+		GetAccountsDistribution(filter filters.AccountsDistribution) (buckets []smodels.DistributionBucket, err error)
+		GetAggAccountsDistribution(filter filters.AccountsDistributionAgg) (items []smodels.AggItem, err error)
+		ExportAccountEvents(filter filters.AccountEvents, fn func(event dmodels.AccountEvent) error) error
+		CreateAPIKey(params smodels.APIKeyParams) (key smodels.IssuedAPIKey, err error)
+		UpdateAPIKey(id uint64, params smodels.APIKeyParams) (key dmodels.APIKey, err error)
+		GetAPIKeys() (keys []dmodels.APIKey, err error)
+		GetAPIKeyUsages(filter filters.APIKeyUsages) (usages []dmodels.APIKeyUsage, err error)
+		GetAPIKeyByToken(token string) (key dmodels.APIKey, found bool, err error)
+		UseAPIKeyQuota(key dmodels.APIKey, cost uint64) (ok bool, err error)
+		FlushAPIKeysUsage()
+		CreateWebhook(apiKeyID uint64, params smodels.WebhookParams) (webhook smodels.CreatedWebhook, err error)
+		UpdateWebhook(apiKeyID uint64, id uint64, params smodels.WebhookParams) (webhook dmodels.Webhook, err error)
+		GetWebhooks(apiKeyID uint64) (webhooks []dmodels.Webhook, err error)
+		GetWebhook(apiKeyID uint64, id uint64) (webhook dmodels.Webhook, err error)
+		DeleteWebhook(apiKeyID uint64, id uint64) error
+		GetWebhookDeliveries(apiKeyID uint64, filter filters.WebhookDeliveries) (deliveries []dmodels.WebhookDelivery, err error)
+		DeliverWebhooks()
+		CleanWebhookDeliveries()
 	}
 	CryptoMarket interface {
-		GetMarketData() (price, volume24h decimal.Decimal, err error)
+		GetMarketData(currencies []string) (prices []dmodels.Price, err error)
+		GetPriceHistory(currency string, from, to time.Time) (prices []dmodels.Price, err error)
 	}
 	Node interface {
 		GetCommunityPoolAmount() (amount decimal.Decimal, err error)
@@ -72,7 +131,14 @@ type (
 		GetBalance(address string) (amount decimal.Decimal, err error)
 		GetStake(address string) (amount decimal.Decimal, err error)
 		GetUnbonding(address string) (amount decimal.Decimal, err error)
+		GetDelegations(address string) (result node.StakeResult, err error)
+		GetDelegatorRewards(address string) (result node.DelegatorRewards, err error)
+		GetUnbondingDelegations(address string) (result node.UnbondingResult, err error)
 		GetProposals() (proposals node.ProposalsResult, err error)
+		GetTallyParams() (params node.TallyParams, err error)
+		GetSlashingParams() (params node.SlashingParams, err error)
+		GetDistributionParams() (params node.DistributionParams, err error)
+		GetParam(subspace string, key string) (value string, err error)
 		GetDelegatorValidatorStake(delegator string, validator string) (amount decimal.Decimal, err error)
 		ProposalTallyResult(id uint64) (result node.ProposalTallyResult, err error)
 		GetBlock(id uint64) (result node.Block, err error)
@@ -82,18 +148,57 @@ type (
 	}
 
 	ServiceFacade struct {
-		dao  dao.DAO
-		cfg  config.Config
-		cm   CryptoMarket
-		node Node
+		dao          dao.DAO
+		cfg          config.Config
+		cm           CryptoMarket
+		node         Node
+		apiKeysUsage *apiKeysUsage
+		// webhookClient sends the webhook deliveries
+		webhookClient *http.Client
 	}
 )
 
+// cachedTypes are the types of the values the services keep in the cache, the redis cache has to know them
+var cachedTypes = []interface{}{
+	map[string]node.Validator{},
+	[]smodels.Validator{},
+	[]dmodels.ValidatorValue{},
+	dmodels.APIKey{},
+	[]smodels.DistributionBucket{},
+	dmodels.SupplySnapshot{},
+	[]dmodels.VestingAccount{},
+	map[string]dmodels.AddressLabel{},
+	[]smodels.ValidatorUptimeItem{},
+	node.SlashingParams{},
+	smodels.StakingParams{},
+	[]smodels.ValidatorAPR{},
+	smodels.Decentralization{},
+}
+
 func NewServices(d dao.DAO, cfg config.Config) (svc Services, err error) {
+	cache.Register(cachedTypes...)
+	markets := cryptoMarkets{coingecko.NewGecko()}
+	if cfg.CMCKey != "" {
+		markets = append(markets, cmc.NewCMC(cfg))
+	}
 	return &ServiceFacade{
-		dao:  d,
-		cfg:  cfg,
-		cm:   coingecko.NewGecko(),
-		node: node.NewAPI(cfg),
+		dao:           d,
+		cfg:           cfg,
+		cm:            markets,
+		node:          node.NewAPI(cfg),
+		apiKeysUsage:  newAPIKeysUsage(),
+		webhookClient: newWebhookClient(cfg.Webhooks),
 	}, nil
 }
+
+// nodeLookupError types the not found and invalid argument responses of the node to the lookup of the requested
+// object, other calls treat such responses as failures of the node
+func nodeLookupError(err error, object string) error {
+	switch {
+	case node.IsNotFound(err):
+		return derrors.Wrap(derrors.CodeNotFound, err, object+" not found")
+	case node.IsInvalidArgument(err):
+		return derrors.Wrap(derrors.CodeInvalidArgument, err, "invalid "+object)
+	}
+	return err
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"fmt"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/everstake/cosmoscan-api/services/node"
+	"github.com/everstake/cosmoscan-api/smodels"
+	"github.com/shopspring/decimal"
+	"sort"
+	"time"
+)
+
+const (
+	stakingParamsCacheKey = "staking_params"
+	validatorsAPRCacheKey = "validators_apr"
+
+	// withdrawnAPRWindowDays is the trailing window of the rewards withdrawn for the withdrawn APR
+	withdrawnAPRWindowDays = 30
+)
+
+var hundred = decimal.New(100, 0)
+
+// GetStakingParams returns the network staking APR before the validator commission:
+// inflation * (1 - community tax) / bonded ratio
+func (s *ServiceFacade) GetStakingParams() (params smodels.StakingParams, err error) {
+	data, err := s.dao.CacheLoad(stakingParamsCacheKey, time.Minute*10, func() (interface{}, error) {
+		return s.makeStakingParams()
+	})
+	if err != nil {
+		return params, fmt.Errorf("makeStakingParams: %w", err)
+	}
+	return data.(smodels.StakingParams), nil
+}
+
+func (s *ServiceFacade) makeStakingParams() (params smodels.StakingParams, err error) {
+	params.Inflation, err = s.node.GetInflation()
+	if err != nil {
+		return params, fmt.Errorf("node.GetInflation: %w", err)
+	}
+	stakingPool, err := s.node.GetStakingPool()
+	if err != nil {
+		return params, fmt.Errorf("node.GetStakingPool: %w", err)
+	}
+	supply, err := s.node.GetTotalSupply()
+	if err != nil {
+		return params, fmt.Errorf("node.GetTotalSupply: %w", err)
+	}
+	distributionParams, err := s.node.GetDistributionParams()
+	if err != nil {
+		return params, fmt.Errorf("node.GetDistributionParams: %w", err)
+	}
+	if supply.IsZero() || stakingPool.Pool.BondedTokens.IsZero() {
+		return params, fmt.Errorf("bonded tokens or supply is zero")
+	}
+	params.CommunityTax = distributionParams.CommunityTax
+	params.BondedRatio = stakingPool.Pool.BondedTokens.Div(supply)
+	params.NetworkAPR = params.Inflation.Mul(decimal.New(1, 0).Sub(params.CommunityTax)).Div(params.BondedRatio).Truncate(2)
+	params.BondedRatio = params.BondedRatio.Mul(hundred).Truncate(2)
+	params.Inflation = params.Inflation.Truncate(2)
+	return params, nil
+}
+
+// GetValidatorRewardsEstimate projects the rewards of the amount delegated to the validator
+func (s *ServiceFacade) GetValidatorRewardsEstimate(address string, amount decimal.Decimal) (estimate smodels.RewardsEstimate, err error) {
+	if amount.IsNegative() {
+		return estimate, derrors.InvalidArgument("amount can not be negative")
+	}
+	estimate.StakingParams, err = s.GetStakingParams()
+	if err != nil {
+		return estimate, fmt.Errorf("GetStakingParams: %w", err)
+	}
+	validator, err := s.GetValidator(address)
+	if err != nil {
+		return estimate, fmt.Errorf("GetValidator: %w", err)
+	}
+	uptime := decimal.Zero
+	validatorsMap, err := s.GetValidatorMap()
+	if err != nil {
+		return estimate, fmt.Errorf("GetValidatorMap: %w", err)
+	}
+	if validatorsMap[address].Status == node.BondedValidatorStatus {
+		validatorUptime, err := s.GetValidatorUptime(address)
+		if err != nil {
+			return estimate, fmt.Errorf("GetValidatorUptime: %w", err)
+		}
+		uptime = validatorUptime.Uptime
+	}
+	withdrawn, err := s.getWithdrawnAPR([]string{address})
+	if err != nil {
+		return estimate, fmt.Errorf("getWithdrawnAPR: %w", err)
+	}
+	estimate.ValidatorAPR = smodels.ValidatorAPR{
+		Title:            validator.Title,
+		OperatorAddress:  validator.OperatorAddress,
+		Commission:       validator.Fee,
+		Uptime:           uptime,
+		ProjectedAPR:     projectedAPR(estimate.NetworkAPR, validator.Fee, uptime),
+		WithdrawnAPR:      withdrawn[address].WithdrawnAPR,
+		WithdrawnGrossAPR: withdrawn[address].WithdrawnGrossAPR,
+	}
+	estimate.WithdrawnWindowDays = withdrawnAPRWindowDays
+	estimate.Amount = amount
+	estimate.YearlyReward = amount.Mul(estimate.ProjectedAPR).Div(hundred)
+	estimate.MonthlyReward = estimate.YearlyReward.Div(decimal.New(12, 0)).Truncate(6)
+	estimate.DailyReward = estimate.YearlyReward.Div(decimal.New(365, 0)).Truncate(6)
+	estimate.YearlyReward = estimate.YearlyReward.Truncate(6)
+	return estimate, nil
+}
+
+// GetValidatorsAPR returns the projected and withdrawn APR of the validators sorted by the projected one
+func (s *ServiceFacade) GetValidatorsAPR() (items []smodels.ValidatorAPR, err error) {
+	data, err := s.dao.CacheLoad(validatorsAPRCacheKey, time.Hour, func() (interface{}, error) {
+		return s.makeValidatorsAPR()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("makeValidatorsAPR: %w", err)
+	}
+	return data.([]smodels.ValidatorAPR), nil
+}
+
+func (s *ServiceFacade) makeValidatorsAPR() (items []smodels.ValidatorAPR, err error) {
+	params, err := s.GetStakingParams()
+	if err != nil {
+		return nil, fmt.Errorf("GetStakingParams: %w", err)
+	}
+	validators, err := s.GetValidators()
+	if err != nil {
+		return nil, fmt.Errorf("GetValidators: %w", err)
+	}
+	uptimes, err := s.GetValidatorsUptime()
+	if err != nil {
+		return nil, fmt.Errorf("GetValidatorsUptime: %w", err)
+	}
+	uptimeMap := make(map[string]decimal.Decimal)
+	for _, u := range uptimes {
+		uptimeMap[u.OperatorAddress] = u.Uptime
+	}
+	withdrawn, err := s.getWithdrawnAPR(nil)
+	if err != nil {
+		return nil, fmt.Errorf("getWithdrawnAPR: %w", err)
+	}
+	for _, v := range validators {
+		uptime := uptimeMap[v.OperatorAddress]
+		items = append(items, smodels.ValidatorAPR{
+			Title:            v.Title,
+			OperatorAddress:  v.OperatorAddress,
+			Commission:       v.Fee,
+			Uptime:           uptime,
+			ProjectedAPR:     projectedAPR(params.NetworkAPR, v.Fee, uptime),
+			WithdrawnAPR:      withdrawn[v.OperatorAddress].WithdrawnAPR,
+			WithdrawnGrossAPR: withdrawn[v.OperatorAddress].WithdrawnGrossAPR,
+		})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].ProjectedAPR.GreaterThan(items[j].ProjectedAPR)
+	})
+	return items, nil
+}
+
+// getWithdrawnAPR annualizes the rewards withdrawn over the trailing window against the average stake of the validator,
+// the gross APR includes the withdrawn commission. The rewards left pending are not counted,
+// so it is below the actual yield when the delegators do not withdraw regularly
+func (s *ServiceFacade) getWithdrawnAPR(validators []string) (map[string]smodels.ValidatorAPR, error) {
+	now := time.Now()
+	from := now.AddDate(0, 0, -withdrawnAPRWindowDays)
+	rewardsFilter := filters.ValidatorRewards{
+		TimeRange:  filters.TimeRange{From: dmodels.NewTime(from), To: dmodels.NewTime(now)},
+		Validators: validators,
+	}
+	rewards, err := s.dao.GetValidatorsDelegatorRewards(rewardsFilter)
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetValidatorsDelegatorRewards: %w", err)
+	}
+	commissions, err := s.dao.GetValidatorsCommissions(rewardsFilter)
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetValidatorsCommissions: %w", err)
+	}
+	stakes := make(map[string]decimal.Decimal)
+	for _, to := range []time.Time{from, now} {
+		items, err := s.dao.GetValidatorsStake(filters.DelegationStakes{Validators: validators, To: dmodels.NewTime(to)})
+		if err != nil {
+			return nil, fmt.Errorf("dao.GetValidatorsStake: %w", err)
+		}
+		for _, item := range items {
+			stakes[item.Validator] = stakes[item.Validator].Add(item.Amount.Div(decimal.New(2, 0)))
+		}
+	}
+	annualize := func(amount decimal.Decimal, stake decimal.Decimal) decimal.Decimal {
+		if !stake.IsPositive() {
+			return decimal.Zero
+		}
+		return amount.Div(stake).Mul(decimal.New(365, 0)).Div(decimal.New(withdrawnAPRWindowDays, 0)).Mul(hundred).Truncate(2)
+	}
+	commissionsMap := make(map[string]decimal.Decimal)
+	for _, item := range commissions {
+		commissionsMap[item.Validator] = item.Amount
+	}
+	result := make(map[string]smodels.ValidatorAPR)
+	for _, item := range rewards {
+		stake := stakes[item.Validator]
+		result[item.Validator] = smodels.ValidatorAPR{
+			WithdrawnAPR:      annualize(item.Amount, stake),
+			WithdrawnGrossAPR: annualize(item.Amount.Add(commissionsMap[item.Validator]), stake),
+		}
+	}
+	return result, nil
+}
+
+// projectedAPR applies the validator commission (a fraction) and uptime (in percents) to the network APR
+func projectedAPR(networkAPR decimal.Decimal, commission decimal.Decimal, uptime decimal.Decimal) decimal.Decimal {
+	return networkAPR.Mul(decimal.New(1, 0).Sub(commission)).Mul(uptime).Div(hundred).Truncate(2)
+}
@@ -8,10 +8,8 @@ import (
 	"github.com/everstake/cosmoscan-api/dmodels"
 	"github.com/everstake/cosmoscan-api/log"
 	"github.com/everstake/cosmoscan-api/services/node"
-	"github.com/everstake/cosmoscan-api/smodels"
 	"github.com/shopspring/decimal"
 	"math"
-	"sort"
 	"time"
 )
 
@@ -42,7 +40,7 @@ func (s *ServiceFacade) getStates(filter filters.Stats) (map[string][]decimal.De
 	filter.From = dmodels.NewTime(filter.To.Add(-time.Hour * 24 * 7))
 	stats, err := s.dao.GetStats(filter)
 	if err != nil {
-		return nil, fmt.Errorf("dao.GetStats: %s", err.Error())
+		return nil, fmt.Errorf("dao.GetStats: %w", err)
 	}
 	mp := make(map[string][]decimal.Decimal)
 	for _, stat := range stats {
@@ -51,119 +49,159 @@ func (s *ServiceFacade) getStates(filter filters.Stats) (map[string][]decimal.De
 	return mp, nil
 }
 
+// dayStat is a stat saved at the end of the day, derivable stats can be recomputed for a past day
+// from the stored data while the rest take the current values of the network
+type dayStat struct {
+	title     string
+	derivable bool
+	fetch     func(from, to time.Time) (decimal.Decimal, error)
+}
+
 func (s *ServiceFacade) MakeStats() {
-	now := time.Now()
-	y, m, d := now.Add(-time.Hour * 24).Date()
-	startOfYesterday := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
-	startOfToday := startOfYesterday.Add(time.Hour * 24)
-	stats := []struct {
-		title string
-		fetch func() (decimal.Decimal, error)
-	}{
+	y, m, d := time.Now().Add(-time.Hour * 24).Date()
+	startOfToday := time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Add(time.Hour * 24)
+	models := s.makeDayStats(s.dayStats(), startOfToday)
+	err := s.dao.CreateStats(models)
+	if err != nil {
+		log.Error("MakeStats: dao.CreateStats: %s", err.Error())
+	}
+}
+
+// makeDayStats computes the stats of the day ending at the end time
+func (s *ServiceFacade) makeDayStats(stats []dayStat, end time.Time) (models []dmodels.Stat) {
+	for _, stat := range stats {
+		value, err := stat.fetch(end.Add(-time.Hour*24), end)
+		if err != nil {
+			log.Error("makeDayStats (%s): %s", stat.title, err.Error())
+			continue
+		}
+		hash := sha1.Sum([]byte(fmt.Sprintf("%s.%s", stat.title, end.String())))
+		id := hex.EncodeToString(hash[:])
+		models = append(models, dmodels.Stat{
+			ID:        id,
+			Title:     stat.title,
+			Value:     value,
+			CreatedAt: end,
+		})
+	}
+	return models
+}
+
+func (s *ServiceFacade) dayStats() []dayStat {
+	return []dayStat{
 		{
 			title: dmodels.StatsTotalStakingBalance,
-			fetch: func() (value decimal.Decimal, err error) {
+			fetch: func(from, to time.Time) (value decimal.Decimal, err error) {
 				stakingPool, err := s.node.GetStakingPool()
 				if err != nil {
-					return value, fmt.Errorf("node.GetStakingPool: %s", err.Error())
+					return value, fmt.Errorf("node.GetStakingPool: %w", err)
 				}
 				return stakingPool.Pool.BondedTokens, nil
 			},
 		},
 		{
-			title: dmodels.StatsTotalDelegators,
-			fetch: func() (value decimal.Decimal, err error) {
+			title:     dmodels.StatsTotalDelegators,
+			derivable: true,
+			fetch: func(from, to time.Time) (value decimal.Decimal, err error) {
 				total, err := s.dao.GetDelegatorsTotal(filters.Delegators{
+					TimeRange: filters.TimeRange{To: dmodels.NewTime(to)},
 				})
 				if err != nil {
-					return value, fmt.Errorf("dao.GetDelegatorsTotal: %s", err.Error())
+					return value, fmt.Errorf("dao.GetDelegatorsTotal: %w", err)
 				}
 				return decimal.NewFromInt(int64(total)), nil
 			},
 		},
 		{
-			title: dmodels.StatsNumberDelegators,
-			fetch: func() (value decimal.Decimal, err error) {
+			title:     dmodels.StatsNumberDelegators,
+			derivable: true,
+			fetch: func(from, to time.Time) (value decimal.Decimal, err error) {
 				total, err := s.dao.GetDelegatorsTotal(filters.Delegators{
 					TimeRange: filters.TimeRange{
-						From: dmodels.NewTime(startOfYesterday),
-						To:   dmodels.NewTime(startOfToday),
+						From: dmodels.NewTime(from),
+						To:   dmodels.NewTime(to),
 					},
 				})
 				if err != nil {
-					return value, fmt.Errorf("dao.GetDelegatorsTotal: %s", err.Error())
+					return value, fmt.Errorf("dao.GetDelegatorsTotal: %w", err)
 				}
 				return decimal.NewFromInt(int64(total)), nil
 			},
 		},
 		{
-			title: dmodels.StatsNumberMultiDelegators,
-			fetch: func() (value decimal.Decimal, err error) {
-				total, err := s.dao.GetMultiDelegatorsTotal(filters.TimeRange{})
+			title:     dmodels.StatsNumberMultiDelegators,
+			derivable: true,
+			fetch: func(from, to time.Time) (value decimal.Decimal, err error) {
+				total, err := s.dao.GetMultiDelegatorsTotal(filters.TimeRange{To: dmodels.NewTime(to)})
 				if err != nil {
-					return value, fmt.Errorf("dao.GetMultiDelegatorsTotal: %s", err.Error())
+					return value, fmt.Errorf("dao.GetMultiDelegatorsTotal: %w", err)
 				}
 				return decimal.NewFromInt(int64(total)), nil
 			},
 		},
 		{
-			title: dmodels.StatsTransfersVolume,
-			fetch: func() (value decimal.Decimal, err error) {
-				volume, err := s.dao.GetTransferVolume(filters.TimeRange{})
+			title:     dmodels.StatsTransfersVolume,
+			derivable: true,
+			fetch: func(from, to time.Time) (value decimal.Decimal, err error) {
+				volume, err := s.dao.GetTransferVolume(filters.TimeRange{To: dmodels.NewTime(to)})
 				if err != nil {
-					return value, fmt.Errorf("dao.GetTransferVolume: %s", err.Error())
+					return value, fmt.Errorf("dao.GetTransferVolume: %w", err)
 				}
 				return volume, nil
 			},
 		},
 		{
-			title: dmodels.StatsFeeVolume,
-			fetch: func() (value decimal.Decimal, err error) {
+			title:     dmodels.StatsFeeVolume,
+			derivable: true,
+			fetch: func(from, to time.Time) (value decimal.Decimal, err error) {
 				volume, err := s.dao.GetTransactionsFeeVolume(filters.TimeRange{
-					From: dmodels.NewTime(startOfYesterday),
-					To:   dmodels.NewTime(startOfToday),
+					From: dmodels.NewTime(from),
+					To:   dmodels.NewTime(to),
 				})
 				if err != nil {
-					return value, fmt.Errorf("dao.GetTransactionsFeeVolume: %s", err.Error())
+					return value, fmt.Errorf("dao.GetTransactionsFeeVolume: %w", err)
 				}
 				return volume, nil
 			},
 		},
 		{
-			title: dmodels.StatsHighestFee,
-			fetch: func() (value decimal.Decimal, err error) {
+			title:     dmodels.StatsHighestFee,
+			derivable: true,
+			fetch: func(from, to time.Time) (value decimal.Decimal, err error) {
 				volume, err := s.dao.GetTransactionsHighestFee(filters.TimeRange{
-					From: dmodels.NewTime(startOfYesterday),
-					To:   dmodels.NewTime(startOfToday),
+					From: dmodels.NewTime(from),
+					To:   dmodels.NewTime(to),
 				})
 				if err != nil {
-					return value, fmt.Errorf("dao.GetTransactionsHighestFee: %s", err.Error())
+					return value, fmt.Errorf("dao.GetTransactionsHighestFee: %w", err)
 				}
 				return volume, nil
 			},
 		},
 		{
-			title: dmodels.StatsUndelegationVolume,
-			fetch: func() (value decimal.Decimal, err error) {
+			title:     dmodels.StatsUndelegationVolume,
+			derivable: true,
+			fetch: func(from, to time.Time) (value decimal.Decimal, err error) {
 				volume, err := s.dao.GetUndelegationsVolume(filters.TimeRange{
-					From: dmodels.NewTime(startOfYesterday),
-					To:   dmodels.NewTime(startOfToday),
+					From: dmodels.NewTime(from),
+					To:   dmodels.NewTime(to),
 				})
 				if err != nil {
-					return value, fmt.Errorf("dao.GetUndelegationsVolume: %s", err.Error())
+					return value, fmt.Errorf("dao.GetUndelegationsVolume: %w", err)
 				}
 				return volume, nil
 			},
 		},
 		{
-			title: dmodels.StatsBlockDelay,
-			fetch: func() (value decimal.Decimal, err error) {
+			title:     dmodels.StatsBlockDelay,
+			derivable: true,
+			fetch: func(from, to time.Time) (value decimal.Decimal, err error) {
 				delay, err := s.dao.GetAvgBlocksDelay(filters.TimeRange{
-					From: dmodels.NewTime(startOfYesterday),
-					To:   dmodels.NewTime(startOfToday),
+					From: dmodels.NewTime(from),
+					To:   dmodels.NewTime(to),
 				})
 				if err != nil {
-					return value, fmt.Errorf("dao.GetAvgBlocksDelay: %s", err.Error())
+					return value, fmt.Errorf("dao.GetAvgBlocksDelay: %w", err)
 				}
 				if math.IsNaN(delay) {
 					return decimal.Zero, nil
@@ -173,135 +211,75 @@ func (s *ServiceFacade) MakeStats() {
 		},
 		{
 			title: dmodels.StatsNetworkSize,
-			fetch: func() (value decimal.Decimal, err error) {
+			fetch: func(from, to time.Time) (value decimal.Decimal, err error) {
 				size, err := s.GetSizeOfNode()
 				if err != nil {
-					return value, fmt.Errorf("GetSizeOfNode: %s", err.Error())
+					return value, fmt.Errorf("GetSizeOfNode: %w", err)
 				}
 				return decimal.NewFromFloat(size), nil
 			},
 		},
 		{
-			title: dmodels.StatsTotalAccounts,
-			fetch: func() (value decimal.Decimal, err error) {
-				total, err := s.dao.GetAccountsTotal(filters.Accounts{})
+			title:     dmodels.StatsTotalAccounts,
+			derivable: true,
+			fetch: func(from, to time.Time) (value decimal.Decimal, err error) {
+				total, err := s.dao.GetAccountsTotal(filters.Accounts{CreatedBefore: to})
 				if err != nil {
-					return value, fmt.Errorf("dao.GetAccountsTotal: %s", err.Error())
+					return value, fmt.Errorf("dao.GetAccountsTotal: %w", err)
 				}
 				return decimal.NewFromInt(int64(total)), nil
 			},
 		},
 		{
 			title: dmodels.StatsTotalWhaleAccounts,
-			fetch: func() (value decimal.Decimal, err error) {
+			fetch: func(from, to time.Time) (value decimal.Decimal, err error) {
 				minAmount := decimal.NewFromFloat(300000)
-				total, err := s.dao.GetAccountsTotal(filters.Accounts{GtTotalAmount: minAmount})
+				total, err := s.getUnlockedAccountsTotal(filters.Accounts{GtTotalAmount: minAmount})
 				if err != nil {
-					return value, fmt.Errorf("dao.GetAccountsTotal: %s", err.Error())
+					return value, fmt.Errorf("getUnlockedAccountsTotal: %w", err)
 				}
 				return decimal.NewFromInt(int64(total)), nil
 			},
 		},
 		{
 			title: dmodels.StatsTotalSmallAccounts,
-			fetch: func() (value decimal.Decimal, err error) {
+			fetch: func(from, to time.Time) (value decimal.Decimal, err error) {
 				maxAmount := decimal.NewFromFloat(1)
-				total, err := s.dao.GetAccountsTotal(filters.Accounts{LtTotalAmount: maxAmount})
+				total, err := s.getUnlockedAccountsTotal(filters.Accounts{LtTotalAmount: maxAmount})
 				if err != nil {
-					return value, fmt.Errorf("dao.GetAccountsTotal: %s", err.Error())
+					return value, fmt.Errorf("getUnlockedAccountsTotal: %w", err)
 				}
 				return decimal.NewFromInt(int64(total)), nil
 			},
 		},
 		{
 			title: dmodels.StatsTotalJailers,
-			fetch: func() (value decimal.Decimal, err error) {
+			fetch: func(from, to time.Time) (value decimal.Decimal, err error) {
 				total, err := s.dao.GetJailersTotal()
 				if err != nil {
-					return value, fmt.Errorf("dao.GetJailersTotal: %s", err.Error())
+					return value, fmt.Errorf("dao.GetJailersTotal: %w", err)
 				}
 				return decimal.NewFromInt(int64(total)), nil
 			},
 		},
 		{
 			title: dmodels.StatsValidatorsWith33Power,
-			fetch: func() (value decimal.Decimal, err error) {
-				sp, err := s.node.GetStakingPool()
-				if err != nil {
-					return value, fmt.Errorf("node.GetStakingPool: %s", err.Error())
-				}
+			fetch: func(from, to time.Time) (value decimal.Decimal, err error) {
 				mp, err := s.GetValidatorMap()
 				if err != nil {
-					return value, fmt.Errorf("s.GetValidatorMap: %s", err.Error())
+					return value, fmt.Errorf("s.GetValidatorMap: %w", err)
 				}
 				var amounts []decimal.Decimal
 				for _, validator := range mp {
-					amounts = append(amounts, validator.DelegatorShares.Div(node.PrecisionDiv))
+					if validator.Status == node.BondedValidatorStatus {
+						amounts = append(amounts, validator.DelegatorShares.Div(node.PrecisionDiv))
+					}
 				}
-				sort.Slice(amounts, func(i, j int) bool {
-					return amounts[i].GreaterThan(amounts[j])
-				})
-				if sp.Pool.BondedTokens.IsZero() {
+				if len(amounts) == 0 {
 					return value, fmt.Errorf("total stake is zero")
 				}
-				stake := sp.Pool.BondedTokens
-				sum := decimal.Zero
-				limit := decimal.NewFromFloat(33.4)
-				for _, amount := range amounts {
-					sum = sum.Add(amount)
-					value = value.Add(decimal.NewFromInt(1))
-					power := sum.Div(stake).Mul(decimal.NewFromInt(100))
-					if power.GreaterThan(limit) {
-						return value, nil
-					}
-				}
-				return value, nil
+				return decimal.NewFromInt(int64(nakamotoCoefficient(amounts, nakamotoLimit))), nil
 			},
 		},
 	}
-
-	var models []dmodels.Stat
-	for _, stat := range stats {
-		value, err := stat.fetch()
-		if err != nil {
-			log.Error("MakeStats (%s): %s", stat.title, err.Error())
-			continue
-		}
-		hash := sha1.Sum([]byte(fmt.Sprintf("%s.%s", stat.title, startOfToday.String())))
-		id := hex.EncodeToString(hash[:])
-		models = append(models, dmodels.Stat{
-			ID:        id,
-			Title:     stat.title,
-			Value:     value,
-			CreatedAt: startOfToday,
-		})
-	}
-	err := s.dao.CreateStats(models)
-	if err != nil {
-		log.Error("MakeStats: dao.CreateStats: %s", err.Error())
-	}
-}
-
-func (s *ServiceFacade) GetAggValidators33Power(filter filters.Agg) (items []smodels.AggItem, err error) {
-	items, err = s.dao.GetAggValidators33Power(filter)
-	if err != nil {
-		return nil, fmt.Errorf("dao.GetAggValidators33Power: %s", err.Error())
-	}
-	return items, nil
-}
-
-func (s *ServiceFacade) GetAggWhaleAccounts(filter filters.Agg) (items []smodels.AggItem, err error) {
-	items, err = s.dao.GetAggWhaleAccounts(filter)
-	if err != nil {
-		return nil, fmt.Errorf("dao.GetAggWhaleAccounts: %s", err.Error())
-	}
-	return items, nil
-}
-
-func (s *ServiceFacade) GetAggBondedRatio(filter filters.Agg) (items []smodels.AggItem, err error) {
-	items, err = s.dao.GetAggHistoricalStatesByField(filter, "his_staked_ratio")
-	if err != nil {
-		return nil, fmt.Errorf("dao.GetAggHistoricalStatesByField: %s", err.Error())
-	}
-	return items, nil
 }
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/everstake/cosmoscan-api/log"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+const (
+	supplyCacheKey = "supply"
+
+	distributionModule = "distribution"
+)
+
+// supplyModules are the module accounts holding tokens out of circulation, besides the staking pools
+// which are counted as bonded and unbonding
+var supplyModules = []string{distributionModule, "fee_collector", "mint", "gov"}
+
+// MakeSupplySnapshot saves the supply breakdown, one snapshot per hour
+func (s *ServiceFacade) MakeSupplySnapshot() {
+	hour := time.Now().UTC().Truncate(time.Hour)
+	supply, err := s.makeSupply()
+	if err != nil {
+		log.Error("MakeSupplySnapshot: makeSupply: %s", err.Error())
+		return
+	}
+	hash := sha1.Sum([]byte(fmt.Sprintf("supply.%s", hour.String())))
+	supply.ID = hex.EncodeToString(hash[:])
+	supply.CreatedAt = dmodels.NewTime(hour)
+	err = s.dao.CreateSupplySnapshots([]dmodels.SupplySnapshot{supply})
+	if err != nil {
+		log.Error("MakeSupplySnapshot: dao.CreateSupplySnapshots: %s", err.Error())
+	}
+}
+
+func (s *ServiceFacade) GetSupply() (supply dmodels.SupplySnapshot, err error) {
+	data, err := s.dao.CacheLoad(supplyCacheKey, time.Minute, func() (interface{}, error) {
+		return s.makeSupply()
+	})
+	if err != nil {
+		return supply, fmt.Errorf("makeSupply: %w", err)
+	}
+	return data.(dmodels.SupplySnapshot), nil
+}
+
+func (s *ServiceFacade) GetCirculatingSupply() (amount decimal.Decimal, err error) {
+	supply, err := s.GetSupply()
+	if err != nil {
+		return amount, fmt.Errorf("GetSupply: %w", err)
+	}
+	return supply.Circulating, nil
+}
+
+func (s *ServiceFacade) GetAggSupply(filter filters.Agg) (snapshots []dmodels.SupplySnapshot, err error) {
+	snapshots, err = s.dao.GetAggSupplySnapshots(filter)
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetAggSupplySnapshots: %w", err)
+	}
+	return snapshots, nil
+}
+
+// makeSupply splits the total supply into the staking pools, the community pool, the rest of the module accounts,
+// the locked vesting and the liquid tokens. The delegated part of the locked vesting is in the bonded pool,
+// so only the rest of it is taken from the liquid tokens.
+// The circulating supply is the total without the community pool and the locked vesting.
+func (s *ServiceFacade) makeSupply() (supply dmodels.SupplySnapshot, err error) {
+	supply.Total, err = s.node.GetTotalSupply()
+	if err != nil {
+		return supply, fmt.Errorf("node.GetTotalSupply: %w", err)
+	}
+	stakingPool, err := s.node.GetStakingPool()
+	if err != nil {
+		return supply, fmt.Errorf("node.GetStakingPool: %w", err)
+	}
+	supply.Bonded = stakingPool.Pool.BondedTokens
+	supply.Unbonding = stakingPool.Pool.NotBondedTokens
+	supply.CommunityPool, err = s.node.GetCommunityPoolAmount()
+	if err != nil {
+		return supply, fmt.Errorf("node.GetCommunityPoolAmount: %w", err)
+	}
+	for _, module := range supplyModules {
+		balance, err := s.node.GetBalance(moduleAddress(module))
+		if err != nil {
+			return supply, fmt.Errorf("node.GetBalance(%s): %w", module, err)
+		}
+		// the community pool is held by the distribution module along with the outstanding rewards
+		if module == distributionModule {
+			balance = nonNegative(balance.Sub(supply.CommunityPool))
+		}
+		supply.ModuleAccounts = supply.ModuleAccounts.Add(balance)
+	}
+	supply.VestingLocked, err = s.getVestingLocked(time.Now())
+	if err != nil {
+		return supply, fmt.Errorf("getVestingLocked: %w", err)
+	}
+	vestingLockedUndelegated, err := s.getVestingLockedUndelegated(time.Now())
+	if err != nil {
+		return supply, fmt.Errorf("getVestingLockedUndelegated: %w", err)
+	}
+	supply.Liquid = nonNegative(supply.Total.Sub(supply.Bonded).Sub(supply.Unbonding).
+		Sub(supply.CommunityPool).Sub(supply.ModuleAccounts).Sub(vestingLockedUndelegated))
+	supply.Circulating = nonNegative(supply.Total.Sub(supply.CommunityPool).Sub(supply.VestingLocked))
+	return supply, nil
+}
+
+// moduleAddress returns the address of the module account derived from the module name
+func moduleAddress(name string) string {
+	hash := sha256.Sum256([]byte(name))
+	return types.AccAddress(hash[:20]).String()
+}
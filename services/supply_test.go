@@ -0,0 +1,43 @@
+package services
+
+import (
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/shopspring/decimal"
+	"testing"
+	"time"
+)
+
+func TestModuleAddress(t *testing.T) {
+	tests := map[string]string{
+		"distribution":       "cosmos1jv65s3grqf6v6jl3dp4t6c9t9rk99cd88lyufl",
+		"fee_collector":      "cosmos17xpfvakm2amg962yls6f84z3kell8c5lserqta",
+		"bonded_tokens_pool": "cosmos1fl48vsnmsdzcv85q5d2q4z5ajdha8yu34mf0eh",
+	}
+	for name, expected := range tests {
+		if address := moduleAddress(name); address != expected {
+			t.Errorf("%s: expected %s, got %s", name, expected, address)
+		}
+	}
+}
+
+func TestVestingLockedUndelegated(t *testing.T) {
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	account := func(address string, amount int64) dmodels.VestingAccount {
+		return dmodels.VestingAccount{
+			Address:         address,
+			Type:            dmodels.VestingTypeDelayed,
+			OriginalVesting: decimal.NewFromInt(amount),
+			EndTime:         now.AddDate(1, 0, 0),
+		}
+	}
+	accounts := []dmodels.VestingAccount{account("cosmos1a", 100), account("cosmos1b", 50), account("cosmos1c", 30)}
+	stakes := []dmodels.DelegationStake{
+		{Delegator: "cosmos1a", Amount: decimal.NewFromInt(40)},
+		{Delegator: "cosmos1a", Amount: decimal.NewFromInt(20)},
+		{Delegator: "cosmos1b", Amount: decimal.NewFromInt(80)},
+	}
+	// 100 - 60 delegated, 50 all delegated, 30 not delegated
+	if amount := vestingLockedUndelegated(accounts, stakes, now); !amount.Equal(decimal.NewFromInt(70)) {
+		t.Errorf("expected 70, got %s", amount)
+	}
+}
@@ -12,26 +12,10 @@ import (
 	"strings"
 )
 
-func (s *ServiceFacade) GetAggTransactionsFee(filter filters.Agg) (items []smodels.AggItem, err error) {
-	items, err = s.dao.GetAggTransactionsFee(filter)
-	if err != nil {
-		return nil, fmt.Errorf("dao.GetAggTransactionsFee: %s", err.Error())
-	}
-	return items, nil
-}
-
-func (s *ServiceFacade) GetAggOperationsCount(filter filters.Agg) (items []smodels.AggItem, err error) {
-	items, err = s.dao.GetAggOperationsCount(filter)
-	if err != nil {
-		return nil, fmt.Errorf("dao.GetAggOperationsCount: %s", err.Error())
-	}
-	return items, nil
-}
-
 func (s *ServiceFacade) GetAvgOperationsPerBlock(filter filters.Agg) (items []smodels.AggItem, err error) {
 	items, err = s.dao.GetAvgOperationsPerBlock(filter)
 	if err != nil {
-		return nil, fmt.Errorf("dao.GetAvgOperationsPerBlock: %s", err.Error())
+		return nil, fmt.Errorf("dao.GetAvgOperationsPerBlock: %w", err)
 	}
 	return items, nil
 }
@@ -43,7 +27,7 @@ type baseMsg struct {
 func (s *ServiceFacade) GetTransaction(hash string) (tx smodels.Tx, err error) {
 	dTx, err := s.node.GetTransaction(hash)
 	if err != nil {
-		return tx, fmt.Errorf("node.GetTransaction: %s", err.Error())
+		return tx, fmt.Errorf("node.GetTransaction: %w", nodeLookupError(err, "transaction"))
 	}
 	var fee decimal.Decimal
 	for _, a := range dTx.Tx.AuthInfo.Fee.Amount {
@@ -63,6 +47,14 @@ func (s *ServiceFacade) GetTransaction(hash string) (tx smodels.Tx, err error) {
 		t := strings.Trim(parts[len(parts)-1], "Msg")
 		msgs = append(msgs, smodels.Message{Type: t, Body: m})
 	}
+	labels, err := s.getAddressLabelsMap()
+	if err != nil {
+		return tx, fmt.Errorf("getAddressLabelsMap: %w", err)
+	}
+	txLabels := make(map[string]dmodels.AddressLabel)
+	for _, m := range dTx.Tx.Body.Messages {
+		collectLabels(m, labels, txLabels)
+	}
 	success := dTx.TxResponse.Code == 0
 	fee = node.Precision(fee)
 	return smodels.Tx{
@@ -76,17 +68,44 @@ func (s *ServiceFacade) GetTransaction(hash string) (tx smodels.Tx, err error) {
 		Memo:      dTx.Tx.Body.Memo,
 		CreatedAt: dmodels.NewTime(dTx.TxResponse.Timestamp),
 		Messages:  msgs,
+		Labels:    txLabels,
 	}, nil
 }
 
+// collectLabels adds the labels of all string values of the message that are labeled addresses
+func collectLabels(msg json.RawMessage, labels map[string]dmodels.AddressLabel, dst map[string]dmodels.AddressLabel) {
+	var value interface{}
+	if json.Unmarshal(msg, &value) != nil {
+		return
+	}
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch t := v.(type) {
+		case string:
+			if label, ok := labels[t]; ok {
+				dst[t] = label
+			}
+		case []interface{}:
+			for _, item := range t {
+				walk(item)
+			}
+		case map[string]interface{}:
+			for _, item := range t {
+				walk(item)
+			}
+		}
+	}
+	walk(value)
+}
+
 func (s *ServiceFacade) GetTransactions(filter filters.Transactions) (resp smodels.PaginatableResponse, err error) {
 	dTxs, err := s.dao.GetTransactions(filter)
 	if err != nil {
-		return resp, fmt.Errorf("dao.GetTransactions: %s", err.Error())
+		return resp, fmt.Errorf("dao.GetTransactions: %w", err)
 	}
 	total, err := s.dao.GetTransactionsCount(filter)
 	if err != nil {
-		return resp, fmt.Errorf("dao.GetTransactionsCount: %s", err.Error())
+		return resp, fmt.Errorf("dao.GetTransactionsCount: %w", err)
 	}
 	var txs []smodels.TxItem
 	for _, tx := range dTxs {
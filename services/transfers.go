@@ -2,15 +2,199 @@ package services
 
 import (
 	"fmt"
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
 	"github.com/everstake/cosmoscan-api/smodels"
 )
 
-func (s *ServiceFacade) GetAggTransfersVolume(filter filters.Agg) (items []smodels.AggItem, err error) {
+// GetAggTransfersVolume returns the transfers volume, the volume of the labeled entities (e.g. exchange inflow)
+// if entities or categories are set
+func (s *ServiceFacade) GetAggTransfersVolume(filter filters.TransfersAgg) (items []smodels.AggItem, err error) {
+	if filter.Direction != "" && filter.Direction != filters.TransfersDirectionIn && filter.Direction != filters.TransfersDirectionOut {
+		return nil, derrors.InvalidArgument("direction should be %s or %s", filters.TransfersDirectionIn, filters.TransfersDirectionOut)
+	}
+	if len(filter.Entities) != 0 || len(filter.Categories) != 0 {
+		filter.Addresses, err = s.getLabeledAddresses(filter.Entities, filter.Categories)
+		if err != nil {
+			return nil, fmt.Errorf("getLabeledAddresses: %w", err)
+		}
+		if len(filter.Addresses) == 0 {
+			return []smodels.AggItem{}, nil
+		}
+	}
 	items, err = s.dao.GetAggTransfersVolume(filter)
 	if err != nil {
-		return nil, fmt.Errorf("dao.GetAggTransfersVolume: %s", err.Error())
+		return nil, fmt.Errorf("dao.GetAggTransfersVolume: %w", err)
 	}
 	return items, nil
 }
 
+
+const (
+	transfersMaxLimit      = 100
+	counterpartiesMaxLimit = 100
+
+	traceDefaultDepth = 2
+	traceMaxDepth     = 4
+	// traceEdgesLimit is the max number of the largest counterparties followed from each address
+	traceEdgesLimit = 10
+	traceMaxNodes   = 200
+)
+
+func (s *ServiceFacade) GetTransfers(filter filters.Transfers) (resp smodels.PaginatableResponse, err error) {
+	if filter.Limit == 0 || filter.Limit > transfersMaxLimit {
+		filter.Limit = transfersMaxLimit
+	}
+	transfers, err := s.dao.GetTransfers(filter)
+	if err != nil {
+		return resp, fmt.Errorf("dao.GetTransfers: %w", err)
+	}
+	total, err := s.dao.GetTransfersTotal(filter)
+	if err != nil {
+		return resp, fmt.Errorf("dao.GetTransfersTotal: %w", err)
+	}
+	var addresses []string
+	for _, t := range transfers {
+		addresses = append(addresses, t.From, t.To)
+	}
+	labels, err := s.getAddressLabels(addresses...)
+	if err != nil {
+		return resp, fmt.Errorf("getAddressLabels: %w", err)
+	}
+	items := make([]smodels.Transfer, len(transfers))
+	for i, t := range transfers {
+		items[i] = smodels.Transfer{
+			TxHash:    t.TxHash,
+			From:      t.From,
+			To:        t.To,
+			Amount:    t.Amount,
+			Currency:  t.Currency,
+			CreatedAt: dmodels.NewTime(t.CreatedAt),
+		}
+		if label, ok := labels[t.From]; ok {
+			items[i].FromLabel = &label
+		}
+		if label, ok := labels[t.To]; ok {
+			items[i].ToLabel = &label
+		}
+	}
+	return smodels.PaginatableResponse{Items: items, Total: total}, nil
+}
+
+// GetCounterparties returns the top counterparties of the address by the volume of atom transfers in both directions
+func (s *ServiceFacade) GetCounterparties(filter filters.Counterparties) (items []smodels.Counterparty, err error) {
+	if _, err := types.AccAddressFromBech32(filter.Address); err != nil {
+		return nil, derrors.Wrap(derrors.CodeInvalidArgument, err, "invalid address")
+	}
+	if filter.Limit == 0 || filter.Limit > counterpartiesMaxLimit {
+		filter.Limit = counterpartiesMaxLimit
+	}
+	counterparties, err := s.dao.GetCounterparties(filter)
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetCounterparties: %w", err)
+	}
+	addresses := make([]string, len(counterparties))
+	for i, c := range counterparties {
+		addresses[i] = c.Address
+	}
+	labels, err := s.getAddressLabels(addresses...)
+	if err != nil {
+		return nil, fmt.Errorf("getAddressLabels: %w", err)
+	}
+	items = make([]smodels.Counterparty, len(counterparties))
+	for i, c := range counterparties {
+		items[i] = smodels.Counterparty{
+			Address:   c.Address,
+			Sent:      c.Sent,
+			Received:  c.Received,
+			Transfers: c.Transfers,
+		}
+		if label, ok := labels[c.Address]; ok {
+			items[i].Label = &label
+		}
+	}
+	return items, nil
+}
+
+// GetTransfersTrace follows the atom transfers from (or to) the address up to filter.Depth hops,
+// only the largest counterparties of each address are followed and exchanges and modules are not expanded
+func (s *ServiceFacade) GetTransfersTrace(filter filters.Trace) (graph smodels.TraceGraph, err error) {
+	if _, err := types.AccAddressFromBech32(filter.Address); err != nil {
+		return graph, derrors.Wrap(derrors.CodeInvalidArgument, err, "invalid address")
+	}
+	if filter.Direction == "" {
+		filter.Direction = filters.TransfersDirectionOut
+	}
+	if filter.Direction != filters.TransfersDirectionIn && filter.Direction != filters.TransfersDirectionOut {
+		return graph, derrors.InvalidArgument("direction should be %s or %s", filters.TransfersDirectionIn, filters.TransfersDirectionOut)
+	}
+	if filter.Depth == 0 {
+		filter.Depth = traceDefaultDepth
+	}
+	if filter.Depth > traceMaxDepth {
+		return graph, derrors.InvalidArgument("depth should not be greater than %d", traceMaxDepth)
+	}
+	labels, err := s.getAddressLabelsMap()
+	if err != nil {
+		return graph, fmt.Errorf("getAddressLabelsMap: %w", err)
+	}
+	addNode := func(address string, depth uint64) {
+		node := smodels.TraceNode{Address: address, Depth: depth}
+		if label, ok := labels[address]; ok {
+			node.Label = &label
+		}
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	visited := map[string]bool{filter.Address: true}
+	addNode(filter.Address, 0)
+	edges := make(map[string]bool)
+	frontier := []string{filter.Address}
+	for depth := uint64(1); depth <= filter.Depth && len(frontier) != 0; depth++ {
+		items, err := s.dao.GetTransferEdges(filters.TransferEdges{
+			TimeRange: filter.TimeRange,
+			Addresses: frontier,
+			Direction: filter.Direction,
+			Limit:     traceEdgesLimit,
+		})
+		if err != nil {
+			return graph, fmt.Errorf("dao.GetTransferEdges: %w", err)
+		}
+		var next []string
+		for _, item := range items {
+			address := item.Recipient
+			if filter.Direction == filters.TransfersDirectionIn {
+				address = item.Sender
+			}
+			if !visited[address] {
+				if len(graph.Nodes) >= traceMaxNodes {
+					graph.Truncated = true
+					continue
+				}
+				visited[address] = true
+				addNode(address, depth)
+				label, ok := labels[address]
+				if !ok || (label.Category != dmodels.LabelCategoryExchange && label.Category != dmodels.LabelCategoryModule) {
+					next = append(next, address)
+				}
+			}
+			key := item.Sender + "." + item.Recipient
+			if edges[key] {
+				continue
+			}
+			edges[key] = true
+			graph.Edges = append(graph.Edges, smodels.TraceEdge{
+				From:      item.Sender,
+				To:        item.Recipient,
+				Amount:    item.Amount,
+				Transfers: item.Transfers,
+			})
+		}
+		frontier = next
+	}
+	if graph.Edges == nil {
+		graph.Edges = []smodels.TraceEdge{}
+	}
+	return graph, nil
+}
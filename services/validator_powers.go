@@ -0,0 +1,150 @@
+package services
+
+import (
+	"fmt"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/everstake/cosmoscan-api/smodels"
+	"github.com/shopspring/decimal"
+	"sort"
+	"time"
+)
+
+// nakamotoLimit is the share of the voting power (in percents) the validators need to halt the chain
+var nakamotoLimit = decimal.NewFromFloat(33.4)
+
+// GetAggValidatorPower returns the voting power of the validator at the end of every period
+func (s *ServiceFacade) GetAggValidatorPower(address string, filter filters.Agg) (items []smodels.AggItem, err error) {
+	validator, err := s.GetValidator(address)
+	if err != nil {
+		return nil, fmt.Errorf("GetValidator: %w", err)
+	}
+	changes, err := s.dao.GetAggValidatorPower(filters.ValidatorPowerAgg{Agg: filter, Validator: validator.ConsAddress})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetAggValidatorPower: %w", err)
+	}
+	initial, err := s.dao.GetValidatorsPowers(filters.ValidatorPowers{
+		Validators: []string{validator.ConsAddress},
+		To:         dmodels.NewTime(filter.From.Add(-time.Second)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetValidatorsPowers: %w", err)
+	}
+	power := decimal.Zero
+	if len(initial) != 0 {
+		power = initial[0].Power
+	}
+	changesMap := make(map[int64]decimal.Decimal)
+	for _, item := range changes {
+		changesMap[item.Time.Unix()] = item.Value
+	}
+	for _, period := range filter.Periods() {
+		if value, ok := changesMap[period.Unix()]; ok {
+			power = value
+		}
+		items = append(items, smodels.AggItem{Time: dmodels.NewTime(period), Value: power})
+	}
+	return items, nil
+}
+
+// GetAggValidators33Power returns the Nakamoto coefficient at the end of every period computed from the stored validator set,
+// the set at the start is loaded once and the stored changes are applied period by period,
+// the periods before the set has been tracked are taken from the daily stats
+func (s *ServiceFacade) GetAggValidators33Power(filter filters.Agg) (items []smodels.AggItem, err error) {
+	periods := filter.Periods()
+	if len(periods) == 0 {
+		return nil, nil
+	}
+	stats, err := s.dao.GetAggValidators33Power(filter)
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetAggValidators33Power: %w", err)
+	}
+	statsMap := make(map[int64]decimal.Decimal)
+	for _, item := range stats {
+		statsMap[item.Time.Unix()] = item.Value
+	}
+	initial, err := s.dao.GetValidatorsPowers(filters.ValidatorPowers{To: dmodels.NewTime(periods[0].Add(-time.Second))})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetValidatorsPowers: %w", err)
+	}
+	changes, err := s.dao.GetValidatorPowerChanges(filters.ValidatorPowers{From: dmodels.NewTime(periods[0]), To: filter.To})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetValidatorPowerChanges: %w", err)
+	}
+	powers := make(map[string]decimal.Decimal, len(initial))
+	for _, p := range initial {
+		powers[p.Validator] = p.Power
+	}
+	var next int
+	for i, period := range periods {
+		for ; next < len(changes) && (i+1 == len(periods) || changes[next].CreatedAt.Before(periods[i+1])); next++ {
+			change := changes[next]
+			if change.Power.IsPositive() {
+				powers[change.Validator] = change.Power
+			} else {
+				delete(powers, change.Validator)
+			}
+		}
+		if len(powers) == 0 {
+			if value, ok := statsMap[period.Unix()]; ok {
+				items = append(items, smodels.AggItem{Time: dmodels.NewTime(period), Value: value})
+			}
+			continue
+		}
+		amounts := make([]decimal.Decimal, 0, len(powers))
+		for _, power := range powers {
+			amounts = append(amounts, power)
+		}
+		items = append(items, smodels.AggItem{
+			Time:  dmodels.NewTime(period),
+			Value: decimal.NewFromInt(int64(nakamotoCoefficient(amounts, nakamotoLimit))),
+		})
+	}
+	return items, nil
+}
+
+// getPowerChanges returns the change of the voting power of every validator (by consensus address) since the time
+func (s *ServiceFacade) getPowerChanges(since time.Time) (map[string]decimal.Decimal, error) {
+	past, err := s.dao.GetValidatorsPowers(filters.ValidatorPowers{To: dmodels.NewTime(since)})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetValidatorsPowers: %w", err)
+	}
+	changes := make(map[string]decimal.Decimal)
+	if len(past) == 0 { // the set was not tracked yet
+		return changes, nil
+	}
+	current, err := s.dao.GetValidatorsPowers(filters.ValidatorPowers{})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetValidatorsPowers: %w", err)
+	}
+	for _, p := range past {
+		changes[p.Validator] = p.Power.Neg()
+	}
+	for _, p := range current {
+		changes[p.Validator] = changes[p.Validator].Add(p.Power)
+	}
+	return changes, nil
+}
+
+// nakamotoCoefficient returns the minimal number of validators that together have more than limit percents of the power
+func nakamotoCoefficient(amounts []decimal.Decimal, limit decimal.Decimal) (n uint64) {
+	sort.Slice(amounts, func(i, j int) bool {
+		return amounts[i].GreaterThan(amounts[j])
+	})
+	total := decimal.Zero
+	for _, amount := range amounts {
+		total = total.Add(amount)
+	}
+	if total.IsZero() {
+		return 0
+	}
+	sum := decimal.Zero
+	for _, amount := range amounts {
+		sum = sum.Add(amount)
+		n++
+		if sum.Div(total).Mul(decimal.NewFromInt(100)).GreaterThan(limit) {
+			return n
+		}
+	}
+	return n
+}
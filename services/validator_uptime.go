@@ -0,0 +1,227 @@
+package services
+
+import (
+	"fmt"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/services/node"
+	"github.com/everstake/cosmoscan-api/smodels"
+	"github.com/shopspring/decimal"
+	"sort"
+	"time"
+)
+
+const (
+	slashingParamsCacheKey   = "slashing_params"
+	validatorsUptimeCacheKey = "validators_uptime"
+
+	defaultSigningMapBlocks = 100
+	maxSigningMapBlocks     = 1000
+)
+
+// GetValidatorUptime returns the signing stat of the validator over the slashing window,
+// the validators out of the active set do not sign, so only the window is returned for them
+func (s *ServiceFacade) GetValidatorUptime(address string) (uptime smodels.ValidatorUptime, err error) {
+	validator, err := s.GetValidator(address)
+	if err != nil {
+		return uptime, fmt.Errorf("GetValidator: %w", err)
+	}
+	validatorsMap, err := s.GetValidatorMap()
+	if err != nil {
+		return uptime, fmt.Errorf("GetValidatorMap: %w", err)
+	}
+	params, err := s.getSlashingParams()
+	if err != nil {
+		return uptime, fmt.Errorf("getSlashingParams: %w", err)
+	}
+	from, to, err := s.getUptimeWindow(params.SignedBlocksWindow)
+	if err != nil {
+		return uptime, fmt.Errorf("getUptimeWindow: %w", err)
+	}
+	uptime = smodels.ValidatorUptime{
+		Window:             to - from + 1,
+		FromHeight:         from,
+		ToHeight:           to,
+		MinSignedPerWindow: params.MinSignedPerWindow,
+		Active:             validatorsMap[address].Status == node.BondedValidatorStatus,
+	}
+	if !uptime.Active {
+		return uptime, nil
+	}
+	uptime.Missed, err = s.dao.GetMissedBlocksCount(filters.MissedBlocks{
+		Validators: []string{validator.ConsAddress},
+		FromHeight: from,
+		ToHeight:   to,
+	})
+	if err != nil {
+		return uptime, fmt.Errorf("dao.GetMissedBlocksCount: %w", err)
+	}
+	uptime.Proposed, err = s.dao.GetProposedBlocksTotal(filters.BlocksProposed{
+		Proposers:  []string{validator.ConsAddress},
+		FromHeight: from,
+		ToHeight:   to,
+	})
+	if err != nil {
+		return uptime, fmt.Errorf("dao.GetProposedBlocksTotal: %w", err)
+	}
+	if uptime.Missed > uptime.Window {
+		uptime.Missed = uptime.Window
+	}
+	uptime.Signed = uptime.Window - uptime.Missed
+	uptime.Uptime = uptimePercent(uptime.Missed, uptime.Window)
+	maxMissed := decimal.New(1, 0).Sub(params.MinSignedPerWindow).
+		Mul(decimal.NewFromInt(int64(params.SignedBlocksWindow))).IntPart()
+	if uint64(maxMissed) > uptime.Missed {
+		uptime.MissedBeforeJail = uint64(maxMissed) - uptime.Missed
+	}
+	return uptime, nil
+}
+
+// GetValidatorSigningMap returns the signed, missed and proposed flags of the validator for the latest blocks
+func (s *ServiceFacade) GetValidatorSigningMap(address string, blocks uint64) (items []smodels.SigningMapItem, err error) {
+	if blocks == 0 {
+		blocks = defaultSigningMapBlocks
+	}
+	if blocks > maxSigningMapBlocks {
+		return nil, derrors.InvalidArgument("blocks should not be greater than %d", maxSigningMapBlocks)
+	}
+	validator, err := s.GetValidator(address)
+	if err != nil {
+		return nil, fmt.Errorf("GetValidator: %w", err)
+	}
+	from, to, err := s.getUptimeWindow(blocks)
+	if err != nil {
+		return nil, fmt.Errorf("getUptimeWindow: %w", err)
+	}
+	missed, err := s.dao.GetMissedBlocksHeights(filters.MissedBlocks{
+		Validators: []string{validator.ConsAddress},
+		FromHeight: from,
+		ToHeight:   to,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetMissedBlocksHeights: %w", err)
+	}
+	proposed, err := s.dao.GetProposedBlocksHeights(filters.BlocksProposed{
+		Proposers:  []string{validator.ConsAddress},
+		FromHeight: from,
+		ToHeight:   to,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetProposedBlocksHeights: %w", err)
+	}
+	missedMap := make(map[uint64]bool)
+	for _, height := range missed {
+		missedMap[height] = true
+	}
+	proposedMap := make(map[uint64]bool)
+	for _, height := range proposed {
+		proposedMap[height] = true
+	}
+	items = make([]smodels.SigningMapItem, 0, to-from+1)
+	for height := to; height >= from && height > 0; height-- {
+		items = append(items, smodels.SigningMapItem{
+			Height:   height,
+			Signed:   !missedMap[height],
+			Missed:   missedMap[height],
+			Proposed: proposedMap[height],
+		})
+	}
+	return items, nil
+}
+
+// GetValidatorsUptime returns the bonded validators sorted by uptime over the slashing window
+func (s *ServiceFacade) GetValidatorsUptime() (items []smodels.ValidatorUptimeItem, err error) {
+	data, err := s.dao.CacheLoad(validatorsUptimeCacheKey, time.Minute*5, func() (interface{}, error) {
+		return s.makeValidatorsUptime()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("makeValidatorsUptime: %w", err)
+	}
+	return data.([]smodels.ValidatorUptimeItem), nil
+}
+
+func (s *ServiceFacade) makeValidatorsUptime() (items []smodels.ValidatorUptimeItem, err error) {
+	params, err := s.getSlashingParams()
+	if err != nil {
+		return nil, fmt.Errorf("getSlashingParams: %w", err)
+	}
+	from, to, err := s.getUptimeWindow(params.SignedBlocksWindow)
+	if err != nil {
+		return nil, fmt.Errorf("getUptimeWindow: %w", err)
+	}
+	window := to - from + 1
+	missed, err := s.dao.GetValidatorsMissedBlocks(filters.MissedBlocks{FromHeight: from, ToHeight: to})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetValidatorsMissedBlocks: %w", err)
+	}
+	missedMap := make(map[string]uint64)
+	for _, item := range missed {
+		missedMap[item.Validator] = item.Value
+	}
+	validatorsMap, err := s.GetValidatorMap()
+	if err != nil {
+		return nil, fmt.Errorf("GetValidatorMap: %w", err)
+	}
+	validators, err := s.GetValidators()
+	if err != nil {
+		return nil, fmt.Errorf("GetValidators: %w", err)
+	}
+	sort.Slice(validators, func(i, j int) bool {
+		return validators[i].Power.GreaterThan(validators[j].Power)
+	})
+	for _, v := range validators {
+		if validatorsMap[v.OperatorAddress].Status != node.BondedValidatorStatus {
+			continue
+		}
+		m := missedMap[v.ConsAddress]
+		if m > window {
+			m = window
+		}
+		items = append(items, smodels.ValidatorUptimeItem{
+			Title:           v.Title,
+			OperatorAddress: v.OperatorAddress,
+			ConsAddress:     v.ConsAddress,
+			Missed:          m,
+			Uptime:          uptimePercent(m, window),
+		})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Missed < items[j].Missed
+	})
+	return items, nil
+}
+
+func (s *ServiceFacade) getSlashingParams() (params node.SlashingParams, err error) {
+	data, err := s.dao.CacheLoad(slashingParamsCacheKey, time.Hour, func() (interface{}, error) {
+		return s.node.GetSlashingParams()
+	})
+	if err != nil {
+		return params, fmt.Errorf("node.GetSlashingParams: %w", err)
+	}
+	return data.(node.SlashingParams), nil
+}
+
+// getUptimeWindow returns the range of the latest n parsed blocks
+func (s *ServiceFacade) getUptimeWindow(n uint64) (from uint64, to uint64, err error) {
+	blocks, err := s.dao.GetBlocks(filters.Blocks{Limit: 1})
+	if err != nil {
+		return 0, 0, fmt.Errorf("dao.GetBlocks: %w", err)
+	}
+	if len(blocks) == 0 {
+		return 0, 0, derrors.NotFound("blocks are not parsed yet")
+	}
+	to = blocks[0].ID
+	from = 1
+	if n != 0 && to > n {
+		from = to - n + 1
+	}
+	return from, to, nil
+}
+
+func uptimePercent(missed uint64, window uint64) decimal.Decimal {
+	if window == 0 {
+		return decimal.Zero
+	}
+	signed := decimal.NewFromInt(int64(window - missed))
+	return signed.Div(decimal.NewFromInt(int64(window))).Mul(decimal.New(100, 0)).Truncate(2)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
 	"github.com/everstake/cosmoscan-api/dao/filters"
 	"github.com/everstake/cosmoscan-api/dmodels"
 	"github.com/everstake/cosmoscan-api/log"
@@ -25,31 +26,74 @@ func (s *ServiceFacade) UpdateValidatorsMap() {
 		log.Error("UpdateValidatorsMap: makeValidatorMap: %s", err.Error())
 		return
 	}
+	previous, found := s.dao.CacheGet(validatorsMapCacheKey)
+	if previousMap, ok := previous.(map[string]node.Validator); found && ok {
+		err = s.emitWebhookEvents(validatorJailEvents(previousMap, mp))
+		if err != nil {
+			log.Error("UpdateValidatorsMap: emitWebhookEvents: %s", err.Error())
+		}
+	}
 	s.dao.CacheSet(validatorsMapCacheKey, mp, time.Minute*30)
 }
 
-func (s *ServiceFacade) GetValidatorMap() (map[string]node.Validator, error) {
-	data, found := s.dao.CacheGet(validatorsMapCacheKey)
-	if found {
-		return data.(map[string]node.Validator), nil
+// validatorJailEvents compares the jailed flags of the validators with the previous ones,
+// a validator is jailed again at another unbonding height
+func validatorJailEvents(previous map[string]node.Validator, current map[string]node.Validator) (events []dmodels.WebhookEvent) {
+	now := time.Now()
+	for address, v := range current {
+		p, ok := previous[address]
+		if !ok || p.Jailed == v.Jailed {
+			continue
+		}
+		eventType, height := dmodels.WebhookEventValidatorJailed, v.UnbondingHeight
+		if !v.Jailed {
+			eventType, height = dmodels.WebhookEventValidatorUnjailed, p.UnbondingHeight
+		}
+		event := dmodels.NewWebhookEvent(eventType, fmt.Sprintf("%s.%d", address, height), smodels.WebhookValidatorJail{
+			Validator:       address,
+			Moniker:         v.Description.Moniker,
+			Jailed:          v.Jailed,
+			UnbondingHeight: v.UnbondingHeight,
+			UnbondingTime:   v.UnbondingTime,
+		}, now)
+		event.Validators = []string{address}
+		events = append(events, event)
+	}
+	return events
+}
+
+// WarmUpCache fills the validators cache on startup unless another replica has already done it
+func (s *ServiceFacade) WarmUpCache() {
+	_, err := s.GetValidatorMap()
+	if err != nil {
+		log.Error("WarmUpCache: GetValidatorMap: %s", err.Error())
 	}
-	mp, err := s.makeValidatorMap()
+	_, err = s.GetValidators()
 	if err != nil {
-		return nil, fmt.Errorf("makeValidatorMap: %s", err.Error())
+		log.Error("WarmUpCache: GetValidators: %s", err.Error())
 	}
-	return mp, nil
+}
+
+func (s *ServiceFacade) GetValidatorMap() (map[string]node.Validator, error) {
+	data, err := s.dao.CacheLoad(validatorsMapCacheKey, time.Minute*30, func() (interface{}, error) {
+		return s.makeValidatorMap()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("makeValidatorMap: %w", err)
+	}
+	return data.(map[string]node.Validator), nil
 }
 
 func (s *ServiceFacade) getConsensusValidatorMap() (map[string]node.Validator, error) {
 	vMap, err := s.GetValidatorMap()
 	if err != nil {
-		return nil, fmt.Errorf("GetValidatorMap: %s", err.Error())
+		return nil, fmt.Errorf("GetValidatorMap: %w", err)
 	}
 	cvMap := make(map[string]node.Validator)
 	for _, v := range vMap {
 		consAddress, err := helpers.GetHexAddressFromBase64PK(v.ConsensusPubkey.Key)
 		if err != nil {
-			return nil, fmt.Errorf("helpers.GetHexAddressFromBase64PK: %s", err.Error())
+			return nil, fmt.Errorf("helpers.GetHexAddressFromBase64PK: %w", err)
 		}
 		cvMap[consAddress] = v
 	}
@@ -60,7 +104,7 @@ func (s *ServiceFacade) makeValidatorMap() (map[string]node.Validator, error) {
 	mp := make(map[string]node.Validator)
 	validators, err := s.node.GetValidators()
 	if err != nil {
-		return nil, fmt.Errorf("node.GetValidators: %s", err.Error())
+		return nil, fmt.Errorf("node.GetValidators: %w", err)
 	}
 	for _, validator := range validators {
 		mp[validator.OperatorAddress] = validator
@@ -71,12 +115,12 @@ func (s *ServiceFacade) makeValidatorMap() (map[string]node.Validator, error) {
 func (s *ServiceFacade) GetStakingPie() (pie smodels.Pie, err error) {
 	stakingPool, err := s.node.GetStakingPool()
 	if err != nil {
-		return pie, fmt.Errorf("node.GetStakingPool: %s", err.Error())
+		return pie, fmt.Errorf("node.GetStakingPool: %w", err)
 	}
 	pie.Total = stakingPool.Pool.BondedTokens
 	validatorsMap, err := s.GetValidatorMap()
 	if err != nil {
-		return pie, fmt.Errorf("s.GetValidatorMap: %s", err.Error())
+		return pie, fmt.Errorf("s.GetValidatorMap: %w", err)
 	}
 	var validators []node.Validator
 	for _, v := range validatorsMap {
@@ -101,11 +145,13 @@ func (s *ServiceFacade) GetStakingPie() (pie smodels.Pie, err error) {
 }
 
 func (s *ServiceFacade) GetValidators() (validators []smodels.Validator, err error) {
-	data, found := s.dao.CacheGet(validatorsCacheKey)
-	if found {
-		return data.([]smodels.Validator), nil
+	data, err := s.dao.CacheLoad(validatorsCacheKey, time.Hour, func() (interface{}, error) {
+		return s.makeValidators()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("makeValidators: %w", err)
 	}
-	return nil, fmt.Errorf("not found in cache")
+	return data.([]smodels.Validator), nil
 }
 
 func (s *ServiceFacade) UpdateValidators() {
@@ -120,51 +166,47 @@ func (s *ServiceFacade) UpdateValidators() {
 func (s *ServiceFacade) makeValidators() (validators []smodels.Validator, err error) {
 	nodeValidators, err := s.node.GetValidators()
 	if err != nil {
-		return nil, fmt.Errorf("node.GetValidators: %s", err.Error())
+		return nil, fmt.Errorf("node.GetValidators: %w", err)
 	}
 	stakingPool, err := s.node.GetStakingPool()
 	if err != nil {
-		return nil, fmt.Errorf("node.GetStakingPool: %s", err.Error())
+		return nil, fmt.Errorf("node.GetStakingPool: %w", err)
+	}
+	powerChanges, err := s.getPowerChanges(time.Now().Add(-time.Hour * 24))
+	if err != nil {
+		return nil, fmt.Errorf("getPowerChanges: %w", err)
 	}
 	for _, v := range nodeValidators {
 		consAddress, err := helpers.GetHexAddressFromBase64PK(v.ConsensusPubkey.Key)
 		if err != nil {
-			return nil, fmt.Errorf("helpers.GetHexAddressFromBase64PK: %s", err.Error())
+			return nil, fmt.Errorf("helpers.GetHexAddressFromBase64PK: %w", err)
 		}
 		blockProposed, err := s.dao.GetProposedBlocksTotal(filters.BlocksProposed{Proposers: []string{consAddress}})
 		if err != nil {
-			return nil, fmt.Errorf("dao.GetProposedBlocksTotal: %s", err.Error())
+			return nil, fmt.Errorf("dao.GetProposedBlocksTotal: %w", err)
 		}
 
 		addressBytes, err := types.GetFromBech32(v.OperatorAddress, types.Bech32PrefixValAddr)
 		if err != nil {
-			return nil, fmt.Errorf("types.GetFromBech32: %s", err.Error())
+			return nil, fmt.Errorf("types.GetFromBech32: %w", err)
 		}
 		address, err := types.AccAddressFromHex(hex.EncodeToString(addressBytes))
 		if err != nil {
-			return nil, fmt.Errorf("types.AccAddressFromHex: %s", err.Error())
+			return nil, fmt.Errorf("types.AccAddressFromHex: %w", err)
 		}
 		totalVotes, err := s.dao.GetTotalVotesByAddress(address.String())
 		if err != nil {
-			return nil, fmt.Errorf("dao.GetTotalVotesByAddress: %s", err.Error())
+			return nil, fmt.Errorf("dao.GetTotalVotesByAddress: %w", err)
 		}
 
 		delegatorsTotal, err := s.dao.GetDelegatorsTotal(filters.Delegators{Validators: []string{v.OperatorAddress}})
 		if err != nil {
-			return nil, fmt.Errorf("dao.GetDelegatorsTotal: %s", err.Error())
+			return nil, fmt.Errorf("dao.GetDelegatorsTotal: %w", err)
 		}
 
-		power24Change, err := s.dao.GetVotingPower(filters.VotingPower{
-			TimeRange: filters.TimeRange{
-				From: dmodels.NewTime(time.Now().Add(-time.Hour * 24)),
-				To:   dmodels.NewTime(time.Now()),
-			},
-			Validators: []string{v.OperatorAddress},
-		})
-
 		selfStake, err := s.node.GetDelegatorValidatorStake(address.String(), v.OperatorAddress)
 		if err != nil {
-			return nil, fmt.Errorf("node.GetDelegatorValidatorStake: %s", err.Error())
+			return nil, fmt.Errorf("node.GetDelegatorValidatorStake: %w", err)
 		}
 
 		power := v.DelegatorShares.Div(node.PrecisionDiv)
@@ -181,7 +223,7 @@ func (s *ServiceFacade) makeValidators() (validators []smodels.Validator, err er
 			Fee:             v.Commission.CommissionRates.Rate,
 			BlocksProposed:  blockProposed,
 			Delegators:      delegatorsTotal,
-			Power24Change:   power24Change,
+			Power24Change:   powerChanges[consAddress],
 			GovernanceVotes: totalVotes,
 			Website:         v.Description.Website,
 			OperatorAddress: v.OperatorAddress,
@@ -200,17 +242,17 @@ func (s *ServiceFacade) GetTopProposedBlocksValidators() (items []dmodels.Valida
 	}
 	items, err = s.dao.GetTopProposedBlocksValidators()
 	if err != nil {
-		return nil, fmt.Errorf("dao.GetTopProposedBlocksValidators: %s", err.Error())
+		return nil, fmt.Errorf("dao.GetTopProposedBlocksValidators: %w", err)
 	}
 	validators, err := s.GetValidatorMap()
 	if err != nil {
-		return nil, fmt.Errorf("GetValidators: %s", err.Error())
+		return nil, fmt.Errorf("GetValidators: %w", err)
 	}
 	mp := make(map[string]string)
 	for _, validator := range validators {
 		address, err := helpers.GetHexAddressFromBase64PK(validator.ConsensusPubkey.Key)
 		if err != nil {
-			return nil, fmt.Errorf("helpers.GetHexAddressFromBase64PK: %s", err.Error())
+			return nil, fmt.Errorf("helpers.GetHexAddressFromBase64PK: %w", err)
 		}
 		mp[address] = validator.Description.Moniker
 	}
@@ -234,11 +276,11 @@ func (s *ServiceFacade) GetMostJailedValidators() (items []dmodels.ValidatorValu
 	}
 	items, err = s.dao.GetMostJailedValidators()
 	if err != nil {
-		return nil, fmt.Errorf("dao.GetMostJailedValidators: %s", err.Error())
+		return nil, fmt.Errorf("dao.GetMostJailedValidators: %w", err)
 	}
 	validators, err := s.GetValidatorMap()
 	if err != nil {
-		return nil, fmt.Errorf("GetValidators: %s", err.Error())
+		return nil, fmt.Errorf("GetValidators: %w", err)
 	}
 	mp := make(map[string]string)
 	for _, validator := range validators {
@@ -310,11 +352,11 @@ func (s *ServiceFacade) GetFeeRanges() (items []smodels.FeeRange, err error) {
 func (s *ServiceFacade) GetValidatorsDelegatorsTotal() (values []dmodels.ValidatorValue, err error) {
 	validatorsMap, err := s.GetValidatorMap()
 	if err != nil {
-		return nil, fmt.Errorf("GetValidatorMap: %s", err.Error())
+		return nil, fmt.Errorf("GetValidatorMap: %w", err)
 	}
 	values, err = s.dao.GetValidatorsDelegatorsTotal()
 	if err != nil {
-		return nil, fmt.Errorf("dao.GetValidatorsDelegatorsTotal: %s", err.Error())
+		return nil, fmt.Errorf("dao.GetValidatorsDelegatorsTotal: %w", err)
 	}
 	for i, v := range values {
 		validator, found := validatorsMap[v.Validator]
@@ -326,40 +368,36 @@ func (s *ServiceFacade) GetValidatorsDelegatorsTotal() (values []dmodels.Validat
 }
 
 func (s *ServiceFacade) GetValidator(address string) (validator smodels.Validator, err error) {
-	data, found := s.dao.CacheGet(validatorsCacheKey)
-	if !found {
-		return validator, fmt.Errorf("not found in cache")
-	}
-	validators, ok := data.([]smodels.Validator)
-	if !ok {
-		return validator, fmt.Errorf("can`t cast to current type")
+	validators, err := s.GetValidators()
+	if err != nil {
+		return validator, fmt.Errorf("GetValidators: %w", err)
 	}
 	for _, v := range validators {
 		if v.OperatorAddress == address {
 			return v, nil
 		}
 	}
-	return validator, fmt.Errorf("not found validator with address: %s", address)
+	return validator, derrors.NotFound("not found validator with address: %s", address)
 }
 
 func (s *ServiceFacade) GetValidatorBalance(valAddress string) (balance smodels.Balance, err error) {
 	validator, err := s.GetValidator(valAddress)
 	if err != nil {
-		return balance, fmt.Errorf("GetValidator: %s", err.Error())
+		return balance, fmt.Errorf("GetValidator: %w", err)
 	}
 	balance.SelfDelegated = validator.SelfStake
 	balance.OtherDelegated = validator.Power.Sub(validator.SelfStake)
 	addressBytes, err := types.GetFromBech32(valAddress, types.Bech32PrefixValAddr)
 	if err != nil {
-		return balance, fmt.Errorf("types.GetFromBech32: %s", err.Error())
+		return balance, derrors.Wrap(derrors.CodeInvalidArgument, err, "invalid validator address")
 	}
 	address, err := types.AccAddressFromHex(hex.EncodeToString(addressBytes))
 	if err != nil {
-		return balance, fmt.Errorf("types.AccAddressFromHex: %s", err.Error())
+		return balance, fmt.Errorf("types.AccAddressFromHex: %w", err)
 	}
 	balance.Available, err = s.node.GetBalance(address.String())
 	if err != nil {
-		return balance, fmt.Errorf("node.GetBalance: %s", err.Error())
+		return balance, fmt.Errorf("node.GetBalance: %w", nodeLookupError(err, "validator"))
 	}
 	return balance, nil
 }
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"fmt"
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/everstake/cosmoscan-api/smodels"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+const vestingAccountsCacheKey = "vesting_accounts"
+
+// GetAccountVesting returns the vesting schedule of the account with the locked amount at the time, now by default
+func (s *ServiceFacade) GetAccountVesting(address string, at time.Time) (vesting smodels.AccountVesting, err error) {
+	if _, err := types.AccAddressFromBech32(address); err != nil {
+		return vesting, derrors.Wrap(derrors.CodeInvalidArgument, err, "invalid address")
+	}
+	accounts, err := s.dao.GetVestingAccounts(filters.VestingAccounts{Addresses: []string{address}})
+	if err != nil {
+		return vesting, fmt.Errorf("dao.GetVestingAccounts: %w", err)
+	}
+	if len(accounts) == 0 {
+		return vesting, derrors.NotFound("not found vesting account with address: %s", address)
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+	account := accounts[0]
+	locked := account.Locked(at)
+	return smodels.AccountVesting{
+		Address:         account.Address,
+		Type:            account.Type,
+		OriginalVesting: account.OriginalVesting,
+		Locked:          locked,
+		Unlocked:        account.OriginalVesting.Sub(locked),
+		StartTime:       dmodels.NewTime(account.StartTime),
+		EndTime:         dmodels.NewTime(account.EndTime),
+		Time:            dmodels.NewTime(at),
+	}, nil
+}
+
+// GetVestingSchedule returns the amount unlocked by all the vesting accounts in every period,
+// the periods can be in the future
+func (s *ServiceFacade) GetVestingSchedule(filter filters.Agg) (items []smodels.AggItem, err error) {
+	accounts, err := s.getVestingAccounts()
+	if err != nil {
+		return nil, fmt.Errorf("getVestingAccounts: %w", err)
+	}
+	end := time.Now()
+	if !filter.To.IsZero() {
+		end = filter.To.Time
+	}
+	periods := filter.Periods()
+	for i, period := range periods {
+		periodEnd := end
+		if i+1 < len(periods) {
+			periodEnd = periods[i+1]
+		}
+		items = append(items, smodels.AggItem{
+			Time:  dmodels.NewTime(period),
+			Value: vestingLocked(accounts, period).Sub(vestingLocked(accounts, periodEnd)),
+		})
+	}
+	return items, nil
+}
+
+// getVestingLocked returns the amount locked by all the vesting accounts at the time
+func (s *ServiceFacade) getVestingLocked(at time.Time) (amount decimal.Decimal, err error) {
+	accounts, err := s.getVestingAccounts()
+	if err != nil {
+		return amount, fmt.Errorf("getVestingAccounts: %w", err)
+	}
+	return vestingLocked(accounts, at), nil
+}
+
+func (s *ServiceFacade) getVestingAccounts() ([]dmodels.VestingAccount, error) {
+	data, err := s.dao.CacheLoad(vestingAccountsCacheKey, time.Minute*10, func() (interface{}, error) {
+		return s.dao.GetVestingAccounts(filters.VestingAccounts{})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetVestingAccounts: %w", err)
+	}
+	return data.([]dmodels.VestingAccount), nil
+}
+
+// getVestingLockedUndelegated returns the locked amount of the vesting accounts which is not delegated,
+// the delegations take the vesting tokens first, so only this part of the locked amount is in the balances
+func (s *ServiceFacade) getVestingLockedUndelegated(at time.Time) (amount decimal.Decimal, err error) {
+	accounts, err := s.getVestingAccounts()
+	if err != nil {
+		return amount, fmt.Errorf("getVestingAccounts: %w", err)
+	}
+	var addresses []string
+	for _, account := range accounts {
+		if account.Locked(at).IsPositive() {
+			addresses = append(addresses, account.Address)
+		}
+	}
+	if len(addresses) == 0 {
+		return decimal.Zero, nil
+	}
+	stakes, err := s.dao.GetDelegationStakes(filters.DelegationStakes{Delegators: addresses})
+	if err != nil {
+		return amount, fmt.Errorf("dao.GetDelegationStakes: %w", err)
+	}
+	return vestingLockedUndelegated(accounts, stakes, at), nil
+}
+
+func vestingLocked(accounts []dmodels.VestingAccount, at time.Time) decimal.Decimal {
+	amount := decimal.Zero
+	for _, account := range accounts {
+		amount = amount.Add(account.Locked(at))
+	}
+	return amount
+}
+
+func vestingLockedUndelegated(accounts []dmodels.VestingAccount, stakes []dmodels.DelegationStake, at time.Time) decimal.Decimal {
+	delegated := make(map[string]decimal.Decimal)
+	for _, stake := range stakes {
+		delegated[stake.Delegator] = delegated[stake.Delegator].Add(stake.Amount)
+	}
+	amount := decimal.Zero
+	for _, account := range accounts {
+		amount = amount.Add(nonNegative(account.Locked(at).Sub(delegated[account.Address])))
+	}
+	return amount
+}
+
+// getUnlockedAccountsTotal counts the accounts like dao.GetAccountsTotal, but the total amounts of the vesting accounts
+// are taken without the locked vesting
+func (s *ServiceFacade) getUnlockedAccountsTotal(filter filters.Accounts) (total uint64, err error) {
+	total, err = s.dao.GetAccountsTotal(filter)
+	if err != nil {
+		return total, fmt.Errorf("dao.GetAccountsTotal: %w", err)
+	}
+	vestingAccounts, err := s.getVestingAccounts()
+	if err != nil {
+		return total, fmt.Errorf("getVestingAccounts: %w", err)
+	}
+	now := time.Now()
+	locked := make(map[string]decimal.Decimal)
+	var addresses []string
+	for _, account := range vestingAccounts {
+		if amount := account.Locked(now); amount.IsPositive() {
+			locked[account.Address] = amount
+			addresses = append(addresses, account.Address)
+		}
+	}
+	if len(addresses) == 0 {
+		return total, nil
+	}
+	accounts, err := s.dao.GetAccounts(filters.Accounts{Addresses: addresses})
+	if err != nil {
+		return total, fmt.Errorf("dao.GetAccounts: %w", err)
+	}
+	return unlockedAccountsTotal(total, filter, accounts, locked), nil
+}
+
+// unlockedAccountsTotal corrects the total of the accounts matched by the filter for the locked amounts
+func unlockedAccountsTotal(total uint64, filter filters.Accounts, accounts []dmodels.Account, locked map[string]decimal.Decimal) uint64 {
+	matches := func(amount decimal.Decimal) bool {
+		if !filter.GtTotalAmount.IsZero() && !amount.GreaterThan(filter.GtTotalAmount) {
+			return false
+		}
+		if !filter.LtTotalAmount.IsZero() && !amount.LessThan(filter.LtTotalAmount) {
+			return false
+		}
+		return true
+	}
+	for _, account := range accounts {
+		if !filter.CreatedBefore.IsZero() && !account.CreatedAt.Before(filter.CreatedBefore) {
+			continue
+		}
+		amount := account.Balance.Add(account.Stake).Add(account.Unbonding)
+		was, is := matches(amount), matches(amount.Sub(locked[account.Address]))
+		switch {
+		case was && !is && total > 0:
+			total--
+		case !was && is:
+			total++
+		}
+	}
+	return total
+}
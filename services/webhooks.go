@@ -0,0 +1,461 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/everstake/cosmoscan-api/config"
+	"github.com/everstake/cosmoscan-api/dao/derrors"
+	"github.com/everstake/cosmoscan-api/dao/filters"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/everstake/cosmoscan-api/log"
+	"github.com/everstake/cosmoscan-api/services/helpers"
+	"github.com/everstake/cosmoscan-api/smodels"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	WebhookEventHeader     = "X-Cosmoscan-Event"
+	WebhookDeliveryHeader  = "X-Cosmoscan-Delivery"
+	WebhookTimestampHeader = "X-Cosmoscan-Timestamp"
+	// WebhookSignatureHeader is the hex hmac sha256 of "<timestamp>.<payload>" keyed by the secret of the webhook
+	WebhookSignatureHeader = "X-Cosmoscan-Signature"
+
+	webhookSecretLength     = 32
+	webhooksPerAPIKey       = 20
+	webhookFilterMaxItems   = 100
+	webhookDefaultTimeout   = time.Second * 10
+	webhookMaxAttempts      = 10
+	webhookFirstRetryDelay  = time.Second * 30
+	webhookMaxRetryDelay    = time.Hour * 6
+	webhookDeliveriesBatch  = 100
+	webhookWorkers          = 8
+	webhookDeliveriesMaxAge = time.Hour * 24 * 30
+	webhookMaxErrorLength   = 1024
+)
+
+var (
+	errWebhookPrivateHost = errors.New("private network hosts are not allowed")
+	privateNetworks       = parseCIDRs(
+		"0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8", "169.254.0.0/16", "172.16.0.0/12",
+		"192.168.0.0/16", "::1/128", "fc00::/7", "fe80::/10",
+	)
+)
+
+func (s *ServiceFacade) CreateWebhook(apiKeyID uint64, params smodels.WebhookParams) (webhook smodels.CreatedWebhook, err error) {
+	webhooks, err := s.dao.GetWebhooks(filters.Webhooks{APIKeyID: apiKeyID})
+	if err != nil {
+		return webhook, fmt.Errorf("dao.GetWebhooks: %w", err)
+	}
+	if len(webhooks) >= webhooksPerAPIKey {
+		return webhook, derrors.InvalidArgument("over max limit of %d webhooks", webhooksPerAPIKey)
+	}
+	b := make([]byte, webhookSecretLength)
+	_, err = rand.Read(b)
+	if err != nil {
+		return webhook, fmt.Errorf("rand.Read: %w", err)
+	}
+	webhook.Secret = hex.EncodeToString(b)
+	webhook.Webhook = dmodels.Webhook{
+		APIKeyID:  apiKeyID,
+		Secret:    webhook.Secret,
+		Active:    true,
+		CreatedAt: time.Now(),
+	}
+	err = s.setWebhookParams(&webhook.Webhook, params)
+	if err != nil {
+		return webhook, err
+	}
+	webhook.ID, err = s.dao.CreateWebhook(webhook.Webhook)
+	if err != nil {
+		return webhook, fmt.Errorf("dao.CreateWebhook: %w", err)
+	}
+	return webhook, nil
+}
+
+func (s *ServiceFacade) UpdateWebhook(apiKeyID uint64, id uint64, params smodels.WebhookParams) (webhook dmodels.Webhook, err error) {
+	webhook, err = s.GetWebhook(apiKeyID, id)
+	if err != nil {
+		return webhook, err
+	}
+	err = s.setWebhookParams(&webhook, params)
+	if err != nil {
+		return webhook, err
+	}
+	webhook.Active = params.Active
+	err = s.dao.UpdateWebhook(webhook)
+	if err != nil {
+		return webhook, fmt.Errorf("dao.UpdateWebhook: %w", err)
+	}
+	return webhook, nil
+}
+
+func (s *ServiceFacade) GetWebhooks(apiKeyID uint64) (webhooks []dmodels.Webhook, err error) {
+	webhooks, err = s.dao.GetWebhooks(filters.Webhooks{APIKeyID: apiKeyID})
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetWebhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// GetWebhook returns the webhook of the api key, webhooks of other keys are not found
+func (s *ServiceFacade) GetWebhook(apiKeyID uint64, id uint64) (webhook dmodels.Webhook, err error) {
+	webhook, err = s.dao.GetWebhook(filters.Webhooks{ID: id, APIKeyID: apiKeyID})
+	if err != nil {
+		if errors.Is(err, derrors.ErrNotFound) {
+			return webhook, derrors.NotFound("webhook %d not found", id)
+		}
+		return webhook, fmt.Errorf("dao.GetWebhook: %w", err)
+	}
+	return webhook, nil
+}
+
+func (s *ServiceFacade) DeleteWebhook(apiKeyID uint64, id uint64) error {
+	_, err := s.GetWebhook(apiKeyID, id)
+	if err != nil {
+		return err
+	}
+	err = s.dao.DeleteWebhook(id)
+	if err != nil {
+		return fmt.Errorf("dao.DeleteWebhook: %w", err)
+	}
+	err = s.dao.DeleteWebhookDeliveries(filters.WebhookDeliveries{WebhookID: id})
+	if err != nil {
+		return fmt.Errorf("dao.DeleteWebhookDeliveries: %w", err)
+	}
+	return nil
+}
+
+// GetWebhookDeliveries returns the delivery log of the webhook, the latest deliveries first
+func (s *ServiceFacade) GetWebhookDeliveries(apiKeyID uint64, filter filters.WebhookDeliveries) (deliveries []dmodels.WebhookDelivery, err error) {
+	_, err = s.GetWebhook(apiKeyID, filter.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+	if filter.Limit == 0 || filter.Limit > webhookDeliveriesBatch {
+		filter.Limit = webhookDeliveriesBatch
+	}
+	deliveries, err = s.dao.GetWebhookDeliveries(filter)
+	if err != nil {
+		return nil, fmt.Errorf("dao.GetWebhookDeliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// DeliverWebhooks sends the due deliveries, failed attempts are retried with exponential backoff
+// until webhookMaxAttempts are made
+func (s *ServiceFacade) DeliverWebhooks() {
+	webhooks, err := s.dao.GetWebhooks(filters.Webhooks{})
+	if err != nil {
+		log.Error("DeliverWebhooks: dao.GetWebhooks: %s", err.Error())
+		return
+	}
+	webhooksMap := make(map[uint64]dmodels.Webhook, len(webhooks))
+	for _, webhook := range webhooks {
+		webhooksMap[webhook.ID] = webhook
+	}
+	sem := make(chan struct{}, webhookWorkers)
+	wg := &sync.WaitGroup{}
+	// the started deliveries are finished on every return, so they do not overlap the next run
+	defer wg.Wait()
+	for {
+		deliveries, err := s.dao.GetWebhookDeliveries(filters.WebhookDeliveries{
+			Status:    dmodels.WebhookDeliveryPending,
+			DueBefore: time.Now(),
+			Limit:     webhookDeliveriesBatch,
+		})
+		if err != nil {
+			log.Error("DeliverWebhooks: dao.GetWebhookDeliveries: %s", err.Error())
+			return
+		}
+		for _, delivery := range deliveries {
+			// the attempt is claimed for longer than it can take, other replicas skip it
+			ok, err := s.dao.ClaimWebhookDelivery(delivery, time.Now().Add(s.webhookTimeout()*2))
+			if err != nil {
+				log.Error("DeliverWebhooks: dao.ClaimWebhookDelivery: %s", err.Error())
+				return
+			}
+			if !ok {
+				continue
+			}
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(delivery dmodels.WebhookDelivery) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				webhook, found := webhooksMap[delivery.WebhookID]
+				delivery = s.deliverWebhook(webhook, found, delivery)
+				err := s.dao.UpdateWebhookDelivery(delivery)
+				if err != nil {
+					log.Error("DeliverWebhooks: dao.UpdateWebhookDelivery: %s", err.Error())
+				}
+			}(delivery)
+		}
+		wg.Wait()
+		if len(deliveries) < webhookDeliveriesBatch {
+			return
+		}
+	}
+}
+
+// CleanWebhookDeliveries removes the finished deliveries older than webhookDeliveriesMaxAge from the log
+func (s *ServiceFacade) CleanWebhookDeliveries() {
+	for _, status := range []string{dmodels.WebhookDeliveryDelivered, dmodels.WebhookDeliveryFailed} {
+		err := s.dao.DeleteWebhookDeliveries(filters.WebhookDeliveries{
+			Status:        status,
+			CreatedBefore: time.Now().Add(-webhookDeliveriesMaxAge),
+		})
+		if err != nil {
+			log.Error("CleanWebhookDeliveries: dao.DeleteWebhookDeliveries: %s", err.Error())
+			return
+		}
+	}
+}
+
+func (s *ServiceFacade) deliverWebhook(webhook dmodels.Webhook, found bool, delivery dmodels.WebhookDelivery) dmodels.WebhookDelivery {
+	now := time.Now()
+	delivery.UpdatedAt = now
+	if !found || !webhook.Active {
+		delivery.Status = dmodels.WebhookDeliveryFailed
+		delivery.Error = "webhook is deleted or inactive"
+		return delivery
+	}
+	delivery.Attempts++
+	code, err := sendWebhook(s.webhookClient, webhook, delivery, now)
+	delivery.ResponseCode = code
+	if err == nil {
+		delivery.Status = dmodels.WebhookDeliveryDelivered
+		delivery.Error = ""
+		return delivery
+	}
+	delivery.Error = err.Error()
+	if len(delivery.Error) > webhookMaxErrorLength {
+		delivery.Error = delivery.Error[:webhookMaxErrorLength]
+	}
+	if delivery.Attempts >= webhookMaxAttempts {
+		delivery.Status = dmodels.WebhookDeliveryFailed
+		return delivery
+	}
+	delivery.NextAttemptAt = now.Add(webhookRetryDelay(delivery.Attempts))
+	return delivery
+}
+
+// emitWebhookEvents saves the deliveries of the events to the webhooks they match
+func (s *ServiceFacade) emitWebhookEvents(events []dmodels.WebhookEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	webhooks, err := s.dao.GetWebhooks(filters.Webhooks{Active: true})
+	if err != nil {
+		return fmt.Errorf("dao.GetWebhooks: %w", err)
+	}
+	deliveries, err := dmodels.NewWebhookDeliveries(webhooks, events)
+	if err != nil {
+		return fmt.Errorf("dmodels.NewWebhookDeliveries: %w", err)
+	}
+	err = s.dao.CreateWebhookDeliveries(deliveries)
+	if err != nil {
+		return fmt.Errorf("dao.CreateWebhookDeliveries: %w", err)
+	}
+	return nil
+}
+
+func (s *ServiceFacade) setWebhookParams(webhook *dmodels.Webhook, params smodels.WebhookParams) error {
+	u, err := url.Parse(params.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return derrors.InvalidArgument("invalid url")
+	}
+	if len(params.Events) == 0 {
+		return derrors.InvalidArgument("events are empty")
+	}
+	for _, event := range params.Events {
+		if !webhookEvent(event) {
+			return derrors.InvalidArgument("unknown event %s", event)
+		}
+	}
+	if len(params.Addresses) > webhookFilterMaxItems || len(params.Validators) > webhookFilterMaxItems {
+		return derrors.InvalidArgument("over max limit of %d addresses", webhookFilterMaxItems)
+	}
+	for _, address := range params.Addresses {
+		if _, err := types.AccAddressFromBech32(address); err != nil {
+			return derrors.Wrap(derrors.CodeInvalidArgument, err, "invalid address")
+		}
+	}
+	var consAddresses []string
+	if len(params.Validators) != 0 {
+		validators, err := s.GetValidatorMap()
+		if err != nil {
+			return fmt.Errorf("GetValidatorMap: %w", err)
+		}
+		for _, address := range params.Validators {
+			validator, ok := validators[address]
+			if !ok {
+				return derrors.InvalidArgument("validator %s not found", address)
+			}
+			consAddress, err := helpers.GetHexAddressFromBase64PK(validator.ConsensusPubkey.Key)
+			if err != nil {
+				return fmt.Errorf("helpers.GetHexAddressFromBase64PK: %w", err)
+			}
+			consAddresses = append(consAddresses, consAddress)
+		}
+	}
+	if params.MinAmount.IsNegative() {
+		return derrors.InvalidArgument("min_amount is negative")
+	}
+	if webhookHasEvent(params.Events, dmodels.WebhookEventTransfer) && len(params.Addresses) == 0 && !params.MinAmount.IsPositive() {
+		return derrors.InvalidArgument("transfer events need addresses or a positive min_amount")
+	}
+	webhook.URL = u.String()
+	webhook.Events = params.Events
+	webhook.Addresses = params.Addresses
+	webhook.Validators = params.Validators
+	webhook.ConsAddresses = consAddresses
+	webhook.MinAmount = params.MinAmount
+	webhook.UpdatedAt = time.Now()
+	return nil
+}
+
+func (s *ServiceFacade) webhookTimeout() time.Duration {
+	if s.cfg.Webhooks.Timeout == 0 {
+		return webhookDefaultTimeout
+	}
+	return time.Duration(s.cfg.Webhooks.Timeout) * time.Second
+}
+
+// newWebhookClient makes the client of the deliveries, redirects are not followed and connections
+// to private network hosts are refused unless they are allowed
+func newWebhookClient(cfg config.Webhooks) *http.Client {
+	timeout := webhookDefaultTimeout
+	if cfg.Timeout != 0 {
+		timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+	dialer := &net.Dialer{Timeout: timeout}
+	if !cfg.AllowPrivateHosts {
+		dialer.Control = func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || privateIP(ip) {
+				return errWebhookPrivateHost
+			}
+			return nil
+		}
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, address)
+			},
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        webhookWorkers * 4,
+			IdleConnTimeout:     time.Minute,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// sendWebhook posts the payload of the delivery signed by the secret of the webhook,
+// responses other than 2xx are errors
+func sendWebhook(client *http.Client, webhook dmodels.Webhook, delivery dmodels.WebhookDelivery, now time.Time) (code uint64, err error) {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("http.NewRequest: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", config.ServiceName)
+	req.Header.Set(WebhookEventHeader, delivery.Event)
+	req.Header.Set(WebhookDeliveryHeader, delivery.EventID)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(webhook.Secret, timestamp, delivery.Payload))
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<16))
+	code = uint64(resp.StatusCode)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return code, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return code, nil
+}
+
+// SignWebhookPayload returns the signature of the delivery, receivers compute it the same way to verify it
+func SignWebhookPayload(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookRetryDelay doubles the delay after every failed attempt
+func webhookRetryDelay(attempts uint64) time.Duration {
+	delay := webhookFirstRetryDelay
+	for i := uint64(1); i < attempts; i++ {
+		delay *= 2
+		if delay >= webhookMaxRetryDelay {
+			return webhookMaxRetryDelay
+		}
+	}
+	return delay
+}
+
+func webhookEvent(event string) bool {
+	for _, e := range dmodels.WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func webhookHasEvent(events []string, event string) bool {
+	for _, e := range events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func privateIP(ip net.IP) bool {
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseCIDRs(cidrs ...string) (networks []*net.IPNet) {
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
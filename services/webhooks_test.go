@@ -0,0 +1,106 @@
+package services
+
+import (
+	"github.com/everstake/cosmoscan-api/config"
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhookReceiver is a local stand-in of the webhook endpoint which fails the first requests
+type webhookReceiver struct {
+	mu       *sync.Mutex
+	failures int
+	payloads []string
+}
+
+func (rc *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	body, _ := ioutil.ReadAll(r.Body)
+	signature := SignWebhookPayload("secret", r.Header.Get(WebhookTimestampHeader), body)
+	if r.Header.Get(WebhookSignatureHeader) != signature || r.Header.Get(WebhookEventHeader) != dmodels.WebhookEventTransfer {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if rc.failures > 0 {
+		rc.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	rc.payloads = append(rc.payloads, string(body))
+}
+
+func TestDeliverWebhook(t *testing.T) {
+	receiver := &webhookReceiver{mu: &sync.Mutex{}, failures: 1}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	s := &ServiceFacade{webhookClient: newWebhookClient(config.Webhooks{AllowPrivateHosts: true})}
+	webhook := dmodels.Webhook{ID: 1, URL: server.URL, Secret: "secret", Active: true}
+	delivery := dmodels.WebhookDelivery{
+		WebhookID: 1,
+		EventID:   "event",
+		Event:     dmodels.WebhookEventTransfer,
+		Payload:   []byte(`{"type":"transfer"}`),
+		Status:    dmodels.WebhookDeliveryPending,
+	}
+
+	delivery = s.deliverWebhook(webhook, true, delivery)
+	if delivery.Status != dmodels.WebhookDeliveryPending || delivery.Attempts != 1 || delivery.ResponseCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected a failed attempt to be retried, got %+v", delivery)
+	}
+	if delay := time.Until(delivery.NextAttemptAt); delay <= 0 || delay > webhookFirstRetryDelay {
+		t.Errorf("expected the retry in %s, got %s", webhookFirstRetryDelay, delay)
+	}
+
+	delivery = s.deliverWebhook(webhook, true, delivery)
+	if delivery.Status != dmodels.WebhookDeliveryDelivered || delivery.Attempts != 2 || delivery.Error != "" {
+		t.Fatalf("expected the delivery to be delivered, got %+v", delivery)
+	}
+	if len(receiver.payloads) != 1 || receiver.payloads[0] != `{"type":"transfer"}` {
+		t.Errorf("unexpected payloads %v", receiver.payloads)
+	}
+
+	delivery.Status, delivery.Attempts = dmodels.WebhookDeliveryPending, webhookMaxAttempts-1
+	receiver.failures = 1
+	delivery = s.deliverWebhook(webhook, true, delivery)
+	if delivery.Status != dmodels.WebhookDeliveryFailed {
+		t.Errorf("expected the delivery to fail after %d attempts, got %+v", webhookMaxAttempts, delivery)
+	}
+
+	webhook.Active = false
+	delivery = s.deliverWebhook(webhook, true, dmodels.WebhookDelivery{Status: dmodels.WebhookDeliveryPending})
+	if delivery.Status != dmodels.WebhookDeliveryFailed || delivery.Attempts != 0 {
+		t.Errorf("expected the delivery to the inactive webhook to fail without attempts, got %+v", delivery)
+	}
+}
+
+func TestWebhookPrivateHosts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	client := newWebhookClient(config.Webhooks{})
+	_, err := sendWebhook(client, dmodels.Webhook{URL: server.URL}, dmodels.WebhookDelivery{}, time.Now())
+	if err == nil || !strings.Contains(err.Error(), errWebhookPrivateHost.Error()) {
+		t.Errorf("expected the private host to be refused, got %v", err)
+	}
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	tests := map[uint64]time.Duration{
+		1:  webhookFirstRetryDelay,
+		2:  webhookFirstRetryDelay * 2,
+		4:  webhookFirstRetryDelay * 8,
+		20: webhookMaxRetryDelay,
+	}
+	for attempts, expected := range tests {
+		if delay := webhookRetryDelay(attempts); delay != expected {
+			t.Errorf("attempts %d: expected %s, got %s", attempts, expected, delay)
+		}
+	}
+}
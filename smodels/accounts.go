@@ -1,11 +1,45 @@
 package smodels
 
-import "github.com/shopspring/decimal"
+import (
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/shopspring/decimal"
+)
 
 type Account struct {
 	Address     string          `json:"address"`
-	Balance     decimal.Decimal `json:"balance"`
-	Delegated   decimal.Decimal `json:"delegated"`
-	Unbonding   decimal.Decimal `json:"unbonding"`
-	StakeReward decimal.Decimal `json:"stake_reward"`
+	Balance     decimal.Decimal `json:"balance" unit:"atom"`
+	Delegated   decimal.Decimal `json:"delegated" unit:"atom"`
+	Unbonding   decimal.Decimal `json:"unbonding" unit:"atom"`
+	StakeReward decimal.Decimal `json:"stake_reward" unit:"atom"`
+
+	Label *dmodels.AddressLabel `json:"label,omitempty"`
+}
+
+type BalanceHistoryItem struct {
+	Time       dmodels.Time    `json:"time"`
+	Balance    decimal.Decimal `json:"balance" unit:"atom"`
+	Stake      decimal.Decimal `json:"stake" unit:"atom"`
+	Unbonding  decimal.Decimal `json:"unbonding" unit:"atom"`
+	Rewards    decimal.Decimal `json:"rewards" unit:"atom"`
+	Total      decimal.Decimal `json:"total" unit:"atom"`
+	Price      decimal.Decimal `json:"price"`
+	TotalValue decimal.Decimal `json:"total_value"`
+}
+
+type RichListItem struct {
+	Address   string                `json:"address"`
+	Balance   decimal.Decimal       `json:"balance" unit:"atom"`
+	Stake     decimal.Decimal       `json:"stake" unit:"atom"`
+	Unbonding decimal.Decimal       `json:"unbonding" unit:"atom"`
+	Total     decimal.Decimal       `json:"total" unit:"atom"`
+	Share     decimal.Decimal       `json:"share"`
+	Label     *dmodels.AddressLabel `json:"label,omitempty"`
+}
+
+type DistributionBucket struct {
+	From    decimal.Decimal `json:"from" unit:"atom"`
+	To      decimal.Decimal `json:"to" unit:"atom"`
+	Holders uint64          `json:"holders"`
+	Amount  decimal.Decimal `json:"amount" unit:"atom"`
+	Share   decimal.Decimal `json:"share"`
 }
@@ -0,0 +1,15 @@
+package smodels
+
+type (
+	AddressLabelParams struct {
+		Address  string `json:"address"`
+		Entity   string `json:"entity"`
+		Category string `json:"category"`
+		Name     string `json:"name"`
+	}
+	Entity struct {
+		Name      string `json:"name"`
+		Category  string `json:"category"`
+		Addresses uint64 `json:"addresses"`
+	}
+)
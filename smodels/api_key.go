@@ -0,0 +1,21 @@
+package smodels
+
+import (
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/shopspring/decimal"
+)
+
+type (
+	APIKeyParams struct {
+		Name       string          `json:"name"`
+		Rate       decimal.Decimal `json:"rate"`
+		Burst      uint64          `json:"burst"`
+		DailyQuota uint64          `json:"daily_quota"`
+		Active     bool            `json:"active"`
+	}
+	// IssuedAPIKey is returned once on issuance, only the hash of the key is stored
+	IssuedAPIKey struct {
+		dmodels.APIKey
+		Key string `json:"key"`
+	}
+)
@@ -3,7 +3,7 @@ package smodels
 import "github.com/shopspring/decimal"
 
 type Balance struct {
-	SelfDelegated  decimal.Decimal `json:"self_delegated"`
-	OtherDelegated decimal.Decimal `json:"other_delegated"`
-	Available      decimal.Decimal `json:"available"`
+	SelfDelegated  decimal.Decimal `json:"self_delegated" unit:"atom"`
+	OtherDelegated decimal.Decimal `json:"other_delegated" unit:"atom"`
+	Available      decimal.Decimal `json:"available" unit:"atom"`
 }
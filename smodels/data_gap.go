@@ -0,0 +1,12 @@
+package smodels
+
+import "github.com/everstake/cosmoscan-api/dmodels"
+
+// DataGap is a missing stat of the day ending at Time or a missing historical state of the hour starting at Time
+type DataGap struct {
+	Table     string       `json:"table"`
+	Title     string       `json:"title,omitempty"`
+	Time      dmodels.Time `json:"time"`
+	Derivable bool         `json:"derivable"`
+	Repaired  bool         `json:"repaired"`
+}
@@ -0,0 +1,34 @@
+package smodels
+
+import (
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/shopspring/decimal"
+)
+
+type (
+	Decentralization struct {
+		Validators DecentralizationMetrics `json:"validators"`
+		Entities   DecentralizationMetrics `json:"entities"`
+	}
+	DecentralizationMetrics struct {
+		Count      uint64          `json:"count"`
+		Nakamoto33 uint64          `json:"nakamoto_33"`
+		Nakamoto66 uint64          `json:"nakamoto_66"`
+		Gini       decimal.Decimal `json:"gini"`
+		HHI        decimal.Decimal `json:"hhi"`
+		Top10Share decimal.Decimal `json:"top_10_share"`
+		Top20Share decimal.Decimal `json:"top_20_share"`
+	}
+)
+
+// Values returns the metrics keyed by dmodels.DecentralizationMetrics names
+func (m DecentralizationMetrics) Values() map[string]decimal.Decimal {
+	return map[string]decimal.Decimal{
+		dmodels.DecentralizationNakamoto33: decimal.NewFromInt(int64(m.Nakamoto33)),
+		dmodels.DecentralizationNakamoto66: decimal.NewFromInt(int64(m.Nakamoto66)),
+		dmodels.DecentralizationGini:       m.Gini,
+		dmodels.DecentralizationHHI:        m.HHI,
+		dmodels.DecentralizationTop10Share: m.Top10Share,
+		dmodels.DecentralizationTop20Share: m.Top20Share,
+	}
+}
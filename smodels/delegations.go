@@ -0,0 +1,44 @@
+package smodels
+
+import (
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/shopspring/decimal"
+)
+
+type (
+	AccountDelegations struct {
+		Address       string              `json:"address"`
+		Delegated     decimal.Decimal     `json:"delegated" unit:"atom"`
+		Rewards       decimal.Decimal     `json:"rewards" unit:"atom"`
+		Unbonding     decimal.Decimal     `json:"unbonding" unit:"atom"`
+		Withdrawn     decimal.Decimal     `json:"withdrawn" unit:"atom"`
+		Delegations   []AccountDelegation `json:"delegations"`
+		Redelegations []Redelegation      `json:"redelegations"`
+	}
+	AccountDelegation struct {
+		Validator  string           `json:"validator"`
+		Title      string           `json:"title"`
+		Stake      decimal.Decimal  `json:"stake" unit:"atom"`
+		Rewards    decimal.Decimal  `json:"rewards" unit:"atom"`
+		Withdrawn  decimal.Decimal  `json:"withdrawn" unit:"atom"`
+		Commission decimal.Decimal  `json:"commission"`
+		Uptime     decimal.Decimal  `json:"uptime"`
+		Jailed     bool             `json:"jailed"`
+		Bonded     bool             `json:"bonded"`
+		Since      dmodels.Time     `json:"since"`
+		Unbondings []UnbondingEntry `json:"unbondings"`
+	}
+	UnbondingEntry struct {
+		CreationHeight uint64          `json:"creation_height"`
+		CompletionTime dmodels.Time    `json:"completion_time"`
+		InitialBalance decimal.Decimal `json:"initial_balance" unit:"atom"`
+		Balance        decimal.Decimal `json:"balance" unit:"atom"`
+	}
+	Redelegation struct {
+		TxHash       string          `json:"tx_hash"`
+		ValidatorSrc string          `json:"validator_src"`
+		ValidatorDst string          `json:"validator_dst"`
+		Amount       decimal.Decimal `json:"amount" unit:"atom"`
+		CreatedAt    dmodels.Time    `json:"created_at"`
+	}
+)
@@ -0,0 +1,39 @@
+package smodels
+
+import (
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/shopspring/decimal"
+)
+
+type (
+	// MetricInfo describes the metric of the registry, Unit is atom for amounts in atom
+	MetricInfo struct {
+		Name string   `json:"name"`
+		Aggs []string `json:"aggs"`
+		Unit string   `json:"unit,omitempty"`
+	}
+
+	// MetricSeries is the time series of the metric, Total is the value aggregated over the whole time range
+	MetricSeries struct {
+		Metric   string            `json:"metric"`
+		Agg      string            `json:"agg"`
+		Bucket   string            `json:"bucket"`
+		TZ       string            `json:"tz"`
+		Unit     string            `json:"unit,omitempty"`
+		From     dmodels.Time      `json:"from"`
+		To       dmodels.Time      `json:"to"`
+		Total    decimal.Decimal   `json:"total" unit:"value"`
+		Items    []AggItem         `json:"items"`
+		Previous *MetricComparison `json:"previous,omitempty"`
+	}
+
+	// MetricComparison is the series of the previous period of the same length, Change is the change
+	// of the total in percents, it is zero when the previous total is zero
+	MetricComparison struct {
+		From   dmodels.Time    `json:"from"`
+		To     dmodels.Time    `json:"to"`
+		Total  decimal.Decimal `json:"total" unit:"value"`
+		Change decimal.Decimal `json:"change"`
+		Items  []AggItem       `json:"items"`
+	}
+)
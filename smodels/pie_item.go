@@ -5,11 +5,11 @@ import "github.com/shopspring/decimal"
 type (
 	Pie struct {
 		Parts []PiePart       `json:"parts"`
-		Total decimal.Decimal `json:"total"`
+		Total decimal.Decimal `json:"total" unit:"atom"`
 	}
 	PiePart struct {
 		Label string          `json:"label"`
 		Title string          `json:"title"`
-		Value decimal.Decimal `json:"value"`
+		Value decimal.Decimal `json:"value" unit:"atom"`
 	}
 )
@@ -0,0 +1,64 @@
+package smodels
+
+import (
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/shopspring/decimal"
+)
+
+type (
+	ProposalDetail struct {
+		dmodels.Proposal
+		Timeline       []ProposalTimelineItem    `json:"timeline"`
+		Deposits       []dmodels.ProposalDeposit `json:"deposits"`
+		ValidatorVotes []ProposalValidatorVote   `json:"validator_votes"`
+		Overrides      []ProposalVoteOverride    `json:"overrides"`
+		// Tally is computed from the indexed delegations, NodeTally is reported by the node
+		Tally     ProposalTally `json:"tally"`
+		NodeTally ProposalTally `json:"node_tally"`
+	}
+
+	ProposalTimelineItem struct {
+		Event string       `json:"event"`
+		Time  dmodels.Time `json:"time"`
+	}
+
+	ProposalValidatorVote struct {
+		Title           string          `json:"title"`
+		OperatorAddress string          `json:"operator_address"`
+		Voter           string          `json:"voter"`
+		Voted           bool            `json:"voted"`
+		Option          string          `json:"option"`
+		TxHash          string          `json:"tx_hash"`
+		VotedAt         dmodels.Time    `json:"voted_at"`
+		Power           decimal.Decimal `json:"power" unit:"atom"`
+		EffectivePower  decimal.Decimal `json:"effective_power" unit:"atom"`
+	}
+
+	ProposalVoteOverride struct {
+		Delegator       string          `json:"delegator"`
+		Validator       string          `json:"validator"`
+		ValidatorTitle  string          `json:"validator_title"`
+		Option          string          `json:"option"`
+		ValidatorOption string          `json:"validator_option"`
+		Amount          decimal.Decimal `json:"amount" unit:"atom"`
+	}
+
+	ProposalTally struct {
+		Yes        decimal.Decimal `json:"yes" unit:"atom"`
+		Abstain    decimal.Decimal `json:"abstain" unit:"atom"`
+		No         decimal.Decimal `json:"no" unit:"atom"`
+		NoWithVeto decimal.Decimal `json:"no_with_veto" unit:"atom"`
+	}
+)
+
+// Add splits amount between the options according to the vote weights
+func (t *ProposalTally) Add(vote dmodels.ProposalVote, amount decimal.Decimal) {
+	t.Yes = t.Yes.Add(amount.Mul(vote.WeightYes))
+	t.Abstain = t.Abstain.Add(amount.Mul(vote.WeightAbstain))
+	t.No = t.No.Add(amount.Mul(vote.WeightNo))
+	t.NoWithVeto = t.NoWithVeto.Add(amount.Mul(vote.WeightNoWithVeto))
+}
+
+func (t ProposalTally) Total() decimal.Decimal {
+	return t.Yes.Add(t.Abstain).Add(t.No).Add(t.NoWithVeto)
+}
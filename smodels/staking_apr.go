@@ -0,0 +1,30 @@
+package smodels
+
+import "github.com/shopspring/decimal"
+
+type (
+	StakingParams struct {
+		Inflation    decimal.Decimal `json:"inflation"`
+		BondedRatio  decimal.Decimal `json:"bonded_ratio"`
+		CommunityTax decimal.Decimal `json:"community_tax"`
+		NetworkAPR   decimal.Decimal `json:"network_apr"`
+	}
+	ValidatorAPR struct {
+		Title             string          `json:"title"`
+		OperatorAddress   string          `json:"operator_address"`
+		Commission        decimal.Decimal `json:"commission"`
+		Uptime            decimal.Decimal `json:"uptime"`
+		ProjectedAPR      decimal.Decimal `json:"projected_apr"`
+		WithdrawnAPR      decimal.Decimal `json:"withdrawn_apr"`
+		WithdrawnGrossAPR decimal.Decimal `json:"withdrawn_gross_apr"`
+	}
+	RewardsEstimate struct {
+		StakingParams
+		ValidatorAPR
+		WithdrawnWindowDays uint64          `json:"withdrawn_window_days"`
+		Amount              decimal.Decimal `json:"amount" unit:"atom"`
+		DailyReward         decimal.Decimal `json:"daily_reward" unit:"atom"`
+		MonthlyReward       decimal.Decimal `json:"monthly_reward" unit:"atom"`
+		YearlyReward        decimal.Decimal `json:"yearly_reward" unit:"atom"`
+	}
+)
@@ -0,0 +1,43 @@
+package smodels
+
+import (
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/shopspring/decimal"
+)
+
+type (
+	Transfer struct {
+		TxHash    string                `json:"tx_hash"`
+		From      string                `json:"from"`
+		To        string                `json:"to"`
+		Amount    decimal.Decimal       `json:"amount"`
+		Currency  string                `json:"currency"`
+		CreatedAt dmodels.Time          `json:"created_at"`
+		FromLabel *dmodels.AddressLabel `json:"from_label,omitempty"`
+		ToLabel   *dmodels.AddressLabel `json:"to_label,omitempty"`
+	}
+	Counterparty struct {
+		Address   string                `json:"address"`
+		Sent      decimal.Decimal       `json:"sent" unit:"atom"`
+		Received  decimal.Decimal       `json:"received" unit:"atom"`
+		Transfers uint64                `json:"transfers"`
+		Label     *dmodels.AddressLabel `json:"label,omitempty"`
+	}
+	TraceGraph struct {
+		Nodes []TraceNode `json:"nodes"`
+		Edges []TraceEdge `json:"edges"`
+		// Truncated means that the graph has reached the max number of nodes
+		Truncated bool `json:"truncated"`
+	}
+	TraceNode struct {
+		Address string                `json:"address"`
+		Depth   uint64                `json:"depth"`
+		Label   *dmodels.AddressLabel `json:"label,omitempty"`
+	}
+	TraceEdge struct {
+		From      string          `json:"from"`
+		To        string          `json:"to"`
+		Amount    decimal.Decimal `json:"amount" unit:"atom"`
+		Transfers uint64          `json:"transfers"`
+	}
+)
@@ -10,7 +10,7 @@ type (
 	TxItem struct {
 		Hash      string          `json:"hash"`
 		Status    bool            `json:"status"`
-		Fee       decimal.Decimal `json:"fee"`
+		Fee       decimal.Decimal `json:"fee" unit:"atom"`
 		Height    uint64          `json:"height"`
 		Messages  uint64          `json:"messages"`
 		CreatedAt dmodels.Time    `json:"created_at"`
@@ -19,13 +19,15 @@ type (
 		Hash      string          `json:"hash"`
 		Type      string          `json:"type"`
 		Status    bool            `json:"status"`
-		Fee       decimal.Decimal `json:"fee"`
+		Fee       decimal.Decimal `json:"fee" unit:"atom"`
 		Height    uint64          `json:"height"`
 		GasUsed   uint64          `json:"gas_used"`
 		GasWanted uint64          `json:"gas_wanted"`
 		Memo      string          `json:"memo"`
 		CreatedAt dmodels.Time    `json:"created_at"`
 		Messages  []Message       `json:"messages"`
+		// Labels of the addresses found in the messages
+		Labels map[string]dmodels.AddressLabel `json:"labels,omitempty"`
 	}
 	Message struct {
 		Type string          `json:"type"`
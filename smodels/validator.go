@@ -9,11 +9,11 @@ type Validator struct {
 	AccAddress      string          `json:"acc_address"`
 	ConsAddress     string          `json:"cons_address"`
 	PercentPower    decimal.Decimal `json:"percent_power"`
-	Power           decimal.Decimal `json:"power"`
-	SelfStake       decimal.Decimal `json:"self_stake"`
+	Power           decimal.Decimal `json:"power" unit:"atom"`
+	SelfStake       decimal.Decimal `json:"self_stake" unit:"atom"`
 	Fee             decimal.Decimal `json:"fee"`
 	BlocksProposed  uint64          `json:"blocks_proposed"`
 	Delegators      uint64          `json:"delegators"`
-	Power24Change   decimal.Decimal `json:"power_24_change"`
+	Power24Change   decimal.Decimal `json:"power_24_change" unit:"atom"`
 	GovernanceVotes uint64          `json:"governance_votes"`
 }
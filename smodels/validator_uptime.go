@@ -0,0 +1,32 @@
+package smodels
+
+import "github.com/shopspring/decimal"
+
+type (
+	ValidatorUptime struct {
+		Window             uint64          `json:"window"`
+		FromHeight         uint64          `json:"from_height"`
+		ToHeight           uint64          `json:"to_height"`
+		Signed             uint64          `json:"signed"`
+		Missed             uint64          `json:"missed"`
+		Proposed           uint64          `json:"proposed"`
+		Uptime             decimal.Decimal `json:"uptime"`
+		MinSignedPerWindow decimal.Decimal `json:"min_signed_per_window"`
+		MissedBeforeJail   uint64          `json:"missed_before_jail"`
+		// Active is false for the jailed and unbonded validators, they have no uptime
+		Active bool `json:"active"`
+	}
+	SigningMapItem struct {
+		Height   uint64 `json:"height"`
+		Signed   bool   `json:"signed"`
+		Missed   bool   `json:"missed"`
+		Proposed bool   `json:"proposed"`
+	}
+	ValidatorUptimeItem struct {
+		Title           string          `json:"title"`
+		OperatorAddress string          `json:"operator_address"`
+		ConsAddress     string          `json:"cons_address"`
+		Missed          uint64          `json:"missed"`
+		Uptime          decimal.Decimal `json:"uptime"`
+	}
+)
@@ -0,0 +1,17 @@
+package smodels
+
+import (
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/shopspring/decimal"
+)
+
+type AccountVesting struct {
+	Address         string          `json:"address"`
+	Type            string          `json:"type"`
+	OriginalVesting decimal.Decimal `json:"original_vesting" unit:"atom"`
+	Locked          decimal.Decimal `json:"locked" unit:"atom"`
+	Unlocked        decimal.Decimal `json:"unlocked" unit:"atom"`
+	StartTime       dmodels.Time    `json:"start_time"`
+	EndTime         dmodels.Time    `json:"end_time"`
+	Time            dmodels.Time    `json:"time"`
+}
@@ -0,0 +1,53 @@
+package smodels
+
+import (
+	"github.com/everstake/cosmoscan-api/dmodels"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+type (
+	WebhookParams struct {
+		URL        string          `json:"url"`
+		Events     []string        `json:"events"`
+		Addresses  []string        `json:"addresses"`
+		Validators []string        `json:"validators"`
+		MinAmount  decimal.Decimal `json:"min_amount"`
+		Active     bool            `json:"active"`
+	}
+	// CreatedWebhook is returned once on creation, the secret signs the deliveries
+	CreatedWebhook struct {
+		dmodels.Webhook
+		Secret string `json:"secret"`
+	}
+
+	// the data of the webhook events
+
+	WebhookTransfer struct {
+		TxHash   string          `json:"tx_hash"`
+		From     string          `json:"from"`
+		To       string          `json:"to"`
+		Amount   decimal.Decimal `json:"amount"`
+		Currency string          `json:"currency"`
+	}
+	WebhookValidatorJail struct {
+		Validator       string    `json:"validator"`
+		Moniker         string    `json:"moniker"`
+		Jailed          bool      `json:"jailed"`
+		UnbondingHeight uint64    `json:"unbonding_height"`
+		UnbondingTime   time.Time `json:"unbonding_time"`
+	}
+	// WebhookMissedBlocks are the blocks missed by the validator (hex consensus address) in a batch of parsed blocks
+	WebhookMissedBlocks struct {
+		ConsAddress string   `json:"cons_address"`
+		Heights     []uint64 `json:"heights"`
+		Count       uint64   `json:"count"`
+	}
+	WebhookProposalStatus struct {
+		ProposalID     uint64       `json:"proposal_id"`
+		Title          string       `json:"title"`
+		PreviousStatus string       `json:"previous_status"`
+		Status         string       `json:"status"`
+		VotingEndTime  dmodels.Time `json:"voting_end_time"`
+	}
+)